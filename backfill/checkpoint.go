@@ -0,0 +1,68 @@
+package backfill
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Checkpoint 记录回填进度。签名按从新到旧分页，Before 是已完整处理的最旧签名，
+// 恢复时从它之前继续；Done 表示已到达 Until/MinSlot 或地址的第一笔交易
+type Checkpoint struct {
+	Address   string    `json:"address"`
+	Before    string    `json:"before,omitempty"`
+	Slot      uint64    `json:"slot,omitempty"`
+	Processed uint64    `json:"processed"`
+	Swaps     uint64    `json:"swaps"`
+	Done      bool      `json:"done"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// LoadCheckpoint 读取检查点文件，文件不存在时返回 nil, nil
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading checkpoint: %s", err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("error decoding checkpoint %s: %s", path, err)
+	}
+	return &checkpoint, nil
+}
+
+// Save 先写临时文件再重命名，进程中断时不会留下半个检查点
+func (c *Checkpoint) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding checkpoint: %s", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("error writing checkpoint: %s", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing checkpoint: %s", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing checkpoint: %s", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing checkpoint: %s", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing checkpoint: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,385 @@
+// Package backfill 通过 getSignaturesForAddress 分页回填某个程序或钱包的历史交易，
+// 并发拉取并解析交换后写入 Sink，每页（或每个 slot 范围）处理完成后保存可恢复的检查点。
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/sirupsen/logrus"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"golang.org/x/time/rate"
+)
+
+// Fetcher 是回填所需的 RPC 方法，*rpc.Client 满足该接口
+type Fetcher interface {
+	GetSignaturesForAddressWithOpts(ctx context.Context, account solana.PublicKey, opts *rpc.GetSignaturesForAddressOpts) ([]*rpc.TransactionSignature, error)
+	GetTransaction(ctx context.Context, txSig solana.Signature, opts *rpc.GetTransactionOpts) (*rpc.GetTransactionResult, error)
+}
+
+// Sink 接收解析出的交换。每页（设置了 FlushSlots 时为每个 slot 范围）写完后调用 Flush，
+// Flush 成功后才会保存检查点，因此中断后恢复时最后一页或范围可能被重复写入（至少一次）
+type Sink interface {
+	Write(swap solanaswapgo.BlockSwap) error
+	Flush() error
+}
+
+// Runner 回填单个地址的历史交易，签名从新到旧处理
+type Runner struct {
+	address solana.PublicKey
+	fetcher Fetcher
+	sink    Sink
+
+	// CheckpointPath 为检查点文件路径，为空时不保存进度
+	CheckpointPath string
+	// Until 为回填的终点签名（不含），MinSlot 为最小 slot，两者都为零值时回填到地址的第一笔交易
+	Until   solana.Signature
+	MinSlot uint64
+
+	Commitment rpc.CommitmentType
+	// PageSize 为每次 getSignaturesForAddress 的数量，最大 1000
+	PageSize int
+	// Concurrency 为并发 getTransaction 的数量
+	Concurrency int
+	// RateLimit 为所有 RPC 请求合计的每秒上限，0 表示不限速
+	RateLimit float64
+
+	// FetchAttempts 为每个 RPC 请求的最大尝试次数
+	FetchAttempts   int
+	FetchRetryDelay time.Duration
+
+	// FlushSlots 大于 0 时不再每页 Flush，而是在签名的 slot 跨过 FlushSlots 的整数倍时 Flush 并保存检查点，
+	// 检查点停在边界前的最后一个签名上。用于每次 Flush 都会结束一个文件的 Sink，
+	// 如按 SlotsPerFile 切分的 sink.Parquet，两者取相同的值时每个 slot 范围只写出一个文件
+	FlushSlots uint64
+
+	Log *logrus.Logger
+}
+
+// NewRunner 创建回填任务，address 可以是程序 ID（如 PUMP_FUN_PROGRAM_ID）或钱包地址
+func NewRunner(address solana.PublicKey, fetcher Fetcher, sink Sink) *Runner {
+	log := logrus.New()
+	log.SetFormatter(&logrus.TextFormatter{
+		TimestampFormat: "2006-01-02 15:04:05",
+		FullTimestamp:   true,
+	})
+
+	return &Runner{
+		address:         address,
+		fetcher:         fetcher,
+		sink:            sink,
+		Commitment:      rpc.CommitmentFinalized,
+		PageSize:        1000,
+		Concurrency:     8,
+		FetchAttempts:   3,
+		FetchRetryDelay: time.Second,
+		Log:             log,
+	}
+}
+
+// Run 从检查点（如有）继续回填，直到到达终点、ctx 取消或出现无法重试的错误。
+// 返回最新的检查点，出错时检查点停留在最后一个完整处理的页
+func (r *Runner) Run(ctx context.Context) (*Checkpoint, error) {
+	if r.fetcher == nil || r.sink == nil {
+		return nil, fmt.Errorf("backfill requires a fetcher and a sink")
+	}
+	pageSize := r.PageSize
+	if pageSize <= 0 || pageSize > 1000 {
+		return nil, fmt.Errorf("page size must be between 1 and 1000, got %d", pageSize)
+	}
+
+	checkpoint, err := r.loadCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint.Done {
+		r.Log.WithField("address", checkpoint.Address).Info("backfill already complete")
+		return checkpoint, nil
+	}
+
+	limiter := rate.NewLimiter(rate.Inf, 1)
+	if r.RateLimit > 0 {
+		limiter = rate.NewLimiter(rate.Limit(r.RateLimit), 1)
+	}
+
+	progress := &pageProgress{before: checkpoint.Before}
+	for {
+		opts := &rpc.GetSignaturesForAddressOpts{
+			Limit:      &pageSize,
+			Until:      r.Until,
+			Commitment: r.Commitment,
+		}
+		if progress.before != "" {
+			if opts.Before, err = solana.SignatureFromBase58(progress.before); err != nil {
+				return checkpoint, fmt.Errorf("error decoding checkpoint signature: %s", err)
+			}
+		}
+
+		var page []*rpc.TransactionSignature
+		err := r.retry(ctx, limiter, func() error {
+			var err error
+			page, err = r.fetcher.GetSignaturesForAddressWithOpts(ctx, r.address, opts)
+			return err
+		})
+		if err != nil {
+			return checkpoint, fmt.Errorf("error getting signatures: %s", err)
+		}
+
+		// 页内签名从新到旧，截断到 MinSlot
+		reachedEnd := len(page) == 0
+		for i, sig := range page {
+			if sig.Slot < r.MinSlot {
+				page, reachedEnd = page[:i], true
+				break
+			}
+		}
+
+		if len(page) > 0 {
+			results, failed, err := r.processPage(ctx, limiter, page)
+			if err != nil {
+				return checkpoint, err
+			}
+			if err := r.writePage(page, results, checkpoint, progress); err != nil {
+				return checkpoint, err
+			}
+
+			r.Log.WithFields(logrus.Fields{
+				"address":   checkpoint.Address,
+				"slot":      progress.slot,
+				"processed": checkpoint.Processed + progress.processed,
+				"swaps":     checkpoint.Swaps + progress.swaps,
+				"failed":    failed,
+			}).Info("backfill page complete")
+		}
+
+		if reachedEnd {
+			if err := r.commit(checkpoint, progress); err != nil {
+				return checkpoint, err
+			}
+			checkpoint.Done = true
+			if err := r.saveCheckpoint(checkpoint); err != nil {
+				return checkpoint, err
+			}
+			return checkpoint, nil
+		}
+	}
+}
+
+// pageProgress 记录已写入 sink 但尚未 Flush 的签名，before 为下一页的起点
+type pageProgress struct {
+	before    string
+	slot      uint64
+	processed uint64
+	swaps     uint64
+
+	// bucket 为最近一个签名所在的 FlushSlots 范围，started 表示已写入过签名
+	bucket  uint64
+	started bool
+}
+
+// writePage 按签名顺序将一页的结果写入 sink。FlushSlots 为 0 时在页尾提交；
+// 否则在签名进入新的 slot 范围之前提交，上一个范围的数据由此完整地落盘
+func (r *Runner) writePage(page []*rpc.TransactionSignature, results []*solanaswapgo.BlockSwap, checkpoint *Checkpoint, progress *pageProgress) error {
+	for i, sig := range page {
+		if r.FlushSlots > 0 {
+			bucket := sig.Slot / r.FlushSlots
+			if progress.started && bucket != progress.bucket {
+				if err := r.commit(checkpoint, progress); err != nil {
+					return err
+				}
+			}
+			progress.bucket, progress.started = bucket, true
+		}
+
+		if result := results[i]; result != nil {
+			if err := r.sink.Write(*result); err != nil {
+				return fmt.Errorf("error writing swap %s: %s", result.Signature, err)
+			}
+			progress.swaps++
+		}
+		progress.before = sig.Signature.String()
+		progress.slot = sig.Slot
+		progress.processed++
+	}
+
+	if r.FlushSlots == 0 {
+		return r.commit(checkpoint, progress)
+	}
+	return nil
+}
+
+// commit 调用 Flush 并将已写入的签名计入检查点后保存
+func (r *Runner) commit(checkpoint *Checkpoint, progress *pageProgress) error {
+	if progress.processed == 0 {
+		return nil
+	}
+	if err := r.sink.Flush(); err != nil {
+		return fmt.Errorf("error flushing sink: %s", err)
+	}
+
+	checkpoint.Before = progress.before
+	checkpoint.Slot = progress.slot
+	checkpoint.Processed += progress.processed
+	checkpoint.Swaps += progress.swaps
+	progress.processed, progress.swaps = 0, 0
+	return r.saveCheckpoint(checkpoint)
+}
+
+// processPage 并发拉取并解析一页交易，返回与 page 一一对应的结果（不含交换时为 nil）和解析失败（跳过）的交易数
+func (r *Runner) processPage(ctx context.Context, limiter *rate.Limiter, page []*rpc.TransactionSignature) ([]*solanaswapgo.BlockSwap, int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := r.Concurrency
+	if workers <= 0 {
+		workers = 1
+	}
+
+	results := make([]*solanaswapgo.BlockSwap, len(page))
+	failedParse := make([]bool, len(page))
+	fetchErrs := make([]error, len(page))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				// 每个下标只由一个 worker 写入，无需加锁
+				results[i], failedParse[i], fetchErrs[i] = r.processSignature(ctx, limiter, page[i])
+				if fetchErrs[i] != nil {
+					cancel()
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range page {
+		// 失败的交易不含交换，无需拉取
+		if page[i].Err != nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	// 第一个拉取错误会取消其余 worker，返回真正的原因而不是 context.Canceled
+	for _, err := range fetchErrs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return nil, 0, err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	failed := 0
+	for _, failedTx := range failedParse {
+		if failedTx {
+			failed++
+		}
+	}
+	return results, failed, nil
+}
+
+// processSignature 拉取并解析单笔交易。解析错误（返回 true）和已被节点清理的交易只记录不中断，
+// 其余拉取错误会中断本页
+func (r *Runner) processSignature(ctx context.Context, limiter *rate.Limiter, sig *rpc.TransactionSignature) (*solanaswapgo.BlockSwap, bool, error) {
+	maxTxVersion := uint64(0)
+	opts := &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     r.Commitment,
+		MaxSupportedTransactionVersion: &maxTxVersion,
+	}
+
+	var txResult *rpc.GetTransactionResult
+	err := r.retry(ctx, limiter, func() error {
+		var err error
+		txResult, err = r.fetcher.GetTransaction(ctx, sig.Signature, opts)
+		return err
+	})
+	if errors.Is(err, rpc.ErrNotFound) {
+		r.Log.WithField("signature", sig.Signature.String()).Warn("transaction not found, skipping")
+		return nil, false, nil
+	}
+	if ctx.Err() != nil {
+		return nil, false, ctx.Err()
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error getting tx %s: %s", sig.Signature, err)
+	}
+
+	swap, err := solanaswapgo.ParseTransactionResult(txResult)
+	if err != nil {
+		r.Log.WithField("signature", sig.Signature.String()).Warnf("error parsing transaction: %s", err)
+		return nil, true, nil
+	}
+	return swap, false, nil
+}
+
+// retry 在限速后调用 call，失败时最多重试 FetchAttempts 次
+func (r *Runner) retry(ctx context.Context, limiter *rate.Limiter, call func() error) error {
+	attempts := r.FetchAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(r.FetchRetryDelay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		lastErr = call()
+		if lastErr == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return lastErr
+}
+
+func (r *Runner) loadCheckpoint() (*Checkpoint, error) {
+	if r.CheckpointPath != "" {
+		checkpoint, err := LoadCheckpoint(r.CheckpointPath)
+		if err != nil {
+			return nil, err
+		}
+		if checkpoint != nil {
+			if checkpoint.Address != r.address.String() {
+				return nil, fmt.Errorf("checkpoint %s belongs to %s, not %s", r.CheckpointPath, checkpoint.Address, r.address)
+			}
+			return checkpoint, nil
+		}
+	}
+	return &Checkpoint{Address: r.address.String()}, nil
+}
+
+func (r *Runner) saveCheckpoint(checkpoint *Checkpoint) error {
+	checkpoint.UpdatedAt = time.Now().UTC()
+	if r.CheckpointPath == "" {
+		return nil
+	}
+	return checkpoint.Save(r.CheckpointPath)
+}
@@ -0,0 +1,146 @@
+// dexparse-server 以 HTTP 服务的形式提供交易解析。
+//
+// 用法:
+//
+//	dexparse-server --addr :8080 --rpc https://api.mainnet-beta.solana.com
+//
+// 端点:
+//
+//	GET  /v1/transactions/{signature}  通过 --rpc 拉取并解析交易
+//	POST /v1/transactions              解析请求体中的 getTransaction 结果（transaction + meta）
+//	POST /v1/blocks?slot=N             解析请求体中的 getBlock 结果
+//	GET  /healthz, /readyz, /metrics
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/sirupsen/logrus"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/metrics"
+	"github.com/zzispp/solana-dex-parse/server"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stderr))
+}
+
+// run 启动服务直到 ctx 取消，参数错误返回 2，服务异常退出返回 1
+func run(ctx context.Context, args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("dexparse-server", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: dexparse-server [flags]\n\n")
+		flags.PrintDefaults()
+	}
+
+	var (
+		addr          string
+		rpcURL        string
+		commitment    string
+		maxBody       int64
+		maxConcurrent int
+		timeout       time.Duration
+		blockWorkers  int
+		logLevel      string
+	)
+	flags.StringVar(&addr, "addr", ":8080", "listen address")
+	flags.StringVar(&rpcURL, "rpc", "", "RPC URL used to fetch transactions by signature")
+	flags.StringVar(&commitment, "commitment", string(rpc.CommitmentConfirmed), "commitment for getTransaction (confirmed or finalized)")
+	flags.Int64Var(&maxBody, "max-body", 8<<20, "maximum request body size in bytes")
+	flags.IntVar(&maxConcurrent, "max-concurrent", 64, "maximum parse requests handled at once")
+	flags.DurationVar(&timeout, "timeout", 30*time.Second, "per-request timeout")
+	flags.IntVar(&blockWorkers, "block-workers", 4, "concurrent transactions per block request")
+	flags.StringVar(&logLevel, "log-level", "info", "log level")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+	switch rpc.CommitmentType(commitment) {
+	case rpc.CommitmentConfirmed, rpc.CommitmentFinalized:
+	default:
+		fmt.Fprintf(stderr, "unsupported commitment %q\n", commitment)
+		return 2
+	}
+	level, err := logrus.ParseLevel(logLevel)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid log level: %s\n", err)
+		return 2
+	}
+
+	log := logrus.New()
+	log.SetOutput(stderr)
+	log.SetLevel(level)
+	log.SetFormatter(&logrus.TextFormatter{
+		TimestampFormat: "2006-01-02 15:04:05",
+		FullTimestamp:   true,
+	})
+
+	// /metrics 同时输出服务的请求计数、解析器的统计以及进程指标
+	collector := metrics.NewCollector()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector, collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	solanaswapgo.SetObserver(collector)
+	defer solanaswapgo.SetObserver(nil)
+
+	config := server.Config{
+		Commitment:     rpc.CommitmentType(commitment),
+		MaxBodyBytes:   maxBody,
+		MaxConcurrent:  maxConcurrent,
+		RequestTimeout: timeout,
+		BlockWorkers:   blockWorkers,
+		Registry:       registry,
+		Log:            log,
+	}
+	if rpcURL != "" {
+		config.RPC = rpc.New(rpcURL)
+	}
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           server.New(config),
+		ReadHeaderTimeout: 10 * time.Second,
+		// 写超时在请求超时之外留出编码响应的余量
+		WriteTimeout: timeout + 10*time.Second,
+		IdleTimeout:  2 * time.Minute,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		log.WithField("addr", addr).Info("listening")
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		log.Errorf("error serving: %s", err)
+		return 1
+	case <-ctx.Done():
+	}
+
+	log.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Errorf("error shutting down: %s", err)
+		return 1
+	}
+	return 0
+}
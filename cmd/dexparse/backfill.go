@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/sirupsen/logrus"
+	"github.com/zzispp/solana-dex-parse/backfill"
+	"github.com/zzispp/solana-dex-parse/sink"
+)
+
+// runBackfill 实现 backfill 子命令：回填一个地址的历史交换并写入 --out
+func runBackfill(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("dexparse backfill", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: dexparse backfill --rpc URL --address PUBKEY --out FILE [flags]\n\n")
+		flags.PrintDefaults()
+	}
+
+	rpcURL := flags.String("rpc", "", "RPC URL")
+	addressStr := flags.String("address", "", "program ID or wallet to backfill")
+	out := flags.String("out", "", "output file: .jsonl, .csv, .db/.sqlite/.sqlite3 or .parquet")
+	checkpointPath := flags.String("checkpoint", "", "checkpoint file (default <out>.checkpoint.json)")
+	until := flags.String("until", "", "stop before this signature")
+	minSlot := flags.Uint64("min-slot", 0, "stop at transactions older than this slot")
+	commitment := flags.String("commitment", string(rpc.CommitmentFinalized), "commitment (confirmed or finalized)")
+	pageSize := flags.Int("page-size", 1000, "signatures per getSignaturesForAddress page (max 1000)")
+	concurrency := flags.Int("concurrency", 8, "concurrent getTransaction requests")
+	rateLimit := flags.Float64("rate", 0, "maximum RPC requests per second, 0 for unlimited")
+	slotsPerFile := flags.Uint64("slots-per-file", 100000, "parquet: start a new file every N slots and checkpoint at these boundaries, 0 for a single file checkpointed only on completion")
+	rowGroupSize := flags.Int64("row-group-size", 65536, "parquet: maximum rows per row group")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *rpcURL == "" || *addressStr == "" || *out == "" || flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+
+	address, err := solana.PublicKeyFromBase58(*addressStr)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid address: %s\n", err)
+		return 2
+	}
+	switch rpc.CommitmentType(*commitment) {
+	case rpc.CommitmentConfirmed, rpc.CommitmentFinalized:
+	default:
+		fmt.Fprintf(stderr, "invalid commitment %q\n", *commitment)
+		return 2
+	}
+
+	var output sink.Sink
+	parquetOutput := strings.EqualFold(filepath.Ext(*out), ".parquet")
+	if parquetOutput {
+		output, err = sink.NewParquet(*out, sink.ParquetOptions{SlotsPerFile: *slotsPerFile, RowGroupSize: *rowGroupSize})
+	} else {
+		output, err = sink.Open(*out)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	runner := backfill.NewRunner(address, rpc.New(*rpcURL), output)
+	runner.Log.SetOutput(stderr)
+	runner.Log.SetLevel(logrus.InfoLevel)
+	runner.CheckpointPath = *checkpointPath
+	if runner.CheckpointPath == "" {
+		runner.CheckpointPath = *out + ".checkpoint.json"
+	}
+	if *until != "" {
+		if runner.Until, err = solana.SignatureFromBase58(*until); err != nil {
+			output.Close()
+			fmt.Fprintf(stderr, "invalid --until signature: %s\n", err)
+			return 2
+		}
+	}
+	runner.MinSlot = *minSlot
+	runner.Commitment = rpc.CommitmentType(*commitment)
+	runner.PageSize = *pageSize
+	runner.Concurrency = *concurrency
+	runner.RateLimit = *rateLimit
+	if parquetOutput {
+		// 每次 Flush 都会结束 Parquet 文件，只在文件的 slot 范围写完时 Flush；
+		// 不切分时所有 slot 落在同一范围，到达终点时才 Flush
+		runner.FlushSlots = *slotsPerFile
+		if runner.FlushSlots == 0 {
+			runner.FlushSlots = math.MaxUint64
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	checkpoint, err := runner.Run(ctx)
+	if closeErr := output.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("error closing %s: %s", *out, closeErr)
+	}
+	if checkpoint != nil {
+		fmt.Fprintf(stdout, "processed %d signatures, wrote %d swaps, done=%v\n", checkpoint.Processed, checkpoint.Swaps, checkpoint.Done)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
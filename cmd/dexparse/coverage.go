@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/fixture"
+)
+
+// runCoverage 实现 coverage 子命令：统计一批交易中没有解码器处理、但余额发生变化的程序
+func runCoverage(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("dexparse coverage", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: dexparse coverage [flags] [signature | file.json | file.jsonl | dir | -]...\n\n")
+		flags.PrintDefaults()
+	}
+
+	var opts options
+	flags.StringVar(&opts.rpcURL, "rpc", "", "RPC URL used to fetch transactions by signature")
+	flags.StringVar(&opts.commitment, "commitment", string(rpc.CommitmentConfirmed), "commitment for getTransaction (confirmed or finalized)")
+	flags.IntVar(&opts.maxTxVersion, "max-tx-version", 0, "maxSupportedTransactionVersion for getTransaction, -1 to omit")
+	format := flags.String("format", "text", "report format: text or json")
+	top := flags.Int("top", 20, "number of programs in the text report, 0 for all")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "invalid format %q\n", *format)
+		return 2
+	}
+
+	inputs, err := expandDirs(flags.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+
+	analyzer := solanaswapgo.NewCoverageAnalyzer()
+	source := newSource(opts, stdin, stderr)
+	source.onParse = analyzer.Add
+
+	failed := false
+	for _, input := range inputs {
+		err := source.each(context.Background(), input, func(rec record) error {
+			// 没有交换是本命令要统计的情况，不算失败
+			if rec.Error != "" && rec.Error != "no swaps found" {
+				fmt.Fprintf(stderr, "%s: %s\n", rec.Input, rec.Error)
+				failed = true
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", input, err)
+			failed = true
+		}
+	}
+
+	report := analyzer.Report()
+	if *format == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = report.WriteText(stdout, *top)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// expandDirs 将目录参数展开为其中的 .json 和 .jsonl 文件（跳过 golden 文件），其余参数保持不变
+func expandDirs(inputs []string) ([]string, error) {
+	var expanded []string
+	for _, input := range inputs {
+		info, err := os.Stat(input)
+		if err != nil || !info.IsDir() {
+			expanded = append(expanded, input)
+			continue
+		}
+
+		err = filepath.WalkDir(input, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := filepath.Ext(path)
+			if !entry.IsDir() && (ext == ".json" || ext == ".jsonl") && !strings.HasSuffix(path, fixture.GoldenSuffix) {
+				expanded = append(expanded, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %s", input, err)
+		}
+	}
+	return expanded, nil
+}
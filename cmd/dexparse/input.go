@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/sirupsen/logrus"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+// maxLineSize 是标准输入单行的最大长度，包含大量内部指令的交易 JSON 可能超过数 MB
+const maxLineSize = 64 << 20
+
+// record 是一笔输入交易的输出结果，Input 标明来源（签名、文件或 文件:行号）
+type record struct {
+	Input     string                  `json:"input"`
+	Signature string                  `json:"signature,omitempty"`
+	Slot      uint64                  `json:"slot,omitempty"`
+	SwapInfo  *solanaswapgo.SwapInfo  `json:"swapInfo,omitempty"`
+	Swaps     []solanaswapgo.SwapData `json:"swaps,omitempty"`
+	Error     string                  `json:"error,omitempty"`
+}
+
+type source struct {
+	opts   options
+	stdin  io.Reader
+	stderr io.Writer
+	client *rpc.Client
+	// log 是所有交易共用的解析器日志，--trace 时输出到 stderr，否则丢弃
+	log *logrus.Logger
+	// onParse 在每笔交易解析之后调用，coverage 子命令用它收集未覆盖的交易
+	onParse func(parser *solanaswapgo.Parser, swaps []solanaswapgo.SwapData)
+}
+
+func newSource(opts options, stdin io.Reader, stderr io.Writer) *source {
+	s := &source{
+		opts:   opts,
+		stdin:  stdin,
+		stderr: stderr,
+		log:    solanaswapgo.DiscardLogger,
+	}
+	if opts.trace {
+		s.log = logrus.New()
+		s.log.SetOutput(stderr)
+		s.log.SetLevel(logrus.TraceLevel)
+	}
+	if opts.rpcURL != "" {
+		s.client = rpc.New(opts.rpcURL)
+	}
+	return s
+}
+
+// each 解析一个命令行参数并对每笔交易调用 fn；参数本身无法识别时返回错误
+func (s *source) each(ctx context.Context, input string, fn func(record) error) error {
+	if input == "-" {
+		return s.eachLine(ctx, "stdin", s.stdin, fn)
+	}
+
+	if _, err := os.Stat(input); err == nil {
+		if filepath.Ext(input) == ".jsonl" {
+			file, err := os.Open(input)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			return s.eachLine(ctx, input, file, fn)
+		}
+
+		raw, err := os.ReadFile(input)
+		if err != nil {
+			return err
+		}
+		return fn(s.fromJSON(input, raw))
+	}
+
+	signature, err := solana.SignatureFromBase58(input)
+	if err != nil {
+		return fmt.Errorf("not a file or transaction signature")
+	}
+	return fn(s.fromSignature(ctx, input, signature))
+}
+
+// eachLine 逐行读取 JSONL，以 { 开头的行按交易 JSON 解析，其余非空行按签名处理
+func (s *source) eachLine(ctx context.Context, name string, reader io.Reader, fn func(record) error) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 1<<20), maxLineSize)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		input := fmt.Sprintf("%s:%d", name, lineNumber)
+		var rec record
+		if line[0] == '{' {
+			rec = s.fromJSON(input, line)
+		} else if signature, err := solana.SignatureFromBase58(string(line)); err == nil {
+			rec = s.fromSignature(ctx, input, signature)
+		} else {
+			rec = record{Input: input, Error: "line is neither a transaction JSON object nor a signature"}
+		}
+
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// fromJSON 解析 getTransaction 结果，也接受完整的 JSON-RPC 响应
+func (s *source) fromJSON(input string, raw []byte) record {
+	rec := record{Input: input}
+
+	var envelope struct {
+		Result json.RawMessage `json:"result"`
+		Slot   uint64          `json:"slot"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		rec.Error = fmt.Sprintf("error decoding JSON: %s", err)
+		return rec
+	}
+	if len(envelope.Result) > 0 {
+		if string(envelope.Result) == "null" {
+			rec.Error = "transaction not found"
+			return rec
+		}
+		raw = envelope.Result
+		if err := json.Unmarshal(raw, &envelope); err != nil {
+			rec.Error = fmt.Sprintf("error decoding result: %s", err)
+			return rec
+		}
+	}
+	rec.Slot = envelope.Slot
+
+	parser, err := solanaswapgo.NewTransactionParserFromResultJSON(raw)
+	if err != nil {
+		rec.Error = err.Error()
+		return rec
+	}
+	return s.parse(rec, parser)
+}
+
+func (s *source) fromSignature(ctx context.Context, input string, signature solana.Signature) record {
+	rec := record{Input: input, Signature: signature.String()}
+	if s.client == nil {
+		rec.Error = "--rpc is required to fetch transactions by signature"
+		return rec
+	}
+
+	opts := &rpc.GetTransactionOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: rpc.CommitmentType(s.opts.commitment),
+	}
+	if s.opts.maxTxVersion >= 0 {
+		maxTxVersion := uint64(s.opts.maxTxVersion)
+		opts.MaxSupportedTransactionVersion = &maxTxVersion
+	}
+
+	tx, err := s.client.GetTransaction(ctx, signature, opts)
+	if err != nil {
+		rec.Error = fmt.Sprintf("error getting tx: %s", err)
+		return rec
+	}
+	rec.Slot = tx.Slot
+
+	parser, err := solanaswapgo.NewTransactionParser(tx)
+	if err != nil {
+		rec.Error = err.Error()
+		return rec
+	}
+	return s.parse(rec, parser)
+}
+
+func (s *source) parse(rec record, parser *solanaswapgo.Parser) record {
+	parser.Log = s.log
+
+	swaps, err := parser.ParseTransaction()
+	if err != nil {
+		rec.Error = fmt.Sprintf("error parsing transaction: %s", err)
+		return rec
+	}
+	if s.onParse != nil {
+		s.onParse(parser, swaps)
+	}
+	if len(swaps) == 0 {
+		rec.Error = "no swaps found"
+		return rec
+	}
+	if s.opts.legs {
+		rec.Swaps = swaps
+	}
+
+	swapInfo, err := parser.ProcessSwapData(swaps)
+	if err != nil {
+		rec.Error = fmt.Sprintf("error processing swap data: %s", err)
+		return rec
+	}
+	if blockTime := parser.GetBlockTime(); blockTime != nil {
+		swapInfo.Timestamp = *blockTime
+	}
+	rec.SwapInfo = swapInfo
+
+	if rec.Signature == "" && len(swapInfo.Signatures) > 0 {
+		rec.Signature = swapInfo.Signatures[0].String()
+	}
+	return rec
+}
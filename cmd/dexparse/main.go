@@ -0,0 +1,140 @@
+// dexparse 解析 Solana 交易中的 DEX 交换并输出 SwapInfo。
+//
+// 用法:
+//
+//	dexparse --rpc https://api.mainnet-beta.solana.com <signature>...
+//	dexparse tx1.json tx2.json
+//	cat txs.jsonl | dexparse --format jsonl
+//	dexparse record --rpc https://api.mainnet-beta.solana.com --protocol pumpfun <signature>...
+//	dexparse backfill --rpc https://api.mainnet-beta.solana.com --address <program-or-wallet> --out swaps.db
+//	dexparse coverage --top 20 txs/
+//
+// 参数可以是交易签名（需要 --rpc）或 getTransaction 结果的 JSON 文件；
+// 没有参数或参数为 "-" 时从标准输入逐行读取 JSON（每行一笔交易，也可以是签名）。
+// record 子命令将交易录制到测试语料并生成 golden 文件；
+// backfill 子命令回填一个地址的历史交换并写入 JSONL、CSV 或 SQLite，中断后可从检查点继续；
+// coverage 子命令统计没有解析出交换但余额发生变化的交易中，哪些程序还没有解码器。
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+type options struct {
+	rpcURL       string
+	commitment   string
+	maxTxVersion int
+	format       string
+	legs         bool
+	trace        bool
+	// launchLabPlatforms 为 Raydium LaunchLab 平台列表的 JSON 文件，为空时使用内置列表
+	launchLabPlatforms string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run 执行命令并返回退出码：参数错误为 2，任意一笔交易处理失败为 1
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "record":
+			return runRecord(args[1:], stdout, stderr)
+		case "backfill":
+			return runBackfill(args[1:], stdout, stderr)
+		case "coverage":
+			return runCoverage(args[1:], stdin, stdout, stderr)
+		}
+	}
+
+	flags := flag.NewFlagSet("dexparse", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: dexparse [flags] [signature | file.json | -]...\n\n")
+		flags.PrintDefaults()
+	}
+
+	var opts options
+	flags.StringVar(&opts.rpcURL, "rpc", "", "RPC URL used to fetch transactions by signature")
+	flags.StringVar(&opts.commitment, "commitment", string(rpc.CommitmentConfirmed), "commitment for getTransaction (confirmed or finalized)")
+	flags.IntVar(&opts.maxTxVersion, "max-tx-version", 0, "maxSupportedTransactionVersion for getTransaction, -1 to omit")
+	flags.StringVar(&opts.format, "format", "json", "output format: json, jsonl or table")
+	flags.BoolVar(&opts.legs, "legs", false, "include every parsed swap leg in the output")
+	flags.BoolVar(&opts.trace, "trace", false, "print parser logs to stderr")
+	flags.StringVar(&opts.launchLabPlatforms, "launchlab-platforms", "", "JSON file of Raydium LaunchLab platforms (name, platformConfig, feeRecipient)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if opts.launchLabPlatforms != "" {
+		if err := loadLaunchLabPlatforms(opts.launchLabPlatforms); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+
+	switch rpc.CommitmentType(opts.commitment) {
+	case rpc.CommitmentConfirmed, rpc.CommitmentFinalized:
+	default:
+		fmt.Fprintf(stderr, "invalid commitment %q\n", opts.commitment)
+		return 2
+	}
+
+	writer, err := newRecordWriter(opts.format, stdout)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	inputs := flags.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+
+	source := newSource(opts, stdin, stderr)
+	failed := false
+	for _, input := range inputs {
+		err := source.each(context.Background(), input, func(rec record) error {
+			if rec.Error != "" {
+				failed = true
+			}
+			return writer.Write(rec)
+		})
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", input, err)
+			failed = true
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// loadLaunchLabPlatforms 读取 LaunchLab 平台列表并替换内置列表
+func loadLaunchLabPlatforms(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	platforms, err := solanaswapgo.ReadLaunchLabPlatforms(file)
+	if err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+	solanaswapgo.SetLaunchLabPlatforms(platforms)
+	return nil
+}
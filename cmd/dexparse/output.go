@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+// recordWriter 按输出格式写出结果
+type recordWriter interface {
+	Write(rec record) error
+	Close() error
+}
+
+func newRecordWriter(format string, out io.Writer) (recordWriter, error) {
+	switch format {
+	case "json":
+		return &jsonWriter{out: out}, nil
+	case "jsonl":
+		return &jsonlWriter{encoder: json.NewEncoder(out)}, nil
+	case "table":
+		writer := &tableWriter{tw: tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)}
+		fmt.Fprintln(writer.tw, "SIGNATURE\tSLOT\tAMMS\tIN\tIN MINT\tOUT\tOUT MINT\tERROR")
+		return writer, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (expected json, jsonl or table)", format)
+	}
+}
+
+// jsonWriter 在结束时输出带缩进的 JSON 数组
+type jsonWriter struct {
+	out     io.Writer
+	records []record
+}
+
+func (w *jsonWriter) Write(rec record) error {
+	w.records = append(w.records, rec)
+	return nil
+}
+
+func (w *jsonWriter) Close() error {
+	if w.records == nil {
+		w.records = []record{}
+	}
+	data, err := json.MarshalIndent(w.records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding output: %s", err)
+	}
+	_, err = fmt.Fprintln(w.out, string(data))
+	return err
+}
+
+// jsonlWriter 每笔交易输出一行，便于与其他工具串联
+type jsonlWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *jsonlWriter) Write(rec record) error {
+	return w.encoder.Encode(rec)
+}
+
+func (w *jsonlWriter) Close() error {
+	return nil
+}
+
+type tableWriter struct {
+	tw *tabwriter.Writer
+}
+
+func (w *tableWriter) Write(rec record) error {
+	signature := rec.Signature
+	if signature == "" {
+		signature = rec.Input
+	}
+
+	if rec.SwapInfo == nil {
+		_, err := fmt.Fprintf(w.tw, "%s\t%d\t-\t-\t-\t-\t-\t%s\n", shortKey(signature), rec.Slot, rec.Error)
+		return err
+	}
+
+	info := rec.SwapInfo
+	_, err := fmt.Fprintf(w.tw, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		shortKey(signature),
+		rec.Slot,
+		strings.Join(info.AMMs, ","),
+		solanaswapgo.FormatAmount(info.TokenInAmount, info.TokenInDecimals),
+		shortKey(info.TokenInMint.String()),
+		solanaswapgo.FormatAmount(info.TokenOutAmount, info.TokenOutDecimals),
+		shortKey(info.TokenOutMint.String()),
+		rec.Error,
+	)
+	return err
+}
+
+func (w *tableWriter) Close() error {
+	return w.tw.Flush()
+}
+
+// shortKey 截断公钥或签名用于表格输出
+func shortKey(key string) string {
+	if len(key) <= 12 {
+		return key
+	}
+	return key[:5] + "…" + key[len(key)-5:]
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/zzispp/solana-dex-parse/fixture"
+)
+
+// runRecord 实现 record 子命令：录制交易到测试语料并生成 golden 文件
+func runRecord(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("dexparse record", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: dexparse record --rpc URL --protocol NAME [flags] signature...\n\n")
+		flags.PrintDefaults()
+	}
+
+	rpcURL := flags.String("rpc", "", "RPC URL used to fetch transactions")
+	protocol := flags.String("protocol", "", "protocol directory under --dir, e.g. pumpfun or raydium")
+	dir := flags.String("dir", "tests/testdata/mainnet", "corpus directory")
+	commitment := flags.String("commitment", string(rpc.CommitmentConfirmed), "commitment for getTransaction (confirmed or finalized)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *rpcURL == "" || *protocol == "" || flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	client := rpc.New(*rpcURL)
+	failed := false
+	for _, input := range flags.Args() {
+		signature, err := solana.SignatureFromBase58(input)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: invalid signature: %s\n", input, err)
+			failed = true
+			continue
+		}
+
+		path, err := fixture.Record(context.Background(), client, *dir, *protocol, signature, rpc.CommitmentType(*commitment))
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", input, err)
+			failed = true
+			continue
+		}
+		fmt.Fprintln(stdout, path)
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
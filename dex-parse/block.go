@@ -0,0 +1,231 @@
+package solanaswapgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// BlockSwap 表示区块中一笔包含交换的交易的解析结果
+type BlockSwap struct {
+	Slot             uint64
+	TransactionIndex int
+	Signature        solana.Signature
+	Fee              uint64 // 交易的网络手续费（lamports）
+	Swaps            []SwapData
+	SwapInfo         *SwapInfo
+}
+
+// BlockTransactionError 记录区块中单笔交易的解码错误，不影响其他交易的解析
+type BlockTransactionError struct {
+	Slot             uint64
+	TransactionIndex int
+	Err              error
+}
+
+func (e *BlockTransactionError) Error() string {
+	return fmt.Sprintf("slot %d transaction %d: %s", e.Slot, e.TransactionIndex, e.Err)
+}
+
+func (e *BlockTransactionError) Unwrap() error {
+	return e.Err
+}
+
+// ParseBlock 按区块顺序解析 getBlock 结果中的全部交易。
+// getBlock 的响应中不包含区块自身的 slot，需要由调用方传入。
+// 失败的交易和不含交换的交易会被跳过；单笔交易解码失败时，其余结果照常返回，
+// 错误以 BlockTransactionError 的形式合并到返回的 error 中。
+func ParseBlock(slot uint64, block *rpc.GetBlockResult) ([]BlockSwap, error) {
+	if block == nil {
+		return nil, fmt.Errorf("block is nil")
+	}
+
+	var (
+		results []BlockSwap
+		errs    []error
+	)
+	for i := range block.Transactions {
+		result, err := parseBlockTransaction(slot, block, i)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if result != nil {
+			results = append(results, *result)
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// ParseBlockConcurrent 与 ParseBlock 相同，但使用 workers 个 goroutine 并发解析。
+// 输出顺序与区块中的交易顺序一致；ctx 取消时停止分发并返回 ctx.Err()。
+func ParseBlockConcurrent(ctx context.Context, slot uint64, block *rpc.GetBlockResult, workers int) ([]BlockSwap, error) {
+	if block == nil {
+		return nil, fmt.Errorf("block is nil")
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+
+	txCount := len(block.Transactions)
+	results := make([]*BlockSwap, txCount)
+	errs := make([]error, txCount)
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				// 每个下标只由一个 worker 写入，无需加锁
+				results[i], errs[i] = parseBlockTransaction(slot, block, i)
+			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < txCount; i++ {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	swaps := make([]BlockSwap, 0, txCount)
+	for _, result := range results {
+		if result != nil {
+			swaps = append(swaps, *result)
+		}
+	}
+
+	return swaps, errors.Join(errs...)
+}
+
+// parseBlockTransaction 解析区块中下标为 index 的交易，不含交换时返回 nil, nil
+func parseBlockTransaction(slot uint64, block *rpc.GetBlockResult, index int) (*BlockSwap, error) {
+	txWithMeta := block.Transactions[index]
+	if txWithMeta.Meta == nil || txWithMeta.Meta.Err != nil {
+		return nil, nil
+	}
+
+	tx, err := decodeBlockTransaction(txWithMeta)
+	if err != nil {
+		return nil, &BlockTransactionError{Slot: slot, TransactionIndex: index, Err: err}
+	}
+
+	blockTime := txWithMeta.BlockTime
+	if blockTime == nil {
+		blockTime = block.BlockTime
+	}
+	txResult := &rpc.GetTransactionResult{
+		Slot:      slot,
+		BlockTime: blockTime,
+		Meta:      txWithMeta.Meta,
+		Version:   txWithMeta.Version,
+	}
+
+	result, err := parseSwapTransaction(txResult, tx)
+	if err != nil {
+		return nil, &BlockTransactionError{Slot: slot, TransactionIndex: index, Err: err}
+	}
+	if result != nil {
+		result.TransactionIndex = index
+	}
+	return result, nil
+}
+
+// ParseTransactionResult 解析单笔 getTransaction 结果，返回与 ParseBlock 相同结构的 BlockSwap。
+// 失败的交易和不含交换的交易返回 nil, nil；交易在区块中的下标未知，TransactionIndex 为 -1。
+func ParseTransactionResult(txResult *rpc.GetTransactionResult) (*BlockSwap, error) {
+	if txResult == nil || txResult.Transaction == nil {
+		return nil, fmt.Errorf("transaction is nil")
+	}
+
+	tx, err := txResult.Transaction.GetTransaction()
+	if err != nil {
+		return nil, fmt.Errorf("error decoding transaction: %s", err)
+	}
+	return ParseDecodedTransactionResult(txResult, tx)
+}
+
+// ParseDecodedTransactionResult 与 ParseTransactionResult 相同，交易已由调用方解码，
+// 如 DecodeTransactionResultJSON 的结果；txResult.Transaction 不会被读取
+func ParseDecodedTransactionResult(txResult *rpc.GetTransactionResult, tx *solana.Transaction) (*BlockSwap, error) {
+	if txResult == nil || tx == nil {
+		return nil, fmt.Errorf("transaction is nil")
+	}
+	if txResult.Meta == nil || txResult.Meta.Err != nil {
+		return nil, nil
+	}
+
+	result, err := parseSwapTransaction(txResult, tx)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		result.TransactionIndex = -1
+	}
+	return result, nil
+}
+
+// parseSwapTransaction 解析已解码的交易，不含交换时返回 nil, nil
+func parseSwapTransaction(txResult *rpc.GetTransactionResult, tx *solana.Transaction) (*BlockSwap, error) {
+	// 版本化交易的查找表地址由 newParser 从 meta.LoadedAddresses 中补全
+	parser, err := NewTransactionParserFromTransactionResult(txResult, tx, txResult.Meta)
+	if err != nil {
+		return nil, err
+	}
+	// 区块中大部分交易与 DEX 无关，避免逐笔输出日志
+	parser.Log = DiscardLogger
+
+	swaps, err := parser.ParseTransaction()
+	if err != nil {
+		return nil, err
+	}
+	if len(swaps) == 0 {
+		return nil, nil
+	}
+
+	result := &BlockSwap{
+		Slot:  txResult.Slot,
+		Fee:   txResult.Meta.Fee,
+		Swaps: swaps,
+	}
+	if len(tx.Signatures) > 0 {
+		result.Signature = tx.Signatures[0]
+	}
+
+	// 无法汇总的交易仍保留原始 Swaps
+	if swapInfo, err := parser.ProcessSwapData(swaps); err == nil {
+		if blockTime := parser.GetBlockTime(); blockTime != nil {
+			swapInfo.Timestamp = *blockTime
+		}
+		result.SwapInfo = swapInfo
+	}
+
+	return result, nil
+}
+
+// decodeBlockTransaction 同时支持 base58/base64 与 json 编码的区块交易
+func decodeBlockTransaction(txWithMeta rpc.TransactionWithMeta) (*solana.Transaction, error) {
+	if txWithMeta.Transaction == nil {
+		return nil, fmt.Errorf("transaction is nil")
+	}
+	if len(txWithMeta.Transaction.GetRawJSON()) > 0 {
+		return txWithMeta.GetParsedTransaction()
+	}
+	return txWithMeta.GetTransaction()
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 
 	"github.com/gagliardetto/solana-go"
-	"github.com/mr-tron/base58"
 )
 
 // isTransfer checks if the instruction is a token transfer (Raydium, Orca)
@@ -73,11 +72,22 @@ func (p *Parser) isPumpFunTradeEventInstruction(inst solana.CompiledInstruction)
 	if !p.allAccountKeys[inst.ProgramIDIndex].Equals(PUMP_FUN_PROGRAM_ID) || len(inst.Data) < 16 {
 		return false
 	}
-	decodedBytes, err := base58.Decode(inst.Data.String())
-	if err != nil {
+	return bytes.Equal(inst.Data[:16], PumpfunTradeEventDiscriminator[:])
+}
+
+func (p *Parser) isPumpFunCreateEventInstruction(inst solana.CompiledInstruction) bool {
+	return p.isProgramDataInstruction(inst, PUMP_FUN_PROGRAM_ID, PumpfunCreateEventDiscriminator[:])
+}
+
+// isProgramDataInstruction 判断指令是否调用 program，且数据以 discriminator 开头
+func (p *Parser) isProgramDataInstruction(inst solana.CompiledInstruction, program solana.PublicKey, discriminator []byte) bool {
+	if int(inst.ProgramIDIndex) >= len(p.allAccountKeys) {
 		return false
 	}
-	return bytes.Equal(decodedBytes[:16], PumpfunTradeEventDiscriminator[:])
+	if !p.allAccountKeys[inst.ProgramIDIndex].Equals(program) {
+		return false
+	}
+	return hasDiscriminator(inst.Data, discriminator)
 }
 
 func (p *Parser) isJupiterRouteEventInstruction(inst solana.CompiledInstruction) bool {
@@ -88,9 +98,10 @@ func (p *Parser) isJupiterRouteEventInstruction(inst solana.CompiledInstruction)
 	if !p.allAccountKeys[inst.ProgramIDIndex].Equals(JUPITER_PROGRAM_ID) || len(inst.Data) < 16 {
 		return false
 	}
-	decodedBytes, err := base58.Decode(inst.Data.String())
-	if err != nil {
-		return false
-	}
-	return bytes.Equal(decodedBytes[:16], JupiterRouteEventDiscriminator[:])
+	return bytes.Equal(inst.Data[:16], JupiterRouteEventDiscriminator[:])
+}
+
+// hasDiscriminator 判断指令原始数据是否以给定鉴别器开头
+func hasDiscriminator(data []byte, discriminator []byte) bool {
+	return len(data) >= len(discriminator) && bytes.Equal(data[:len(discriminator)], discriminator)
 }
@@ -31,6 +31,10 @@ var (
 	OKX_DEX_ROUTER_PROGRAM_ID                 = solana.MustPublicKeyFromBase58("6m2CDdhRgxpH4WjvdzxAYbGxwdGUz5MziiL5jek2kBma")
 	PUMPFUN_AMM_PROGRAM_ID                    = solana.MustPublicKeyFromBase58("pAMMBay6oceH9fJKBRHGP5D4bD4sWpmSwMn52FMfXEA")
 
+	// 转发 Raydium / Pump.fun 指令的代理程序，按对应协议解析
+	RAYDIUM_PROXY_PROGRAM_ID  = solana.MustPublicKeyFromBase58("AP51WLiiqTdbZfgyRMs35PsZpdmLuPDdHYmrB23pEtMU")
+	PUMP_FUN_PROXY_PROGRAM_ID = solana.MustPublicKeyFromBase58("BSfD6SHZigAfDWSjzD5Q41jw8LmKwtmjskPH9XW1mrRW")
+
 	NATIVE_SOL_MINT_PROGRAM_ID = solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
 )
 
@@ -38,6 +42,7 @@ type SwapType string
 
 const (
 	PUMP_FUN          SwapType = "PumpFun"
+	PUMP_SWAP         SwapType = "PumpSwap"
 	JUPITER           SwapType = "Jupiter"
 	RAYDIUM           SwapType = "Raydium"
 	RAYDIUM_LAUNCHLAB SwapType = "RaydiumLaunchLab"
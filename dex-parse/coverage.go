@@ -0,0 +1,302 @@
+package solanaswapgo
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// minNativeChange 是计入余额变化的最小 SOL 变化（lamports），用于排除创建代币账户的租金等小额支出
+const minNativeChange = 10_000_000
+
+// CoverageAnalyzer 汇总一批交易中解析不出交换、但代币余额发生了变化的交易，
+// 统计其中没有解码器处理的程序（外层指令和 CPI），用于决定下一个要支持的 DEX。可以并发调用
+type CoverageAnalyzer struct {
+	// MaxExamples 为每个程序保留的示例签名个数，默认 3
+	MaxExamples int
+
+	mu           sync.Mutex
+	transactions int
+	withSwaps    int
+	uncovered    int
+	programs     map[solana.PublicKey]*ProgramCoverage
+	mints        map[solana.PublicKey]map[solana.PublicKey]int
+}
+
+// CoverageReport 是 CoverageAnalyzer 的汇总结果
+type CoverageReport struct {
+	Transactions int // 分析的成功交易数
+	WithSwaps    int // 解析出交换的交易数
+	Uncovered    int // 没有解析出交换但代币余额变化的交易数
+	// Programs 按涉及的未覆盖交易数从多到少排列
+	Programs []ProgramCoverage
+}
+
+// ProgramCoverage 是一个未被处理的程序在未覆盖交易中的出现情况
+type ProgramCoverage struct {
+	Program      solana.PublicKey
+	Transactions int // 涉及该程序的未覆盖交易数
+	TopLevel     int // 其中作为外层指令调用的交易数
+	CPI          int // 其中作为内部指令（CPI）调用的交易数
+	// SwapLike 为其中同一个账户一种资产增加、另一种资产减少的交易数，这类交易很可能是交换
+	SwapLike int
+	// Mints 为这些交易中余额变化最多的代币，最多 5 个
+	Mints    []solana.PublicKey
+	Examples []solana.Signature
+}
+
+// NewCoverageAnalyzer 创建 CoverageAnalyzer
+func NewCoverageAnalyzer() *CoverageAnalyzer {
+	return &CoverageAnalyzer{
+		MaxExamples: 3,
+		programs:    make(map[solana.PublicKey]*ProgramCoverage),
+		mints:       make(map[solana.PublicKey]map[solana.PublicKey]int),
+	}
+}
+
+// Add 记录一笔交易，swaps 为 parser.ParseTransaction 的结果。失败的交易会被忽略
+func (a *CoverageAnalyzer) Add(p *Parser, swaps []SwapData) {
+	if p.txMeta.Err != nil {
+		return
+	}
+
+	var (
+		mints    []solana.PublicKey
+		swapLike bool
+		programs map[solana.PublicKey]*[2]bool
+	)
+	if len(swaps) == 0 {
+		mints, swapLike = p.balanceChanges()
+		if len(mints) > 0 {
+			programs = p.unhandledPrograms()
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.transactions++
+	if len(swaps) > 0 {
+		a.withSwaps++
+		return
+	}
+	if len(mints) == 0 {
+		return
+	}
+	a.uncovered++
+
+	for program, calls := range programs {
+		coverage := a.programs[program]
+		if coverage == nil {
+			coverage = &ProgramCoverage{Program: program}
+			a.programs[program] = coverage
+			a.mints[program] = make(map[solana.PublicKey]int)
+		}
+		coverage.Transactions++
+		if calls[0] {
+			coverage.TopLevel++
+		}
+		if calls[1] {
+			coverage.CPI++
+		}
+		if swapLike {
+			coverage.SwapLike++
+		}
+		if len(coverage.Examples) < a.MaxExamples && len(p.txInfo.Signatures) > 0 {
+			coverage.Examples = append(coverage.Examples, p.txInfo.Signatures[0])
+		}
+		for _, mint := range mints {
+			a.mints[program][mint]++
+		}
+	}
+}
+
+// Report 返回当前的汇总结果
+func (a *CoverageAnalyzer) Report() *CoverageReport {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	report := &CoverageReport{
+		Transactions: a.transactions,
+		WithSwaps:    a.withSwaps,
+		Uncovered:    a.uncovered,
+		Programs:     make([]ProgramCoverage, 0, len(a.programs)),
+	}
+	for program, coverage := range a.programs {
+		entry := *coverage
+		entry.Examples = append([]solana.Signature(nil), coverage.Examples...)
+		entry.Mints = topMints(a.mints[program], 5)
+		report.Programs = append(report.Programs, entry)
+	}
+	sort.Slice(report.Programs, func(i, j int) bool {
+		x, y := report.Programs[i], report.Programs[j]
+		if x.Transactions != y.Transactions {
+			return x.Transactions > y.Transactions
+		}
+		if x.SwapLike != y.SwapLike {
+			return x.SwapLike > y.SwapLike
+		}
+		return x.Program.String() < y.Program.String()
+	})
+	return report
+}
+
+// WriteText 以表格形式输出前 top 个程序，top 为 0 时输出全部
+func (r *CoverageReport) WriteText(w io.Writer, top int) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "transactions: %d, with swaps: %d, uncovered with balance changes: %d\n\n", r.Transactions, r.WithSwaps, r.Uncovered)
+	fmt.Fprintf(&b, "%-4s  %-44s  %6s  %6s  %6s  %9s  %s\n", "RANK", "PROGRAM", "TXS", "TOP", "CPI", "SWAPLIKE", "EXAMPLE")
+
+	programs := r.Programs
+	if top > 0 && len(programs) > top {
+		programs = programs[:top]
+	}
+	for i, program := range programs {
+		example := ""
+		if len(program.Examples) > 0 {
+			example = program.Examples[0].String()
+		}
+		fmt.Fprintf(&b, "%-4d  %-44s  %6d  %6d  %6d  %9d  %s\n", i+1, program.Program, program.Transactions, program.TopLevel, program.CPI, program.SwapLike, example)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// unhandledPrograms 返回交易中没有解码器处理的程序，值依次表示是否作为外层指令、内部指令调用
+func (p *Parser) unhandledPrograms() map[solana.PublicKey]*[2]bool {
+	programs := make(map[solana.PublicKey]*[2]bool)
+	mark := func(index uint16, cpi int) {
+		if int(index) >= len(p.allAccountKeys) || p.programKindAt(index) != programUnknown {
+			return
+		}
+		program := p.allAccountKeys[index]
+		if infrastructurePrograms[program] || program.Equals(JUPITER_DCA_PROGRAM_ID) {
+			return
+		}
+		if programs[program] == nil {
+			programs[program] = &[2]bool{}
+		}
+		programs[program][cpi] = true
+	}
+
+	for _, instruction := range p.txInfo.Message.Instructions {
+		mark(instruction.ProgramIDIndex, 0)
+	}
+	for _, inner := range p.innerInstructions {
+		for _, instruction := range inner {
+			mark(instruction.ProgramIDIndex, 1)
+		}
+	}
+	return programs
+}
+
+// balanceChanges 返回余额发生变化的代币，以及是否有账户一种资产增加、另一种资产减少。
+// SOL 的变化按所有者账户的 lamports 计算（手续费已扣除），与 wSOL 视为同一种资产
+func (p *Parser) balanceChanges() ([]solana.PublicKey, bool) {
+	type holding struct {
+		owner solana.PublicKey
+		mint  solana.PublicKey
+	}
+	pre := make(map[uint16]uint64)
+	for _, balance := range p.txMeta.PreTokenBalances {
+		if balance.UiTokenAmount != nil {
+			pre[balance.AccountIndex], _ = parseUint64(balance.UiTokenAmount.Amount)
+		}
+	}
+
+	// 每个所有者每种资产的变化方向：1 为增加，-1 为减少
+	directions := make(map[holding]int)
+	var mints []solana.PublicKey
+	seenMints := make(map[solana.PublicKey]bool)
+	record := func(owner, mint solana.PublicKey, direction int) {
+		key := holding{owner: owner, mint: mint}
+		directions[key] += direction
+		if mint != NATIVE_SOL_MINT_PROGRAM_ID && !seenMints[mint] {
+			seenMints[mint] = true
+			mints = append(mints, mint)
+		}
+	}
+
+	for _, balance := range p.txMeta.PostTokenBalances {
+		if balance.UiTokenAmount == nil || balance.Owner == nil {
+			continue
+		}
+		post, _ := parseUint64(balance.UiTokenAmount.Amount)
+		before := pre[balance.AccountIndex]
+		delete(pre, balance.AccountIndex)
+		switch {
+		case post > before:
+			record(*balance.Owner, balance.Mint, 1)
+		case post < before:
+			record(*balance.Owner, balance.Mint, -1)
+		}
+	}
+	// 交易中关闭的代币账户只出现在 PreTokenBalances 中
+	for _, balance := range p.txMeta.PreTokenBalances {
+		if amount, ok := pre[balance.AccountIndex]; ok && amount > 0 && balance.Owner != nil {
+			record(*balance.Owner, balance.Mint, -1)
+		}
+	}
+	if len(mints) == 0 {
+		return nil, false
+	}
+
+	for i := 0; i < len(p.txMeta.PreBalances) && i < len(p.txMeta.PostBalances) && i < len(p.allAccountKeys); i++ {
+		change := int64(p.txMeta.PostBalances[i]) - int64(p.txMeta.PreBalances[i])
+		if i == 0 {
+			change += int64(p.txMeta.Fee)
+		}
+		switch {
+		case change >= minNativeChange:
+			record(p.allAccountKeys[i], NATIVE_SOL_MINT_PROGRAM_ID, 1)
+		case change <= -minNativeChange:
+			record(p.allAccountKeys[i], NATIVE_SOL_MINT_PROGRAM_ID, -1)
+		}
+	}
+
+	increased := make(map[solana.PublicKey]map[solana.PublicKey]bool)
+	decreased := make(map[solana.PublicKey]map[solana.PublicKey]bool)
+	for key, direction := range directions {
+		target := increased
+		if direction < 0 {
+			target = decreased
+		} else if direction == 0 {
+			continue
+		}
+		if target[key.owner] == nil {
+			target[key.owner] = make(map[solana.PublicKey]bool)
+		}
+		target[key.owner][key.mint] = true
+	}
+	for owner, in := range increased {
+		for mint := range decreased[owner] {
+			if !in[mint] {
+				return mints, true
+			}
+		}
+	}
+	return mints, false
+}
+
+// topMints 按出现次数返回前 n 个代币
+func topMints(counts map[solana.PublicKey]int, n int) []solana.PublicKey {
+	mints := make([]solana.PublicKey, 0, len(counts))
+	for mint := range counts {
+		mints = append(mints, mint)
+	}
+	sort.Slice(mints, func(i, j int) bool {
+		if counts[mints[i]] != counts[mints[j]] {
+			return counts[mints[i]] > counts[mints[j]]
+		}
+		return mints[i].String() < mints[j].String()
+	})
+	if len(mints) > n {
+		mints = mints[:n]
+	}
+	return mints
+}
@@ -6,7 +6,6 @@ import (
 
 	ag_binary "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
-	"github.com/mr-tron/base58"
 )
 
 // Boop.fun 指令判别器
@@ -46,7 +45,10 @@ func (p *Parser) processBoopFunSwaps(instructionIndex int) []SwapData {
 	if programID.Equals(BOOPFUN_PROGRAM_ID) {
 		// 尝试解析指令数据
 		instructionData, err := p.parseBoopFunInstruction(mainInstruction)
-		if err == nil && instructionData != nil {
+		if err != nil {
+			p.recordDecodeError(BOOPFUN_PROGRAM_ID, err)
+		}
+		if instructionData != nil {
 			// 创建基于指令数据的事件
 			event := &BoopFunSwapEvent{
 				BuyAmount:    instructionData.BuyAmount,
@@ -63,21 +65,17 @@ func (p *Parser) processBoopFunSwaps(instructionIndex int) []SwapData {
 	}
 
 	// 如果指令解析失败，回退到转账解析作为保底机制
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				switch {
-				case p.isTransferCheck(innerInstruction):
-					transfer := p.processTransferCheck(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: BOOPFUN, Data: transfer})
-					}
-				case p.isTransfer(innerInstruction):
-					transfer := p.processTransfer(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: BOOPFUN, Data: transfer})
-					}
-				}
+	for _, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		switch {
+		case p.isTransferCheck(innerInstruction):
+			transfer := p.processTransferCheck(innerInstruction)
+			if transfer != nil {
+				swaps = append(swaps, SwapData{Type: BOOPFUN, Data: transfer})
+			}
+		case p.isTransfer(innerInstruction):
+			transfer := p.processTransfer(innerInstruction)
+			if transfer != nil {
+				swaps = append(swaps, SwapData{Type: BOOPFUN, Data: transfer})
 			}
 		}
 	}
@@ -85,21 +83,14 @@ func (p *Parser) processBoopFunSwaps(instructionIndex int) []SwapData {
 	return swaps
 }
 
-// parseBoopFunInstruction 解析 Boop.fun 指令数据
+// parseBoopFunInstruction 解析 Boop.fun 指令数据，不是 buy_token 指令时返回 nil, nil
 func (p *Parser) parseBoopFunInstruction(instruction solana.CompiledInstruction) (*BoopFunInstructionData, error) {
-	decodedBytes, err := base58.Decode(instruction.Data.String())
-	if err != nil {
-		return nil, fmt.Errorf("error decoding instruction data: %s", err)
+	decodedBytes := instruction.Data
+	if len(decodedBytes) < 8 || !bytes.Equal(decodedBytes[:8], BoopFunBuyTokenDiscriminator[:]) {
+		return nil, nil
 	}
-
 	if len(decodedBytes) < 24 { // 至少需要 8 + 8 + 8 = 24 字节
-		return nil, fmt.Errorf("instruction data too short")
-	}
-
-	// 检查判别器
-	discriminator := decodedBytes[:8]
-	if !bytes.Equal(discriminator, BoopFunBuyTokenDiscriminator[:]) {
-		return nil, fmt.Errorf("unknown Boop.fun instruction discriminator: %v", discriminator)
+		return nil, fmt.Errorf("instruction data too short: %d bytes", len(decodedBytes))
 	}
 
 	// 跳过判别器，解析指令参数
@@ -120,15 +111,11 @@ func (p *Parser) enrichBoopFunEventFromTransfers(event *BoopFunSwapEvent, instru
 	var transfers []*TransferCheck
 
 	// 收集所有的转账记录
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				if p.isTransferCheck(innerInstruction) {
-					transfer := p.processTransferCheck(innerInstruction)
-					if transfer != nil {
-						transfers = append(transfers, transfer)
-					}
-				}
+	for _, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		if p.isTransferCheck(innerInstruction) {
+			transfer := p.processTransferCheck(innerInstruction)
+			if transfer != nil {
+				transfers = append(transfers, transfer)
 			}
 		}
 	}
@@ -1,12 +1,10 @@
 package solanaswapgo
 
 import (
-	"encoding/json"
 	"fmt"
 
 	ag_binary "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
-	"github.com/mr-tron/base58"
 )
 
 type JupiterSwapEvent struct {
@@ -27,18 +25,14 @@ var JupiterRouteEventDiscriminator = [16]byte{228, 69, 165, 46, 81, 203, 154, 29
 
 func (p *Parser) processJupiterSwaps(instructionIndex int) []SwapData {
 	var swaps []SwapData
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				if p.isJupiterRouteEventInstruction(innerInstruction) {
-					eventData, err := p.parseJupiterRouteEventInstruction(innerInstruction)
-					if err != nil {
-						p.Log.Errorf("error processing Pumpfun trade event: %s", err)
-					}
-					if eventData != nil {
-						swaps = append(swaps, SwapData{Type: JUPITER, Data: eventData})
-					}
-				}
+	for _, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		if p.isJupiterRouteEventInstruction(innerInstruction) {
+			eventData, err := p.parseJupiterRouteEventInstruction(innerInstruction)
+			if err != nil {
+				p.recordDecodeError(JUPITER_PROGRAM_ID, err)
+			}
+			if eventData != nil {
+				swaps = append(swaps, SwapData{Type: JUPITER, Data: eventData})
 			}
 		}
 	}
@@ -56,23 +50,22 @@ func (p *Parser) containsDCAProgram() bool {
 }
 
 func (p *Parser) parseJupiterRouteEventInstruction(instruction solana.CompiledInstruction) (*JupiterSwapEventData, error) {
-	decodedBytes, err := base58.Decode(instruction.Data.String())
-	if err != nil {
-		return nil, fmt.Errorf("error decoding instruction data: %s", err)
+	if len(instruction.Data) < 16 {
+		return nil, fmt.Errorf("instruction data too short: %d bytes", len(instruction.Data))
 	}
-	decoder := ag_binary.NewBorshDecoder(decodedBytes[16:])
+	decoder := ag_binary.NewBorshDecoder(instruction.Data[16:])
 
 	jupSwapEvent, err := handleJupiterRouteEvent(decoder)
 	if err != nil {
 		return nil, fmt.Errorf("error decoding jupiter swap event: %s", err)
 	}
 
-	inputMintDecimals, exists := p.splDecimalsMap[jupSwapEvent.InputMint.String()]
+	inputMintDecimals, exists := p.splDecimalsMap[jupSwapEvent.InputMint]
 	if !exists {
 		inputMintDecimals = 0
 	}
 
-	outputMintDecimals, exists := p.splDecimalsMap[jupSwapEvent.OutputMint.String()]
+	outputMintDecimals, exists := p.splDecimalsMap[jupSwapEvent.OutputMint]
 	if !exists {
 		outputMintDecimals = 0
 	}
@@ -93,12 +86,11 @@ func handleJupiterRouteEvent(decoder *ag_binary.Decoder) (*JupiterSwapEvent, err
 }
 
 func (p *Parser) extractSPLDecimals() error {
-	mintToDecimals := make(map[string]uint8)
+	mintToDecimals := make(map[solana.PublicKey]uint8, len(p.txMeta.PostTokenBalances)+1)
 
 	for _, accountInfo := range p.txMeta.PostTokenBalances {
 		if !accountInfo.Mint.IsZero() {
-			mintAddress := accountInfo.Mint.String()
-			mintToDecimals[mintAddress] = uint8(accountInfo.UiTokenAmount.Decimals)
+			mintToDecimals[accountInfo.Mint] = uint8(accountInfo.UiTokenAmount.Decimals)
 		}
 	}
 
@@ -124,7 +116,7 @@ func (p *Parser) extractSPLDecimals() error {
 			return
 		}
 
-		mint := p.allAccountKeys[instr.Accounts[1]].String()
+		mint := p.allAccountKeys[instr.Accounts[1]]
 		if _, exists := mintToDecimals[mint]; !exists {
 			mintToDecimals[mint] = 0
 		}
@@ -140,8 +132,8 @@ func (p *Parser) extractSPLDecimals() error {
 	}
 
 	// Add Native SOL if not present
-	if _, exists := mintToDecimals[NATIVE_SOL_MINT_PROGRAM_ID.String()]; !exists {
-		mintToDecimals[NATIVE_SOL_MINT_PROGRAM_ID.String()] = 9 // Native SOL has 9 decimal places
+	if _, exists := mintToDecimals[NATIVE_SOL_MINT_PROGRAM_ID]; !exists {
+		mintToDecimals[NATIVE_SOL_MINT_PROGRAM_ID] = 9 // Native SOL has 9 decimal places
 	}
 
 	p.splDecimalsMap = mintToDecimals
@@ -162,20 +154,20 @@ func parseJupiterEvents(events []SwapData) (*SwapInfo, error) {
 			continue
 		}
 
-		var jupiterEvent JupiterSwapEventData
-		eventData, err := json.Marshal(event.Data)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal event data: %v", err)
-		}
-
-		if err := json.Unmarshal(eventData, &jupiterEvent); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal Jupiter event data: %v", err)
+		var jupiterEvent *JupiterSwapEventData
+		switch data := event.Data.(type) {
+		case *JupiterSwapEventData:
+			jupiterEvent = data
+		case JupiterSwapEventData:
+			jupiterEvent = &data
+		default:
+			return nil, fmt.Errorf("unexpected Jupiter event data type: %T", event.Data)
 		}
 
 		if i == 0 {
-			firstSwap = &jupiterEvent
+			firstSwap = jupiterEvent
 		}
-		lastSwap = &jupiterEvent
+		lastSwap = jupiterEvent
 	}
 
 	if firstSwap == nil || lastSwap == nil {
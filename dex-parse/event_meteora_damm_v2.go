@@ -6,7 +6,6 @@ import (
 
 	ag_binary "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
-	"github.com/mr-tron/base58"
 )
 
 // Meteora DAMM v2 swap 指令判别器
@@ -47,7 +46,10 @@ func (p *Parser) processMeteoraDAMMv2Swaps(instructionIndex int) []SwapData {
 	// 检查是否是 Meteora DAMM v2 程序
 	if programID.Equals(METEORA_DAMM_V2_PROGRAM_ID) {
 		instructionData, err := p.parseMeteoraDAMMv2Instruction(mainInstruction)
-		if err == nil && instructionData != nil {
+		if err != nil {
+			p.recordDecodeError(METEORA_DAMM_V2_PROGRAM_ID, err)
+		}
+		if instructionData != nil {
 			// 创建基于指令数据的事件
 			event := &MeteoraDAMMv2SwapEvent{
 				AmountIn:         instructionData.Amount,
@@ -64,21 +66,17 @@ func (p *Parser) processMeteoraDAMMv2Swaps(instructionIndex int) []SwapData {
 	}
 
 	// 如果指令解析失败，回退到转账解析作为保底机制
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				switch {
-				case p.isTransferCheck(innerInstruction):
-					transfer := p.processTransferCheck(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: METEORA, Data: transfer})
-					}
-				case p.isTransfer(innerInstruction):
-					transfer := p.processTransfer(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: METEORA, Data: transfer})
-					}
-				}
+	for _, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		switch {
+		case p.isTransferCheck(innerInstruction):
+			transfer := p.processTransferCheck(innerInstruction)
+			if transfer != nil {
+				swaps = append(swaps, SwapData{Type: METEORA, Data: transfer})
+			}
+		case p.isTransfer(innerInstruction):
+			transfer := p.processTransfer(innerInstruction)
+			if transfer != nil {
+				swaps = append(swaps, SwapData{Type: METEORA, Data: transfer})
 			}
 		}
 	}
@@ -86,36 +84,17 @@ func (p *Parser) processMeteoraDAMMv2Swaps(instructionIndex int) []SwapData {
 	return swaps
 }
 
-// parseMeteoraDAMMv2Instruction 解析 Meteora DAMM v2 指令数据
+// parseMeteoraDAMMv2Instruction 解析 Meteora DAMM v2 指令数据，不是 swap 指令时返回 nil, nil
 func (p *Parser) parseMeteoraDAMMv2Instruction(instruction solana.CompiledInstruction) (*MeteoraDAMMv2InstructionData, error) {
-	decodedBytes, err := base58.Decode(instruction.Data.String())
-	if err != nil {
-		fmt.Printf("  [DEBUG] Base58 解码失败: %s\n", err)
-		return nil, fmt.Errorf("error decoding instruction data: %s", err)
-	}
-
-	fmt.Printf("  [DEBUG] 指令数据长度: %d\n", len(decodedBytes))
-	if len(decodedBytes) < 8 {
-		fmt.Printf("  [DEBUG] 指令数据太短\n")
-		return nil, fmt.Errorf("instruction data too short")
-	}
-
-	// 检查指令类型
-	discriminator := decodedBytes[:8]
-	fmt.Printf("  [DEBUG] 实际判别器: %v\n", discriminator)
-	fmt.Printf("  [DEBUG] 期望判别器: %v\n", MeteoraDAMMv2SwapDiscriminator[:])
-
-	if !bytes.Equal(discriminator, MeteoraDAMMv2SwapDiscriminator[:]) {
-		fmt.Printf("  [DEBUG] 判别器不匹配\n")
-		return nil, fmt.Errorf("unknown Meteora DAMM v2 instruction discriminator: %v", discriminator)
+	decodedBytes := instruction.Data
+	if len(decodedBytes) < 8 || !bytes.Equal(decodedBytes[:8], MeteoraDAMMv2SwapDiscriminator[:]) {
+		return nil, nil
 	}
 
 	// 跳过判别器，解析指令参数
 	remainingBytes := decodedBytes[8:]
-	fmt.Printf("  [DEBUG] 剩余字节长度: %d\n", len(remainingBytes))
 
 	if len(remainingBytes) < 16 { // 至少需要 8 + 8 = 16 字节用于两个 uint64
-		fmt.Printf("  [DEBUG] 剩余字节太少，无法解析参数\n")
 		return nil, fmt.Errorf("instruction data too short for swap parameters")
 	}
 
@@ -123,12 +102,9 @@ func (p *Parser) parseMeteoraDAMMv2Instruction(instruction solana.CompiledInstru
 
 	var instructionData MeteoraDAMMv2InstructionData
 	if err := decoder.Decode(&instructionData); err != nil {
-		fmt.Printf("  [DEBUG] Borsh 解码失败: %s\n", err)
 		return nil, fmt.Errorf("error unmarshaling instruction data: %s", err)
 	}
 
-	fmt.Printf("  [DEBUG] 解码成功! Amount=%d, OtherAmountThreshold=%d\n",
-		instructionData.Amount, instructionData.OtherAmountThreshold)
 	return &instructionData, nil
 }
 
@@ -137,15 +113,11 @@ func (p *Parser) enrichMeteoraDAMMv2EventFromTransfers(event *MeteoraDAMMv2SwapE
 	var transfers []*TransferCheck
 
 	// 收集所有的转账记录
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				if p.isTransferCheck(innerInstruction) {
-					transfer := p.processTransferCheck(innerInstruction)
-					if transfer != nil {
-						transfers = append(transfers, transfer)
-					}
-				}
+	for _, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		if p.isTransferCheck(innerInstruction) {
+			transfer := p.processTransferCheck(innerInstruction)
+			if transfer != nil {
+				transfers = append(transfers, transfer)
 			}
 		}
 	}
@@ -6,7 +6,6 @@ import (
 
 	ag_binary "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
-	"github.com/mr-tron/base58"
 )
 
 // Meteora DBC swap 指令判别器
@@ -66,7 +65,10 @@ func (p *Parser) processMeteoraDBCSwaps(instructionIndex int) []SwapData {
 
 		// 如果事件解析失败，尝试解析指令数据
 		instructionData, err := p.parseMeteoraDBCInstruction(mainInstruction)
-		if err == nil && instructionData != nil {
+		if err != nil {
+			p.recordDecodeError(METEORA_DBC_PROGRAM_ID, err)
+		}
+		if instructionData != nil {
 			// 创建基于指令数据的事件
 			event := &MeteoraDBCSwapEvent{
 				AmountIn:         instructionData.AmountIn,
@@ -82,21 +84,17 @@ func (p *Parser) processMeteoraDBCSwaps(instructionIndex int) []SwapData {
 	}
 
 	// 如果都失败，回退到转账解析作为保底机制
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				switch {
-				case p.isTransferCheck(innerInstruction):
-					transfer := p.processTransferCheck(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: METEORA, Data: transfer})
-					}
-				case p.isTransfer(innerInstruction):
-					transfer := p.processTransfer(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: METEORA, Data: transfer})
-					}
-				}
+	for _, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		switch {
+		case p.isTransferCheck(innerInstruction):
+			transfer := p.processTransferCheck(innerInstruction)
+			if transfer != nil {
+				swaps = append(swaps, SwapData{Type: METEORA, Data: transfer})
+			}
+		case p.isTransfer(innerInstruction):
+			transfer := p.processTransfer(innerInstruction)
+			if transfer != nil {
+				swaps = append(swaps, SwapData{Type: METEORA, Data: transfer})
 			}
 		}
 	}
@@ -106,14 +104,10 @@ func (p *Parser) processMeteoraDBCSwaps(instructionIndex int) []SwapData {
 
 // parseMeteoraDBCEvent 从内部指令中解析 Meteora DBC 事件
 func (p *Parser) parseMeteoraDBCEvent(instructionIndex int) *MeteoraDBCSwapEvent {
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				// 查找 Meteora DBC 的内部事件指令
-				if p.isMeteoraDBCEventInstruction(innerInstruction) {
-					return p.parseMeteoraDBCEventInstruction(innerInstruction)
-				}
-			}
+	for _, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		// 查找 Meteora DBC 的内部事件指令
+		if p.isMeteoraDBCEventInstruction(innerInstruction) {
+			return p.parseMeteoraDBCEventInstruction(innerInstruction)
 		}
 	}
 	return nil
@@ -136,21 +130,14 @@ func (p *Parser) parseMeteoraDBCEventInstruction(instruction solana.CompiledInst
 	return nil
 }
 
-// parseMeteoraDBCInstruction 解析 Meteora DBC 指令数据
+// parseMeteoraDBCInstruction 解析 Meteora DBC 指令数据，不是 swap 指令时返回 nil, nil
 func (p *Parser) parseMeteoraDBCInstruction(instruction solana.CompiledInstruction) (*MeteoraDBCInstructionData, error) {
-	decodedBytes, err := base58.Decode(instruction.Data.String())
-	if err != nil {
-		return nil, fmt.Errorf("error decoding instruction data: %s", err)
+	decodedBytes := instruction.Data
+	if len(decodedBytes) < 8 || !bytes.Equal(decodedBytes[:8], MeteoraDBCSwapDiscriminator[:]) {
+		return nil, nil
 	}
-
 	if len(decodedBytes) < 24 { // 至少需要 8 + 8 + 8 = 24 字节
-		return nil, fmt.Errorf("instruction data too short")
-	}
-
-	// 检查判别器
-	discriminator := decodedBytes[:8]
-	if !bytes.Equal(discriminator, MeteoraDBCSwapDiscriminator[:]) {
-		return nil, fmt.Errorf("unknown Meteora DBC instruction discriminator: %v", discriminator)
+		return nil, fmt.Errorf("instruction data too short: %d bytes", len(decodedBytes))
 	}
 
 	// 跳过判别器，解析指令参数
@@ -173,15 +160,11 @@ func (p *Parser) enrichMeteoraDBCEventFromTransfers(event *MeteoraDBCSwapEvent,
 	var transfers []*TransferCheck
 
 	// 收集所有的转账记录
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				if p.isTransferCheck(innerInstruction) {
-					transfer := p.processTransferCheck(innerInstruction)
-					if transfer != nil {
-						transfers = append(transfers, transfer)
-					}
-				}
+	for _, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		if p.isTransferCheck(innerInstruction) {
+			transfer := p.processTransferCheck(innerInstruction)
+			if transfer != nil {
+				transfers = append(transfers, transfer)
 			}
 		}
 	}
@@ -3,8 +3,6 @@ package solanaswapgo
 import (
 	"bytes"
 	"fmt"
-
-	"github.com/mr-tron/base58"
 )
 
 var (
@@ -29,14 +27,9 @@ func (p *Parser) processOKXSwaps(instructionIndex int) []SwapData {
 		return nil
 	}
 
-	if len(parentInstruction.Data) < 8 {
-		p.Log.Warnf("instruction %d skipped: data too short (%d)", instructionIndex, len(parentInstruction.Data))
-		return nil
-	}
-
-	decodedBytes, err := base58.Decode(parentInstruction.Data.String())
-	if err != nil {
-		p.Log.Errorf("failed to decode okx swap instruction %d: %s", instructionIndex, err)
+	decodedBytes := parentInstruction.Data
+	if len(decodedBytes) < 8 {
+		p.recordDecodeError(OKX_DEX_ROUTER_PROGRAM_ID, fmt.Errorf("instruction %d data too short: %d bytes", instructionIndex, len(decodedBytes)))
 		return nil
 	}
 
@@ -5,14 +5,31 @@ import (
 
 	ag_binary "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
-	"github.com/mr-tron/base58"
+	"github.com/zzispp/solana-dex-parse/pumpcurve"
 )
 
 var (
 	PumpfunTradeEventDiscriminator  = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 189, 219, 127, 211, 78, 230, 97, 238}
 	PumpfunCreateEventDiscriminator = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 27, 114, 169, 77, 222, 235, 99, 118}
+	// PumpfunCompleteEventDiscriminator 联合曲线被买完时发出
+	PumpfunCompleteEventDiscriminator = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 95, 114, 97, 156, 212, 46, 152, 8}
+	// PumpfunCompleteMigrationEventDiscriminator 迁移到 PumpSwap 完成时发出（CompletePumpAmmMigrationEvent）
+	PumpfunCompleteMigrationEventDiscriminator = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 189, 233, 93, 185, 92, 148, 234, 148}
+
+	// PumpfunMigrateDiscriminator 是 migrate 指令的判别器
+	PumpfunMigrateDiscriminator = [8]byte{155, 234, 231, 146, 236, 158, 162, 30}
+)
+
+// Pump.fun TradeEvent 的布局版本。程序升级时只在事件末尾追加字段，版本由事件数据的长度决定
+const (
+	PumpfunTradeEventV1 = 1 // 原始的 8 个字段
+	PumpfunTradeEventV2 = 2 // 追加真实储备
+	PumpfunTradeEventV3 = 3 // 追加手续费接收者、手续费和创作者费用
+	PumpfunTradeEventV4 = 4 // 追加交易量统计
+	PumpfunTradeEventV5 = 5 // 追加指令名
 )
 
+// PumpfunTradeEvent 是 Pump.fun 交易时发出的事件，Version 之后的字段只在对应版本及以上的事件中存在，否则为零值
 type PumpfunTradeEvent struct {
 	Mint                 solana.PublicKey
 	SolAmount            uint64
@@ -22,54 +39,82 @@ type PumpfunTradeEvent struct {
 	Timestamp            int64
 	VirtualSolReserves   uint64
 	VirtualTokenReserves uint64
+
+	Version int
+
+	// V2
+	RealSolReserves   uint64
+	RealTokenReserves uint64
+	// V3，Fee 和 CreatorFee 以 lamports 计
+	FeeRecipient          solana.PublicKey
+	FeeBasisPoints        uint64
+	Fee                   uint64
+	Creator               solana.PublicKey
+	CreatorFeeBasisPoints uint64
+	CreatorFee            uint64
+	// V4
+	TrackVolume          bool
+	TotalUnclaimedTokens uint64
+	TotalClaimedTokens   uint64
+	CurrentSolVolume     uint64
+	LastUpdateTimestamp  int64
+	// V5，如 "buy"、"sell"、"buy_exact_sol_in"
+	IxName string
+
+	// 以下字段不在事件中，由交易后的储备按 pumpcurve 计算
+	SpotPrice     float64 // 每个代币的 SOL 价格
+	MarketCap     float64 // 按总供应量计算的市值（SOL）
+	CurveProgress float64 // 联合曲线完成百分比（0 ~ 100）
 }
 
+// PumpfunCreateEvent 是 Pump.fun 创建代币时发出的事件。
+// Creator 之后的字段是后来追加的，旧版事件中不存在：此时 Creator 取 User，其余为 0
 type PumpfunCreateEvent struct {
-	Name         string
-	Symbol       string
-	Uri          string
+	Name                 string
+	Symbol               string
+	Uri                  string
+	Mint                 solana.PublicKey
+	BondingCurve         solana.PublicKey
+	User                 solana.PublicKey
+	Creator              solana.PublicKey
+	Timestamp            int64
+	VirtualTokenReserves uint64
+	VirtualSolReserves   uint64
+	RealTokenReserves    uint64
+	TokenTotalSupply     uint64
+}
+
+// PumpfunCompleteEvent 是联合曲线被买完（代币毕业）时发出的事件
+type PumpfunCompleteEvent struct {
+	User         solana.PublicKey
 	Mint         solana.PublicKey
 	BondingCurve solana.PublicKey
-	User         solana.PublicKey
+	Timestamp    int64
 }
 
-func (p *Parser) processPumpfunSwaps(instructionIndex int) []SwapData {
-	var swaps []SwapData
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				if p.isPumpFunTradeEventInstruction(innerInstruction) {
-					eventData, err := p.parsePumpfunTradeEventInstruction(innerInstruction)
-					if err != nil {
-						p.Log.Errorf("error processing Pumpfun trade event: %s", err)
-					}
-					if eventData != nil {
-						swaps = append(swaps, SwapData{Type: PUMP_FUN, Data: eventData})
-					}
-				}
-			}
-		}
-	}
-	return swaps
+// PumpfunCompleteMigrationEvent 是 migrate 指令把联合曲线迁移到 PumpSwap 池后发出的事件，
+// MintAmount 和 SolAmount 为转入池子的代币和 SOL（已扣除 PoolMigrationFee）
+type PumpfunCompleteMigrationEvent struct {
+	User             solana.PublicKey
+	Mint             solana.PublicKey
+	MintAmount       uint64
+	SolAmount        uint64
+	PoolMigrationFee uint64
+	BondingCurve     solana.PublicKey
+	Timestamp        int64
+	Pool             solana.PublicKey
 }
 
-func (p *Parser) processPumpfunAMMSwaps(instructionIndex int) []SwapData {
+func (p *Parser) processPumpfunSwaps(instructionIndex int) []SwapData {
 	var swaps []SwapData
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				switch {
-				case p.isTransferCheck(innerInstruction):
-					transfer := p.processTransferCheck(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: PUMP_FUN, Data: transfer})
-					}
-				case p.isTransfer(innerInstruction):
-					transfer := p.processTransfer(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: PUMP_FUN, Data: transfer})
-					}
-				}
+	for _, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		if p.isPumpFunTradeEventInstruction(innerInstruction) {
+			eventData, err := p.parsePumpfunTradeEventInstruction(innerInstruction)
+			if err != nil {
+				p.recordDecodeError(PUMP_FUN_PROGRAM_ID, err)
+			}
+			if eventData != nil {
+				swaps = append(swaps, SwapData{Type: PUMP_FUN, Data: eventData})
 			}
 		}
 	}
@@ -77,20 +122,88 @@ func (p *Parser) processPumpfunAMMSwaps(instructionIndex int) []SwapData {
 }
 
 func (p *Parser) parsePumpfunTradeEventInstruction(instruction solana.CompiledInstruction) (*PumpfunTradeEvent, error) {
-	decodedBytes, err := base58.Decode(instruction.Data.String())
-	if err != nil {
-		return nil, fmt.Errorf("error decoding instruction data: %s", err)
+	if len(instruction.Data) < 16 {
+		return nil, fmt.Errorf("instruction data too short: %d bytes", len(instruction.Data))
 	}
-	decoder := ag_binary.NewBorshDecoder(decodedBytes[16:])
+	decoder := ag_binary.NewBorshDecoder(instruction.Data[16:])
 
 	return handlePumpfunTradeEvent(decoder)
 }
 
 func handlePumpfunTradeEvent(decoder *ag_binary.Decoder) (*PumpfunTradeEvent, error) {
 	var trade PumpfunTradeEvent
-	if err := decoder.Decode(&trade); err != nil {
+	layouts := [][]interface{}{
+		{&trade.Mint, &trade.SolAmount, &trade.TokenAmount, &trade.IsBuy, &trade.User, &trade.Timestamp, &trade.VirtualSolReserves, &trade.VirtualTokenReserves},
+		{&trade.RealSolReserves, &trade.RealTokenReserves},
+		{&trade.FeeRecipient, &trade.FeeBasisPoints, &trade.Fee, &trade.Creator, &trade.CreatorFeeBasisPoints, &trade.CreatorFee},
+		{&trade.TrackVolume, &trade.TotalUnclaimedTokens, &trade.TotalClaimedTokens, &trade.CurrentSolVolume, &trade.LastUpdateTimestamp},
+		{&trade.IxName},
+	}
+	version, err := decodeLayouts(decoder, layouts)
+	if err != nil {
 		return nil, fmt.Errorf("error unmarshaling TradeEvent: %s", err)
 	}
+	trade.Version = version
+
+	curve := pumpcurve.FromVirtualReserves(trade.VirtualSolReserves, trade.VirtualTokenReserves)
+	if trade.Version >= PumpfunTradeEventV2 {
+		curve.RealSolReserves, curve.RealTokenReserves = trade.RealSolReserves, trade.RealTokenReserves
+	}
+	trade.SpotPrice = curve.SpotPrice()
+	trade.MarketCap = curve.MarketCap()
+	trade.CurveProgress = curve.Progress()
 
 	return &trade, nil
 }
+
+// decodeLayouts 解码只在末尾追加字段的事件：layouts[0] 为最初的字段，之后每组为一个版本追加的字段。
+// 数据用完时停止，返回解码到的版本（从 1 开始）；某一组只有部分数据时返回错误
+func decodeLayouts(decoder *ag_binary.Decoder, layouts [][]interface{}) (int, error) {
+	version := 0
+	for i, fields := range layouts {
+		if i > 0 && !decoder.HasRemaining() {
+			break
+		}
+		if err := decodeFields(decoder, fields...); err != nil {
+			return version, fmt.Errorf("v%d: %s", i+1, err)
+		}
+		version = i + 1
+	}
+	return version, nil
+}
+
+// decodeFields 按顺序以 borsh 解码各个字段
+func decodeFields(decoder *ag_binary.Decoder, fields ...interface{}) error {
+	for _, field := range fields {
+		if err := decoder.Decode(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Parser) parsePumpfunCreateEventInstruction(instruction solana.CompiledInstruction) (*PumpfunCreateEvent, error) {
+	if len(instruction.Data) < 16 {
+		return nil, fmt.Errorf("instruction data too short: %d bytes", len(instruction.Data))
+	}
+	decoder := ag_binary.NewBorshDecoder(instruction.Data[16:])
+
+	return handlePumpfunCreateEvent(decoder)
+}
+
+func handlePumpfunCreateEvent(decoder *ag_binary.Decoder) (*PumpfunCreateEvent, error) {
+	var create PumpfunCreateEvent
+	if err := decodeFields(decoder, &create.Name, &create.Symbol, &create.Uri, &create.Mint, &create.BondingCurve, &create.User); err != nil {
+		return nil, fmt.Errorf("error unmarshaling CreateEvent: %s", err)
+	}
+
+	create.Creator = create.User
+	if !decoder.HasRemaining() {
+		return &create, nil
+	}
+	if err := decodeFields(decoder, &create.Creator, &create.Timestamp, &create.VirtualTokenReserves, &create.VirtualSolReserves, &create.RealTokenReserves, &create.TokenTotalSupply); err != nil {
+		return nil, fmt.Errorf("error unmarshaling CreateEvent: %s", err)
+	}
+
+	return &create, nil
+}
@@ -0,0 +1,266 @@
+package solanaswapgo
+
+import (
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// PumpfunAMMCreatePoolEventDiscriminator 是 PumpSwap 创建池子时发出的事件的判别器
+var PumpfunAMMCreatePoolEventDiscriminator = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 177, 49, 12, 210, 160, 118, 167, 116}
+
+// PumpfunAMMCreatePoolEvent 是 PumpSwap 创建池子时发出的事件。CoinCreator 是后来追加的字段，旧版事件中为零值
+type PumpfunAMMCreatePoolEvent struct {
+	Timestamp             int64
+	Index                 uint16
+	Creator               solana.PublicKey
+	BaseMint              solana.PublicKey
+	QuoteMint             solana.PublicKey
+	BaseMintDecimals      uint8
+	QuoteMintDecimals     uint8
+	BaseAmountIn          uint64
+	QuoteAmountIn         uint64
+	PoolBaseAmount        uint64
+	PoolQuoteAmount       uint64
+	MinimumLiquidity      uint64
+	InitialLiquidity      uint64
+	LpTokenAmountOut      uint64
+	PoolBump              uint8
+	Pool                  solana.PublicKey
+	LpMint                solana.PublicKey
+	UserBaseTokenAccount  solana.PublicKey
+	UserQuoteTokenAccount solana.PublicKey
+	CoinCreator           solana.PublicKey `bin:"-"`
+}
+
+func (p *Parser) parsePumpfunAMMCreatePoolEventInstruction(instruction solana.CompiledInstruction) (*PumpfunAMMCreatePoolEvent, error) {
+	if len(instruction.Data) < 16 {
+		return nil, fmt.Errorf("instruction data too short: %d bytes", len(instruction.Data))
+	}
+	decoder := ag_binary.NewBorshDecoder(instruction.Data[16:])
+
+	var event PumpfunAMMCreatePoolEvent
+	if err := decoder.Decode(&event); err != nil {
+		return nil, fmt.Errorf("error unmarshaling CreatePoolEvent: %s", err)
+	}
+	if decoder.HasRemaining() {
+		if err := decoder.Decode(&event.CoinCreator); err != nil {
+			return nil, fmt.Errorf("error unmarshaling CreatePoolEvent: %s", err)
+		}
+	}
+
+	return &event, nil
+}
+
+// PumpSwap 交易事件的判别器
+var (
+	PumpfunAMMBuyEventDiscriminator  = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 103, 244, 82, 31, 44, 245, 119, 119}
+	PumpfunAMMSellEventDiscriminator = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 62, 47, 55, 10, 165, 3, 220, 42}
+)
+
+// PumpSwap 交易事件的布局版本，与 Pump.fun 一样只在末尾追加字段
+const (
+	PumpfunAMMEventV1 = 1 // 最初的字段
+	PumpfunAMMEventV2 = 2 // 追加代币创作者费用
+	PumpfunAMMEventV3 = 3 // 追加交易量统计（仅 BuyEvent）
+	PumpfunAMMEventV4 = 4 // 追加 MinBaseAmountOut 和指令名（仅 BuyEvent）
+)
+
+// PumpfunAMMBuyEvent 是 PumpSwap 买入 base 代币时发出的事件，费用以 quote 代币计。
+// BaseMint 之后的字段不在事件中，取自池子的指令账户和代币余额
+type PumpfunAMMBuyEvent struct {
+	Timestamp                        int64
+	BaseAmountOut                    uint64
+	MaxQuoteAmountIn                 uint64
+	UserBaseTokenReserves            uint64
+	UserQuoteTokenReserves           uint64
+	PoolBaseTokenReserves            uint64
+	PoolQuoteTokenReserves           uint64
+	QuoteAmountIn                    uint64
+	LpFeeBasisPoints                 uint64
+	LpFee                            uint64
+	ProtocolFeeBasisPoints           uint64
+	ProtocolFee                      uint64
+	QuoteAmountInWithLpFee           uint64
+	UserQuoteAmountIn                uint64 // 用户实际支付的 quote 数量，包含全部费用
+	Pool                             solana.PublicKey
+	User                             solana.PublicKey
+	UserBaseTokenAccount             solana.PublicKey
+	UserQuoteTokenAccount            solana.PublicKey
+	ProtocolFeeRecipient             solana.PublicKey
+	ProtocolFeeRecipientTokenAccount solana.PublicKey
+
+	Version int
+
+	// V2
+	CoinCreator               solana.PublicKey
+	CoinCreatorFeeBasisPoints uint64
+	CoinCreatorFee            uint64
+	// V3
+	TrackVolume          bool
+	TotalUnclaimedTokens uint64
+	TotalClaimedTokens   uint64
+	CurrentSolVolume     uint64
+	LastUpdateTimestamp  int64
+	// V4
+	MinBaseAmountOut uint64
+	IxName           string
+
+	BaseMint          solana.PublicKey
+	QuoteMint         solana.PublicKey
+	BaseMintDecimals  uint8
+	QuoteMintDecimals uint8
+}
+
+// PumpfunAMMSellEvent 是 PumpSwap 卖出 base 代币时发出的事件，费用以 quote 代币计。
+// BaseMint 之后的字段不在事件中，取自池子的指令账户和代币余额
+type PumpfunAMMSellEvent struct {
+	Timestamp                        int64
+	BaseAmountIn                     uint64
+	MinQuoteAmountOut                uint64
+	UserBaseTokenReserves            uint64
+	UserQuoteTokenReserves           uint64
+	PoolBaseTokenReserves            uint64
+	PoolQuoteTokenReserves           uint64
+	QuoteAmountOut                   uint64
+	LpFeeBasisPoints                 uint64
+	LpFee                            uint64
+	ProtocolFeeBasisPoints           uint64
+	ProtocolFee                      uint64
+	QuoteAmountOutWithoutLpFee       uint64
+	UserQuoteAmountOut               uint64 // 用户实际收到的 quote 数量，已扣除全部费用
+	Pool                             solana.PublicKey
+	User                             solana.PublicKey
+	UserBaseTokenAccount             solana.PublicKey
+	UserQuoteTokenAccount            solana.PublicKey
+	ProtocolFeeRecipient             solana.PublicKey
+	ProtocolFeeRecipientTokenAccount solana.PublicKey
+
+	Version int
+
+	// V2
+	CoinCreator               solana.PublicKey
+	CoinCreatorFeeBasisPoints uint64
+	CoinCreatorFee            uint64
+
+	BaseMint          solana.PublicKey
+	QuoteMint         solana.PublicKey
+	BaseMintDecimals  uint8
+	QuoteMintDecimals uint8
+}
+
+// pumpfunAMMPoolMints 是 PumpSwap buy / sell 指令中 base 和 quote mint 的账户位置（第 0 个为池子）
+const (
+	pumpfunAMMBaseMintIndex  = 3
+	pumpfunAMMQuoteMintIndex = 4
+)
+
+func (p *Parser) processPumpfunAMMSwaps(instructionIndex int) []SwapData {
+	var (
+		swaps     []SwapData
+		transfers []SwapData
+	)
+	instructions := p.getInnerInstructions(instructionIndex)
+	for _, innerInstruction := range instructions {
+		switch {
+		case p.isProgramDataInstruction(innerInstruction, PUMPFUN_AMM_PROGRAM_ID, PumpfunAMMBuyEventDiscriminator[:]):
+			event, err := p.parsePumpfunAMMBuyEventInstruction(innerInstruction)
+			if err != nil {
+				p.recordDecodeError(PUMPFUN_AMM_PROGRAM_ID, err)
+				continue
+			}
+			event.BaseMint, event.QuoteMint = p.pumpfunAMMPoolMints(instructionIndex, event.Pool, event.UserBaseTokenAccount, event.UserQuoteTokenAccount)
+			event.BaseMintDecimals, event.QuoteMintDecimals = p.splDecimalsMap[event.BaseMint], p.splDecimalsMap[event.QuoteMint]
+			swaps = append(swaps, SwapData{Type: PUMP_SWAP, Data: event})
+		case p.isProgramDataInstruction(innerInstruction, PUMPFUN_AMM_PROGRAM_ID, PumpfunAMMSellEventDiscriminator[:]):
+			event, err := p.parsePumpfunAMMSellEventInstruction(innerInstruction)
+			if err != nil {
+				p.recordDecodeError(PUMPFUN_AMM_PROGRAM_ID, err)
+				continue
+			}
+			event.BaseMint, event.QuoteMint = p.pumpfunAMMPoolMints(instructionIndex, event.Pool, event.UserBaseTokenAccount, event.UserQuoteTokenAccount)
+			event.BaseMintDecimals, event.QuoteMintDecimals = p.splDecimalsMap[event.BaseMint], p.splDecimalsMap[event.QuoteMint]
+			swaps = append(swaps, SwapData{Type: PUMP_SWAP, Data: event})
+		case p.isTransferCheck(innerInstruction):
+			transfer := p.processTransferCheck(innerInstruction)
+			if transfer != nil {
+				transfers = append(transfers, SwapData{Type: PUMP_SWAP, Data: transfer})
+			}
+		case p.isTransfer(innerInstruction):
+			transfer := p.processTransfer(innerInstruction)
+			if transfer != nil {
+				transfers = append(transfers, SwapData{Type: PUMP_SWAP, Data: transfer})
+			}
+		}
+	}
+
+	// 没有事件的旧交易退回到按转账解析
+	if len(swaps) == 0 {
+		return transfers
+	}
+	return swaps
+}
+
+func (p *Parser) parsePumpfunAMMBuyEventInstruction(instruction solana.CompiledInstruction) (*PumpfunAMMBuyEvent, error) {
+	decoder := ag_binary.NewBorshDecoder(instruction.Data[16:])
+
+	var e PumpfunAMMBuyEvent
+	version, err := decodeLayouts(decoder, [][]interface{}{
+		{&e.Timestamp, &e.BaseAmountOut, &e.MaxQuoteAmountIn, &e.UserBaseTokenReserves, &e.UserQuoteTokenReserves, &e.PoolBaseTokenReserves,
+			&e.PoolQuoteTokenReserves, &e.QuoteAmountIn, &e.LpFeeBasisPoints, &e.LpFee, &e.ProtocolFeeBasisPoints, &e.ProtocolFee,
+			&e.QuoteAmountInWithLpFee, &e.UserQuoteAmountIn, &e.Pool, &e.User, &e.UserBaseTokenAccount, &e.UserQuoteTokenAccount,
+			&e.ProtocolFeeRecipient, &e.ProtocolFeeRecipientTokenAccount},
+		{&e.CoinCreator, &e.CoinCreatorFeeBasisPoints, &e.CoinCreatorFee},
+		{&e.TrackVolume, &e.TotalUnclaimedTokens, &e.TotalClaimedTokens, &e.CurrentSolVolume, &e.LastUpdateTimestamp},
+		{&e.MinBaseAmountOut, &e.IxName},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling BuyEvent: %s", err)
+	}
+	e.Version = version
+
+	return &e, nil
+}
+
+func (p *Parser) parsePumpfunAMMSellEventInstruction(instruction solana.CompiledInstruction) (*PumpfunAMMSellEvent, error) {
+	decoder := ag_binary.NewBorshDecoder(instruction.Data[16:])
+
+	var e PumpfunAMMSellEvent
+	version, err := decodeLayouts(decoder, [][]interface{}{
+		{&e.Timestamp, &e.BaseAmountIn, &e.MinQuoteAmountOut, &e.UserBaseTokenReserves, &e.UserQuoteTokenReserves, &e.PoolBaseTokenReserves,
+			&e.PoolQuoteTokenReserves, &e.QuoteAmountOut, &e.LpFeeBasisPoints, &e.LpFee, &e.ProtocolFeeBasisPoints, &e.ProtocolFee,
+			&e.QuoteAmountOutWithoutLpFee, &e.UserQuoteAmountOut, &e.Pool, &e.User, &e.UserBaseTokenAccount, &e.UserQuoteTokenAccount,
+			&e.ProtocolFeeRecipient, &e.ProtocolFeeRecipientTokenAccount},
+		{&e.CoinCreator, &e.CoinCreatorFeeBasisPoints, &e.CoinCreatorFee},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling SellEvent: %s", err)
+	}
+	e.Version = version
+
+	return &e, nil
+}
+
+// pumpfunAMMPoolMints 返回池子的 base 和 quote mint：优先取自同一外层指令下调用该池子的 PumpSwap 指令，
+// 找不到时取自用户代币账户的余额记录
+func (p *Parser) pumpfunAMMPoolMints(instructionIndex int, pool, userBase, userQuote solana.PublicKey) (solana.PublicKey, solana.PublicKey) {
+	instructions := append([]solana.CompiledInstruction{p.txInfo.Message.Instructions[instructionIndex]}, p.getInnerInstructions(instructionIndex)...)
+	for _, instruction := range instructions {
+		if int(instruction.ProgramIDIndex) >= len(p.allAccountKeys) || !p.allAccountKeys[instruction.ProgramIDIndex].Equals(PUMPFUN_AMM_PROGRAM_ID) {
+			continue
+		}
+		if len(instruction.Accounts) > pumpfunAMMQuoteMintIndex && p.accountAt(instruction, 0).Equals(pool) {
+			return p.accountAt(instruction, pumpfunAMMBaseMintIndex), p.accountAt(instruction, pumpfunAMMQuoteMintIndex)
+		}
+	}
+
+	var base, quote solana.PublicKey
+	if info, ok := p.splTokenInfoMap[userBase]; ok {
+		base, _ = solana.PublicKeyFromBase58(info.Mint)
+	}
+	if info, ok := p.splTokenInfoMap[userQuote]; ok {
+		quote, _ = solana.PublicKeyFromBase58(info.Mint)
+	}
+	return base, quote
+}
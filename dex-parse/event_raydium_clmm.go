@@ -0,0 +1,154 @@
+package solanaswapgo
+
+import (
+	"fmt"
+	"math"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// Raydium CLMM 交换指令和事件的判别器。CLMM 和 CPMM 的 SwapEvent 同名，判别器相同
+var (
+	RaydiumCLMMSwapDiscriminator   = [8]byte{248, 198, 158, 145, 225, 117, 135, 200}
+	RaydiumCLMMSwapV2Discriminator = [8]byte{43, 4, 237, 11, 26, 201, 30, 98}
+	RaydiumSwapEventDiscriminator  = [8]byte{64, 198, 205, 232, 38, 8, 113, 226}
+)
+
+// swap / swap_v2 指令中的账户位置。swap_v2 在 observation 之后依次为 token_program、token_program_2022、memo 和两个 mint
+const (
+	raydiumCLMMPoolIndex        = 2
+	raydiumCLMMInputVaultIndex  = 5
+	raydiumCLMMOutputVaultIndex = 6
+	raydiumCLMMInputMintIndex   = 11
+	raydiumCLMMOutputMintIndex  = 12
+)
+
+// RaydiumCLMMSwapEvent 是 Raydium CLMM 在日志中输出的 SwapEvent。Amount0 和 Amount1 为池子收到和付出的数量，
+// Token-2022 的转账费用另计：用户实际支付输入数量加输入转账费用，实际收到输出数量减输出转账费用。
+// SqrtPriceX64、Liquidity 和 Tick 为交换后的池子状态
+type RaydiumCLMMSwapEvent struct {
+	PoolState     solana.PublicKey
+	Sender        solana.PublicKey
+	TokenAccount0 solana.PublicKey
+	TokenAccount1 solana.PublicKey
+	Amount0       uint64
+	TransferFee0  uint64
+	Amount1       uint64
+	TransferFee1  uint64
+	ZeroForOne    bool
+	SqrtPriceX64  ag_binary.Uint128
+	Liquidity     ag_binary.Uint128
+	Tick          int32
+
+	// 以下字段不在事件中，取自 swap / swap_v2 指令参数和账户
+	Amount               uint64            `bin:"-"` // IsBaseInput 时为输入数量，否则为输出数量
+	OtherAmountThreshold uint64            `bin:"-"` // IsBaseInput 时为最少输出，否则为最多输入
+	SqrtPriceLimitX64    ag_binary.Uint128 `bin:"-"`
+	IsBaseInput          bool              `bin:"-"`
+	Mint0                solana.PublicKey  `bin:"-"`
+	Decimals0            uint8             `bin:"-"`
+	Mint1                solana.PublicKey  `bin:"-"`
+	Decimals1            uint8             `bin:"-"`
+	// Price 为交换后 1 个 token0 以 token1 计的价格，已按两个代币的精度换算
+	Price float64 `bin:"-"`
+}
+
+// raydiumCLMMSwapInstructionData 是 swap 和 swap_v2 共用的指令参数
+type raydiumCLMMSwapInstructionData struct {
+	Amount               uint64
+	OtherAmountThreshold uint64
+	SqrtPriceLimitX64    ag_binary.Uint128
+	IsBaseInput          bool
+}
+
+// isRaydiumCLMMSwapInstruction 判断指令是否为 Raydium CLMM 的 swap 或 swap_v2，返回是否为 swap_v2
+func (p *Parser) isRaydiumCLMMSwapInstruction(instruction solana.CompiledInstruction) (bool, bool) {
+	switch {
+	case p.isProgramDataInstruction(instruction, RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID, RaydiumCLMMSwapV2Discriminator[:]):
+		return true, true
+	case p.isProgramDataInstruction(instruction, RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID, RaydiumCLMMSwapDiscriminator[:]):
+		return true, false
+	}
+	return false, false
+}
+
+// parseRaydiumCLMMSwap 解析第 instructionIndex 个外层指令（innerIndex 为 -1）或其内部指令中的 Raydium CLMM 交换，
+// 数量取自该指令在日志中输出的 SwapEvent。没有 SwapEvent（如日志被截断或未获取日志）时返回 nil
+func (p *Parser) parseRaydiumCLMMSwap(instructionIndex, innerIndex int, instruction solana.CompiledInstruction, v2 bool) (*RaydiumCLMMSwapEvent, error) {
+	events := programDataEvents(p.instructionLogs(instructionIndex, innerIndex), RaydiumSwapEventDiscriminator[:])
+	if len(events) == 0 {
+		return nil, nil
+	}
+	if len(events) > 1 {
+		return nil, fmt.Errorf("error parsing raydium clmm swap: expected 1 SwapEvent, found %d", len(events))
+	}
+
+	var args raydiumCLMMSwapInstructionData
+	if err := ag_binary.NewBorshDecoder(instruction.Data[8:]).Decode(&args); err != nil {
+		return nil, fmt.Errorf("error decoding raydium clmm swap instruction: %s", err)
+	}
+	var event RaydiumCLMMSwapEvent
+	if err := ag_binary.NewBorshDecoder(events[0]).Decode(&event); err != nil {
+		return nil, fmt.Errorf("error decoding raydium clmm SwapEvent: %s", err)
+	}
+	if !event.PoolState.Equals(p.accountAt(instruction, raydiumCLMMPoolIndex)) {
+		return nil, fmt.Errorf("error parsing raydium clmm swap: SwapEvent pool %s does not match instruction", event.PoolState)
+	}
+	if event.TransferFee0 > event.Amount0 || event.TransferFee1 > event.Amount1 {
+		return nil, fmt.Errorf("error parsing raydium clmm swap: SwapEvent transfer fee exceeds amount")
+	}
+	event.Amount = args.Amount
+	event.OtherAmountThreshold = args.OtherAmountThreshold
+	event.SqrtPriceLimitX64 = args.SqrtPriceLimitX64
+	event.IsBaseInput = args.IsBaseInput
+
+	// swap_v2 的指令账户中有两个 mint，swap 只能取自池子代币账户的余额记录
+	var in, out TokenInfo
+	if v2 && len(instruction.Accounts) > raydiumCLMMOutputMintIndex {
+		inMint, outMint := p.accountAt(instruction, raydiumCLMMInputMintIndex), p.accountAt(instruction, raydiumCLMMOutputMintIndex)
+		in = TokenInfo{Mint: inMint.String(), Decimals: p.splDecimalsMap[inMint]}
+		out = TokenInfo{Mint: outMint.String(), Decimals: p.splDecimalsMap[outMint]}
+	} else {
+		in = p.splTokenInfoMap[p.accountAt(instruction, raydiumCLMMInputVaultIndex)]
+		out = p.splTokenInfoMap[p.accountAt(instruction, raydiumCLMMOutputVaultIndex)]
+	}
+	token0, token1 := in, out
+	if !event.ZeroForOne {
+		token0, token1 = out, in
+	}
+	mint0, err := solana.PublicKeyFromBase58(token0.Mint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing raydium clmm token0 mint: %s", err)
+	}
+	mint1, err := solana.PublicKeyFromBase58(token1.Mint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing raydium clmm token1 mint: %s", err)
+	}
+	event.Mint0, event.Decimals0 = mint0, token0.Decimals
+	event.Mint1, event.Decimals1 = mint1, token1.Decimals
+	event.Price = clmmPrice(event.SqrtPriceX64, event.Decimals0, event.Decimals1)
+	return &event, nil
+}
+
+// Input 返回用户的输入代币、实际支付的数量（含转账费用）和精度
+func (e *RaydiumCLMMSwapEvent) Input() (solana.PublicKey, uint64, uint8) {
+	if e.ZeroForOne {
+		return e.Mint0, e.Amount0 + e.TransferFee0, e.Decimals0
+	}
+	return e.Mint1, e.Amount1 + e.TransferFee1, e.Decimals1
+}
+
+// Output 返回用户的输出代币、实际收到的数量（扣除转账费用）和精度
+func (e *RaydiumCLMMSwapEvent) Output() (solana.PublicKey, uint64, uint8) {
+	if e.ZeroForOne {
+		return e.Mint1, e.Amount1 - e.TransferFee1, e.Decimals1
+	}
+	return e.Mint0, e.Amount0 - e.TransferFee0, e.Decimals0
+}
+
+// clmmPrice 将 Q64.64 格式的 sqrt 价格换算为 1 个 token0 以 token1 计的价格
+func clmmPrice(sqrtPriceX64 ag_binary.Uint128, decimals0, decimals1 uint8) float64 {
+	sqrtPrice := (float64(sqrtPriceX64.Hi)*math.Pow(2, 64) + float64(sqrtPriceX64.Lo)) / math.Pow(2, 64)
+	return sqrtPrice * sqrtPrice * math.Pow10(int(decimals0)-int(decimals1))
+}
@@ -0,0 +1,157 @@
+package solanaswapgo
+
+import (
+	"fmt"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// Raydium CPMM 交换指令的判别器，SwapEvent 的判别器见 RaydiumSwapEventDiscriminator
+var (
+	RaydiumCPMMSwapBaseInputDiscriminator  = [8]byte{143, 190, 90, 218, 196, 30, 51, 222}
+	RaydiumCPMMSwapBaseOutputDiscriminator = [8]byte{55, 217, 98, 86, 163, 74, 180, 173}
+)
+
+// Raydium CPMM SwapEvent 的布局版本，只在末尾追加字段
+const (
+	RaydiumCPMMSwapEventV1 = iota + 1 // 池子、交换前的金库余额、数量和转账费用
+	RaydiumCPMMSwapEventV2            // 追加两个 mint、交易费用和创作者费用
+)
+
+// swap_base_input / swap_base_output 指令中的账户位置
+const (
+	raydiumCPMMPoolIndex               = 3
+	raydiumCPMMInputTokenProgramIndex  = 8
+	raydiumCPMMOutputTokenProgramIndex = 9
+	raydiumCPMMInputMintIndex          = 10
+	raydiumCPMMOutputMintIndex         = 11
+)
+
+// RaydiumCPMMSwapEvent 是 Raydium CPMM 在日志中输出的 SwapEvent。InputAmount 和 OutputAmount 为池子收到和付出的数量，
+// Token-2022 的转账费用另计：用户实际支付 InputAmount 加 InputTransferFee，实际收到 OutputAmount 减 OutputTransferFee
+type RaydiumCPMMSwapEvent struct {
+	Version int
+
+	PoolId            solana.PublicKey
+	InputVaultBefore  uint64
+	OutputVaultBefore uint64
+	InputAmount       uint64
+	OutputAmount      uint64
+	InputTransferFee  uint64
+	OutputTransferFee uint64
+	BaseInput         bool
+
+	// V2
+	InputMint         solana.PublicKey
+	OutputMint        solana.PublicKey
+	TradeFee          uint64 // 以输入代币计，包含 LP、协议和基金的份额
+	CreatorFee        uint64
+	CreatorFeeOnInput bool // CreatorFee 以输入代币计，否则以输出代币计，见 InputCreatorFee 和 OutputCreatorFee
+
+	// 以下字段不在事件中，取自指令参数和账户。V1 事件的 InputMint 和 OutputMint 也取自指令账户
+	User               solana.PublicKey
+	MinimumAmountOut   uint64 // swap_base_input 的滑点限制
+	MaxAmountIn        uint64 // swap_base_output 的滑点限制
+	InputTokenProgram  solana.PublicKey
+	OutputTokenProgram solana.PublicKey
+	InputDecimals      uint8
+	OutputDecimals     uint8
+}
+
+// raydiumCPMMSwapInstructionData 是交换指令判别器之后的参数。
+// swap_base_input 依次为 amount_in 和 minimum_amount_out，swap_base_output 依次为 max_amount_in 和 amount_out
+type raydiumCPMMSwapInstructionData struct {
+	First  uint64
+	Second uint64
+}
+
+// isRaydiumCPMMSwapInstruction 判断指令是否为 Raydium CPMM 的 swap_base_input 或 swap_base_output
+func (p *Parser) isRaydiumCPMMSwapInstruction(instruction solana.CompiledInstruction) bool {
+	return p.isProgramDataInstruction(instruction, RAYDIUM_CPMM_PROGRAM_ID, RaydiumCPMMSwapBaseInputDiscriminator[:]) ||
+		p.isProgramDataInstruction(instruction, RAYDIUM_CPMM_PROGRAM_ID, RaydiumCPMMSwapBaseOutputDiscriminator[:])
+}
+
+// parseRaydiumCPMMSwap 解析第 instructionIndex 个外层指令（innerIndex 为 -1）或其内部指令中的 Raydium CPMM 交换，
+// 数量取自该指令在日志中输出的 SwapEvent。没有 SwapEvent（如日志被截断或未获取日志）时返回 nil
+func (p *Parser) parseRaydiumCPMMSwap(instructionIndex, innerIndex int, instruction solana.CompiledInstruction) (*RaydiumCPMMSwapEvent, error) {
+	events := programDataEvents(p.instructionLogs(instructionIndex, innerIndex), RaydiumSwapEventDiscriminator[:])
+	if len(events) == 0 {
+		return nil, nil
+	}
+	if len(events) > 1 {
+		return nil, fmt.Errorf("error parsing raydium cpmm swap: expected 1 SwapEvent, found %d", len(events))
+	}
+	if len(instruction.Accounts) <= raydiumCPMMOutputMintIndex {
+		return nil, fmt.Errorf("error parsing raydium cpmm swap: unexpected account count %d", len(instruction.Accounts))
+	}
+
+	var args raydiumCPMMSwapInstructionData
+	if err := ag_binary.NewBorshDecoder(instruction.Data[8:]).Decode(&args); err != nil {
+		return nil, fmt.Errorf("error decoding raydium cpmm swap instruction: %s", err)
+	}
+
+	var event RaydiumCPMMSwapEvent
+	version, err := decodeLayouts(ag_binary.NewBorshDecoder(events[0]), [][]interface{}{
+		{&event.PoolId, &event.InputVaultBefore, &event.OutputVaultBefore, &event.InputAmount, &event.OutputAmount,
+			&event.InputTransferFee, &event.OutputTransferFee, &event.BaseInput},
+		{&event.InputMint, &event.OutputMint, &event.TradeFee, &event.CreatorFee, &event.CreatorFeeOnInput},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling raydium cpmm SwapEvent: %s", err)
+	}
+	event.Version = version
+
+	if !event.PoolId.Equals(p.accountAt(instruction, raydiumCPMMPoolIndex)) {
+		return nil, fmt.Errorf("error parsing raydium cpmm swap: SwapEvent pool %s does not match instruction", event.PoolId)
+	}
+	if event.OutputTransferFee > event.OutputAmount {
+		return nil, fmt.Errorf("error parsing raydium cpmm swap: SwapEvent output transfer fee exceeds amount")
+	}
+	inputMint, outputMint := p.accountAt(instruction, raydiumCPMMInputMintIndex), p.accountAt(instruction, raydiumCPMMOutputMintIndex)
+	if version >= RaydiumCPMMSwapEventV2 && (!event.InputMint.Equals(inputMint) || !event.OutputMint.Equals(outputMint)) {
+		return nil, fmt.Errorf("error parsing raydium cpmm swap: SwapEvent mints do not match instruction")
+	}
+	baseInput := hasDiscriminator(instruction.Data, RaydiumCPMMSwapBaseInputDiscriminator[:])
+	if event.BaseInput != baseInput {
+		return nil, fmt.Errorf("error parsing raydium cpmm swap: SwapEvent base_input does not match instruction")
+	}
+
+	event.User = p.accountAt(instruction, 0)
+	if baseInput {
+		event.MinimumAmountOut = args.Second
+	} else {
+		event.MaxAmountIn = args.First
+	}
+	event.InputMint, event.OutputMint = inputMint, outputMint
+	event.InputTokenProgram = p.accountAt(instruction, raydiumCPMMInputTokenProgramIndex)
+	event.OutputTokenProgram = p.accountAt(instruction, raydiumCPMMOutputTokenProgramIndex)
+	event.InputDecimals, event.OutputDecimals = p.splDecimalsMap[inputMint], p.splDecimalsMap[outputMint]
+	return &event, nil
+}
+
+// Input 返回用户的输入代币、实际支付的数量（含转账费用）和精度
+func (e *RaydiumCPMMSwapEvent) Input() (solana.PublicKey, uint64, uint8) {
+	return e.InputMint, e.InputAmount + e.InputTransferFee, e.InputDecimals
+}
+
+// Output 返回用户的输出代币、实际收到的数量（扣除转账费用）和精度
+func (e *RaydiumCPMMSwapEvent) Output() (solana.PublicKey, uint64, uint8) {
+	return e.OutputMint, e.OutputAmount - e.OutputTransferFee, e.OutputDecimals
+}
+
+// InputCreatorFee 返回以输入代币计的创作者费用，计入 SwapInfo.Fees.CreatorFee
+func (e *RaydiumCPMMSwapEvent) InputCreatorFee() uint64 {
+	if e.CreatorFeeOnInput {
+		return e.CreatorFee
+	}
+	return 0
+}
+
+// OutputCreatorFee 返回以输出代币计的创作者费用。SwapInfo.Fees 只使用一个代币，不包含这部分费用
+func (e *RaydiumCPMMSwapEvent) OutputCreatorFee() uint64 {
+	if e.CreatorFeeOnInput {
+		return 0
+	}
+	return e.CreatorFee
+}
@@ -1,300 +1,390 @@
 package solanaswapgo
 
 import (
-	"bytes"
 	"fmt"
+	"strconv"
 
 	ag_binary "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
-	"github.com/mr-tron/base58"
 )
 
-// Raydium LaunchLab 事件判别器
+// Raydium LaunchLab 交易指令的判别器
 var (
-	RaydiumLaunchLabBuyEventDiscriminator  = [8]byte{102, 230, 103, 87, 137, 106, 134, 187} // buy_exact_in 指令的判别器
-	RaydiumLaunchLabSellEventDiscriminator = [8]byte{149, 39, 222, 155, 211, 124, 152, 26}  // sell_exact_in 指令的判别器
+	RaydiumLaunchLabBuyExactInDiscriminator   = [8]byte{250, 234, 13, 123, 213, 156, 19, 236}
+	RaydiumLaunchLabBuyExactOutDiscriminator  = [8]byte{24, 211, 116, 40, 105, 3, 153, 56}
+	RaydiumLaunchLabSellExactInDiscriminator  = [8]byte{149, 39, 222, 155, 211, 124, 152, 26}
+	RaydiumLaunchLabSellExactOutDiscriminator = [8]byte{95, 200, 71, 34, 8, 9, 11, 166}
+
+	// 创建池子的指令判别器，三者的参数都以 MintParams 开头
+	RaydiumLaunchLabInitializeDiscriminator              = [8]byte{175, 175, 109, 31, 13, 152, 155, 237}
+	RaydiumLaunchLabInitializeV2Discriminator            = [8]byte{67, 153, 175, 39, 218, 16, 38, 32}
+	RaydiumLaunchLabInitializeWithToken2022Discriminator = [8]byte{37, 190, 126, 222, 44, 154, 171, 17}
+
+	// RaydiumLaunchLabTradeEventDiscriminator 是 TradeEvent 通过 CPI 发出时的判别器，与 Pump.fun 的 TradeEvent 同名
+	RaydiumLaunchLabTradeEventDiscriminator = [16]byte{228, 69, 165, 46, 81, 203, 154, 29, 189, 219, 127, 211, 78, 230, 97, 238}
 )
 
-// RaydiumLaunchLabBuyEvent 表示 Raydium LaunchLab 的买入事件
-type RaydiumLaunchLabBuyEvent struct {
-	PoolState        solana.PublicKey
-	TotalBaseSell    uint64
-	VirtualBase      uint64
-	VirtualQuote     uint64
-	RealBaseBefore   uint64
-	RealQuoteBefore  uint64
-	RealBaseAfter    uint64
-	RealQuoteAfter   uint64
-	AmountIn         uint64
-	AmountOut        uint64
-	MinimumAmountOut uint64
-	ProtocolFee      uint64
-	PlatformFee      uint64
-	ShareFee         uint64
-	TradeDirection   TradeDirection
-	PoolStatus       PoolStatus
-	TokenMint        solana.PublicKey
-	TokenDecimals    uint8
-	IsBuy            bool // 标识是买入还是卖出
+// Raydium LaunchLab TradeEvent 的布局版本，版本由事件数据的长度决定
+const (
+	RaydiumLaunchLabTradeEventV1 = 1 // 最初的字段
+	RaydiumLaunchLabTradeEventV2 = 2 // 在 ShareFee 之前插入 CreatorFee，末尾追加 ExactIn
+)
+
+// raydiumLaunchLabTradeEventV1Size 是 V1 事件去掉判别器后的长度
+const raydiumLaunchLabTradeEventV1Size = 32 + 12*8 + 2
+
+// 指令中的账户位置。platform_config 在交易和创建池子的指令中位置相同
+const (
+	raydiumLaunchLabPlatformConfigIndex = 3
+
+	// buy_exact_in / buy_exact_out / sell_exact_in / sell_exact_out
+	raydiumLaunchLabPoolIndex      = 4
+	raydiumLaunchLabBaseMintIndex  = 9
+	raydiumLaunchLabQuoteMintIndex = 10
+	// 支持创作者费用后，交易指令在 program 之后追加 system_program、platform_fee_vault 和 creator_fee_vault
+	raydiumLaunchLabPlatformFeeVaultIndex = 16
+
+	// initialize / initialize_v2 / initialize_with_token_2022
+	raydiumLaunchLabCreatorIndex        = 1
+	raydiumLaunchLabInitializePoolIndex = 5
+	raydiumLaunchLabInitializeMintIndex = 6
+)
+
+// RaydiumLaunchLabTradeDirection 是 TradeEvent 中的交易方向
+type RaydiumLaunchLabTradeDirection uint8
+
+const (
+	RaydiumLaunchLabBuy  RaydiumLaunchLabTradeDirection = iota // 用 quote 代币买入 base 代币
+	RaydiumLaunchLabSell                                       // 卖出 base 代币换回 quote 代币
+)
+
+// RaydiumLaunchLabPoolStatus 是交易后的池子状态
+type RaydiumLaunchLabPoolStatus uint8
+
+const (
+	RaydiumLaunchLabPoolFund    RaydiumLaunchLabPoolStatus = iota // 募集中
+	RaydiumLaunchLabPoolMigrate                                   // 募集完成，等待迁移
+	RaydiumLaunchLabPoolTrade                                     // 已迁移
+)
+
+// RaydiumLaunchLabTradeEvent 是 Raydium LaunchLab 交易时发出的 TradeEvent。AmountIn 为用户支付的数量，AmountOut 为用户收到的数量，
+// 费用均以 quote 代币计：买入时从 AmountIn 中扣除，卖出时已从 AmountOut 中扣除
+type RaydiumLaunchLabTradeEvent struct {
+	PoolState       solana.PublicKey
+	TotalBaseSell   uint64
+	VirtualBase     uint64
+	VirtualQuote    uint64
+	RealBaseBefore  uint64
+	RealQuoteBefore uint64
+	RealBaseAfter   uint64
+	RealQuoteAfter  uint64
+	AmountIn        uint64
+	AmountOut       uint64
+	ProtocolFee     uint64
+	PlatformFee     uint64
+	CreatorFee      uint64 // V2
+	ShareFee        uint64
+	TradeDirection  RaydiumLaunchLabTradeDirection
+	PoolStatus      RaydiumLaunchLabPoolStatus
+	ExactIn         bool // V2，V1 事件取自指令
+
+	// Version 为 0 表示没有 TradeEvent，事件字段由指令参数和转账补全
+	Version int
+
+	// 以下字段不在事件中，取自指令参数和账户
+	User             solana.PublicKey
+	PlatformConfig   solana.PublicKey
+	Platform         string // 按 LaunchLabPlatforms 识别的发射平台，未识别时为空
+	MinimumAmountOut uint64 // exact_in 的滑点限制
+	MaximumAmountIn  uint64 // exact_out 的滑点限制
+	ShareFeeRate     uint64
+	BaseMint         solana.PublicKey
+	QuoteMint        solana.PublicKey
+	BaseDecimals     uint8
+	QuoteDecimals    uint8
 }
 
-// TradeDirection 交易方向
-type TradeDirection struct {
-	IsBuy bool
+// RaydiumLaunchLabInstructionData 是交易指令判别器之后的参数。
+// exact_in 依次为 amount_in 和 minimum_amount_out，exact_out 依次为 amount_out 和 maximum_amount_in
+type RaydiumLaunchLabInstructionData struct {
+	Amount       uint64
+	OtherAmount  uint64
+	ShareFeeRate uint64
 }
 
-// PoolStatus 池状态
-type PoolStatus struct {
-	IsFund bool
+// raydiumLaunchLabMintParams 是创建池子指令的第一个参数
+type raydiumLaunchLabMintParams struct {
+	Decimals uint8
+	Name     string
+	Symbol   string
+	Uri      string
 }
 
-// RaydiumLaunchLabInstructionData 指令数据结构
-type RaydiumLaunchLabInstructionData struct {
-	AmountIn         uint64
-	MinimumAmountOut uint64
-	ShareFeeRate     uint64
+// raydiumLaunchLabTradeInstruction 返回交易指令的方向和是否为 exact_in，不是交易指令时 ok 为 false
+func raydiumLaunchLabTradeInstruction(data []byte) (direction RaydiumLaunchLabTradeDirection, exactIn bool, ok bool) {
+	switch {
+	case hasDiscriminator(data, RaydiumLaunchLabBuyExactInDiscriminator[:]):
+		return RaydiumLaunchLabBuy, true, true
+	case hasDiscriminator(data, RaydiumLaunchLabBuyExactOutDiscriminator[:]):
+		return RaydiumLaunchLabBuy, false, true
+	case hasDiscriminator(data, RaydiumLaunchLabSellExactInDiscriminator[:]):
+		return RaydiumLaunchLabSell, true, true
+	case hasDiscriminator(data, RaydiumLaunchLabSellExactOutDiscriminator[:]):
+		return RaydiumLaunchLabSell, false, true
+	}
+	return 0, false, false
 }
 
-// processRaydiumLaunchLabSwaps 处理 Raydium LaunchLab 交换
+// processRaydiumLaunchLabSwaps 处理第 instructionIndex 个外层指令及其内部指令中的 Raydium LaunchLab 交易，
+// 外层为路由等其他程序时也能找到通过 CPI 调用的交易。每个交易指令的数量和费用取自它之后的 TradeEvent，
+// 没有 TradeEvent 时由指令参数和转账补全，都没有交易指令时按转账解析
 func (p *Parser) processRaydiumLaunchLabSwaps(instructionIndex int) []SwapData {
-	var swaps []SwapData
+	instructions := append([]solana.CompiledInstruction{p.txInfo.Message.Instructions[instructionIndex]}, p.getInnerInstructions(instructionIndex)...)
 
-	// 首先尝试解析主指令数据
-	mainInstruction := p.txInfo.Message.Instructions[instructionIndex]
-	instructionData, isBuy, err := p.parseRaydiumLaunchLabInstruction(mainInstruction)
-	if err == nil && instructionData != nil {
-		// 创建基于指令数据的事件
-		event := &RaydiumLaunchLabBuyEvent{
-			AmountIn:         instructionData.AmountIn,
-			MinimumAmountOut: instructionData.MinimumAmountOut,
-			ShareFee:         instructionData.ShareFeeRate,
-			IsBuy:            isBuy,
+	var trades []int
+	for i, instruction := range instructions {
+		if p.isRaydiumLaunchLabTradeInstruction(instruction) {
+			trades = append(trades, i)
 		}
-
-		// 从转账记录中获取实际的输出金额和代币信息
-		p.enrichRaydiumLaunchLabEventFromTransfers(event, instructionIndex)
-
-		swaps = append(swaps, SwapData{Type: RAYDIUM_LAUNCHLAB, Data: event})
-		return swaps
 	}
-
-	// 如果主指令解析失败，尝试从内部指令解析事件
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				if p.isRaydiumLaunchLabEvent(innerInstruction) {
-					eventData, err := p.parseRaydiumLaunchLabEvent(innerInstruction)
-					if err != nil {
-						p.Log.Errorf("error processing Raydium LaunchLab event: %s", err)
-						continue
-					}
-					if eventData != nil {
-						// 获取代币信息并设置到事件中
-						p.enrichRaydiumLaunchLabEvent(eventData, instructionIndex)
-						swaps = append(swaps, SwapData{Type: RAYDIUM_LAUNCHLAB, Data: eventData})
-					}
-				}
-			}
-		}
+	if len(trades) == 0 {
+		return p.processRaydiumLaunchLabTransfers(instructions[1:], "")
 	}
 
-	// 如果没有找到事件数据，尝试使用转账数据进行解析
-	if len(swaps) == 0 {
-		return p.processRaydiumLaunchLabTransfers(instructionIndex)
+	var swaps []SwapData
+	for n, i := range trades {
+		// 交易指令的 TradeEvent 和转账在它之后、下一个交易指令之前
+		end := len(instructions)
+		if n+1 < len(trades) {
+			end = trades[n+1]
+		}
+		swaps = append(swaps, p.processRaydiumLaunchLabTrade(instructions[i], instructions[i+1:end])...)
 	}
-
 	return swaps
 }
 
-// enrichRaydiumLaunchLabEvent 从交易中获取代币信息并丰富事件数据
-func (p *Parser) enrichRaydiumLaunchLabEvent(event *RaydiumLaunchLabBuyEvent, instructionIndex int) {
-	// 从转账记录中获取代币信息
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				if p.isTransferCheck(innerInstruction) {
-					// 解析转账检查指令以获取代币 mint
-					if len(innerInstruction.Accounts) >= 3 {
-						mintIndex := innerInstruction.Accounts[2]
-						if int(mintIndex) < len(p.allAccountKeys) {
-							mint := p.allAccountKeys[mintIndex]
-							// 如果不是 SOL，则这应该是输出代币
-							if !mint.Equals(NATIVE_SOL_MINT_PROGRAM_ID) {
-								event.TokenMint = mint
-								if decimals, exists := p.splDecimalsMap[mint.String()]; exists {
-									event.TokenDecimals = decimals
-								} else {
-									event.TokenDecimals = 6 // 默认值
-								}
-								return
-							}
-						}
-					}
-				}
-			}
-		}
+// isRaydiumLaunchLabTradeInstruction 判断指令是否为 LaunchLab 的四个交易指令之一
+func (p *Parser) isRaydiumLaunchLabTradeInstruction(instruction solana.CompiledInstruction) bool {
+	if p.programKindAt(instruction.ProgramIDIndex) != programRaydiumLaunchLab {
+		return false
 	}
+	_, _, ok := raydiumLaunchLabTradeInstruction(instruction.Data)
+	return ok
 }
 
-// enrichRaydiumLaunchLabEventFromTransfers 从转账记录中获取实际的交换信息
-func (p *Parser) enrichRaydiumLaunchLabEventFromTransfers(event *RaydiumLaunchLabBuyEvent, instructionIndex int) {
-	var solAmount uint64
-	var tokenAmount uint64
-	var tokenMint solana.PublicKey
-	var tokenDecimals uint8 = 6
-
-	// 分析内部指令中的转账记录
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				if p.isTransferCheck(innerInstruction) {
-					transfer := p.processTransferCheck(innerInstruction)
-					if transfer != nil {
-						// 检查是否是 SOL 转账
-						if transfer.Info.Mint == NATIVE_SOL_MINT_PROGRAM_ID.String() {
-							solAmount, _ = parseUint64(transfer.Info.TokenAmount.Amount)
-						} else if transfer.Info.Mint != "Unknown" && transfer.Info.Mint != "" {
-							// 这应该是代币转账
-							tokenAmount, _ = parseUint64(transfer.Info.TokenAmount.Amount)
-							tokenMint = solana.MustPublicKeyFromBase58(transfer.Info.Mint)
-							tokenDecimals = transfer.Info.TokenAmount.Decimals
-						}
-					}
-				}
-			}
-		}
+// processRaydiumLaunchLabTrade 解析一个交易指令，inner 为它执行期间的内部指令
+func (p *Parser) processRaydiumLaunchLabTrade(instruction solana.CompiledInstruction, inner []solana.CompiledInstruction) []SwapData {
+	direction, exactIn, _ := raydiumLaunchLabTradeInstruction(instruction.Data)
+	platform := p.launchLabPlatform(
+		p.accountAt(instruction, raydiumLaunchLabPlatformConfigIndex),
+		p.accountAt(instruction, raydiumLaunchLabPlatformFeeVaultIndex),
+	)
+
+	event, err := p.parseRaydiumLaunchLabInstruction(instruction, direction, exactIn)
+	if err != nil {
+		p.recordDecodeError(RAYDIUM_LAUNCHLAB_PROGRAM_ID, err)
+		return p.processRaydiumLaunchLabTransfers(inner, platform)
 	}
+	event.Platform = platform
 
-	// 更新事件数据 - 根据买入/卖出方向正确设置输入输出
-	if event.IsBuy {
-		// 买入：SOL -> Token
-		if solAmount > 0 {
-			event.AmountIn = solAmount
-		}
-		if tokenAmount > 0 {
-			event.AmountOut = tokenAmount
-		}
-	} else {
-		// 卖出：Token -> SOL
-		if tokenAmount > 0 {
-			event.AmountIn = tokenAmount
+	for _, innerInstruction := range inner {
+		if !p.isProgramDataInstruction(innerInstruction, RAYDIUM_LAUNCHLAB_PROGRAM_ID, RaydiumLaunchLabTradeEventDiscriminator[:]) {
+			continue
 		}
-		if solAmount > 0 {
-			event.AmountOut = solAmount
+		if err := p.parseRaydiumLaunchLabTradeEvent(innerInstruction, event); err != nil {
+			p.recordDecodeError(RAYDIUM_LAUNCHLAB_PROGRAM_ID, err)
+			continue
 		}
+		return []SwapData{{Type: RAYDIUM_LAUNCHLAB, Data: event, Platform: platform}}
 	}
 
-	if !tokenMint.IsZero() {
-		event.TokenMint = tokenMint
-		event.TokenDecimals = tokenDecimals
+	// 没有 TradeEvent 时从转账记录中获取实际数量
+	if !p.enrichRaydiumLaunchLabEventFromTransfers(event, inner) {
+		return p.processRaydiumLaunchLabTransfers(inner, platform)
 	}
+	return []SwapData{{Type: RAYDIUM_LAUNCHLAB, Data: event, Platform: platform}}
 }
 
-// parseUint64 辅助函数，将字符串转换为 uint64
-func parseUint64(s string) (uint64, error) {
-	var result uint64
-	for _, c := range s {
-		if c < '0' || c > '9' {
-			break
-		}
-		result = result*10 + uint64(c-'0')
+// parseRaydiumLaunchLabInstruction 解析交易指令的参数和账户，返回尚未填入事件字段的 RaydiumLaunchLabTradeEvent
+func (p *Parser) parseRaydiumLaunchLabInstruction(instruction solana.CompiledInstruction, direction RaydiumLaunchLabTradeDirection, exactIn bool) (*RaydiumLaunchLabTradeEvent, error) {
+	if len(instruction.Accounts) <= raydiumLaunchLabQuoteMintIndex {
+		return nil, fmt.Errorf("error parsing raydium launchlab trade: unexpected account count %d", len(instruction.Accounts))
+	}
+	var args RaydiumLaunchLabInstructionData
+	if err := ag_binary.NewBorshDecoder(instruction.Data[8:]).Decode(&args); err != nil {
+		return nil, fmt.Errorf("error decoding raydium launchlab trade instruction: %s", err)
 	}
-	return result, nil
-}
-
-// processRaydiumLaunchLabTransfers 通过分析转账记录解析交换信息
-func (p *Parser) processRaydiumLaunchLabTransfers(instructionIndex int) []SwapData {
-	var swaps []SwapData
 
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				switch {
-				case p.isTransferCheck(innerInstruction):
-					transfer := p.processTransferCheck(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: RAYDIUM_LAUNCHLAB, Data: transfer})
-					}
-				case p.isTransfer(innerInstruction):
-					transfer := p.processTransfer(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: RAYDIUM_LAUNCHLAB, Data: transfer})
-					}
-				}
-			}
-		}
+	event := &RaydiumLaunchLabTradeEvent{
+		PoolState:      p.accountAt(instruction, raydiumLaunchLabPoolIndex),
+		TradeDirection: direction,
+		ExactIn:        exactIn,
+		User:           p.accountAt(instruction, 0),
+		PlatformConfig: p.accountAt(instruction, raydiumLaunchLabPlatformConfigIndex),
+		ShareFeeRate:   args.ShareFeeRate,
+		BaseMint:       p.accountAt(instruction, raydiumLaunchLabBaseMintIndex),
+		QuoteMint:      p.accountAt(instruction, raydiumLaunchLabQuoteMintIndex),
 	}
-	return swaps
+	if exactIn {
+		event.AmountIn, event.MinimumAmountOut = args.Amount, args.OtherAmount
+	} else {
+		event.AmountOut, event.MaximumAmountIn = args.Amount, args.OtherAmount
+	}
+	event.BaseDecimals, event.QuoteDecimals = p.splDecimalsMap[event.BaseMint], p.splDecimalsMap[event.QuoteMint]
+	return event, nil
 }
 
-// isRaydiumLaunchLabEvent 检查是否为 Raydium LaunchLab 事件指令
-func (p *Parser) isRaydiumLaunchLabEvent(inst solana.CompiledInstruction) bool {
-	// Add bounds checking for ProgramIDIndex
-	if int(inst.ProgramIDIndex) >= len(p.allAccountKeys) {
-		return false
+// parseRaydiumLaunchLabTradeEvent 将 TradeEvent 解码到 event 中，事件的池子和方向必须与指令一致。解码失败时 event 不变
+func (p *Parser) parseRaydiumLaunchLabTradeEvent(instruction solana.CompiledInstruction, event *RaydiumLaunchLabTradeEvent) error {
+	data := instruction.Data[16:]
+	decoded := *event
+	head := []interface{}{&decoded.PoolState, &decoded.TotalBaseSell, &decoded.VirtualBase, &decoded.VirtualQuote,
+		&decoded.RealBaseBefore, &decoded.RealQuoteBefore, &decoded.RealBaseAfter, &decoded.RealQuoteAfter,
+		&decoded.AmountIn, &decoded.AmountOut, &decoded.ProtocolFee, &decoded.PlatformFee}
+	var fields []interface{}
+	if len(data) > raydiumLaunchLabTradeEventV1Size {
+		decoded.Version = RaydiumLaunchLabTradeEventV2
+		fields = append(head, &decoded.CreatorFee, &decoded.ShareFee, &decoded.TradeDirection, &decoded.PoolStatus, &decoded.ExactIn)
+	} else {
+		decoded.Version = RaydiumLaunchLabTradeEventV1
+		fields = append(head, &decoded.ShareFee, &decoded.TradeDirection, &decoded.PoolStatus)
 	}
-	if !p.allAccountKeys[inst.ProgramIDIndex].Equals(RAYDIUM_LAUNCHLAB_PROGRAM_ID) {
-		return false
+	if err := decodeFields(ag_binary.NewBorshDecoder(data), fields...); err != nil {
+		return fmt.Errorf("error unmarshaling raydium launchlab TradeEvent: %s", err)
 	}
 
-	if len(inst.Data) < 8 {
-		return false
+	if !decoded.PoolState.Equals(event.PoolState) {
+		return fmt.Errorf("error parsing raydium launchlab trade: TradeEvent pool %s does not match instruction", decoded.PoolState)
+	}
+	if decoded.TradeDirection != event.TradeDirection || decoded.ExactIn != event.ExactIn {
+		return fmt.Errorf("error parsing raydium launchlab trade: TradeEvent direction does not match instruction")
 	}
+	*event = decoded
+	return nil
+}
 
-	decodedBytes, err := base58.Decode(inst.Data.String())
-	if err != nil {
+// enrichRaydiumLaunchLabEventFromTransfers 从交易指令的内部转账中获取用户实际支付和收到的数量，没有找到两个代币的转账时返回 false
+func (p *Parser) enrichRaydiumLaunchLabEventFromTransfers(event *RaydiumLaunchLabTradeEvent, inner []solana.CompiledInstruction) bool {
+	var baseAmount, quoteAmount uint64
+	for _, innerInstruction := range inner {
+		if !p.isTransferCheck(innerInstruction) {
+			continue
+		}
+		transfer := p.processTransferCheck(innerInstruction)
+		if transfer == nil {
+			continue
+		}
+		amount, err := parseUint64(transfer.Info.TokenAmount.Amount)
+		if err != nil {
+			p.recordDecodeError(RAYDIUM_LAUNCHLAB_PROGRAM_ID, err)
+			continue
+		}
+		switch transfer.Info.Mint {
+		case event.BaseMint.String():
+			baseAmount = amount
+			event.BaseDecimals = transfer.Info.TokenAmount.Decimals
+		case event.QuoteMint.String():
+			quoteAmount = amount
+			event.QuoteDecimals = transfer.Info.TokenAmount.Decimals
+		}
+	}
+	if baseAmount == 0 || quoteAmount == 0 {
 		return false
 	}
 
-	// 检查是否匹配已知的事件判别器
-	return bytes.Equal(decodedBytes[:8], RaydiumLaunchLabBuyEventDiscriminator[:]) || bytes.Equal(decodedBytes[:8], RaydiumLaunchLabSellEventDiscriminator[:])
+	if event.IsBuy() {
+		event.AmountIn, event.AmountOut = quoteAmount, baseAmount
+	} else {
+		event.AmountIn, event.AmountOut = baseAmount, quoteAmount
+	}
+	return true
 }
 
-// parseRaydiumLaunchLabEvent 解析 Raydium LaunchLab 事件
-func (p *Parser) parseRaydiumLaunchLabEvent(instruction solana.CompiledInstruction) (*RaydiumLaunchLabBuyEvent, error) {
-	decodedBytes, err := base58.Decode(instruction.Data.String())
-	if err != nil {
-		return nil, fmt.Errorf("error decoding instruction data: %s", err)
-	}
+// IsBuy 返回是否为买入 base 代币
+func (e *RaydiumLaunchLabTradeEvent) IsBuy() bool {
+	return e.TradeDirection == RaydiumLaunchLabBuy
+}
 
-	// 跳过判别器，解析事件数据
-	decoder := ag_binary.NewBorshDecoder(decodedBytes[8:])
+// Input 返回用户的输入代币、支付的数量和精度
+func (e *RaydiumLaunchLabTradeEvent) Input() (solana.PublicKey, uint64, uint8) {
+	if e.IsBuy() {
+		return e.QuoteMint, e.AmountIn, e.QuoteDecimals
+	}
+	return e.BaseMint, e.AmountIn, e.BaseDecimals
+}
 
-	var event RaydiumLaunchLabBuyEvent
-	if err := decoder.Decode(&event); err != nil {
-		return nil, fmt.Errorf("error unmarshaling RaydiumLaunchLabBuyEvent: %s", err)
+// Output 返回用户的输出代币、收到的数量和精度
+func (e *RaydiumLaunchLabTradeEvent) Output() (solana.PublicKey, uint64, uint8) {
+	if e.IsBuy() {
+		return e.BaseMint, e.AmountOut, e.BaseDecimals
 	}
+	return e.QuoteMint, e.AmountOut, e.QuoteDecimals
+}
 
-	return &event, nil
+// isRaydiumLaunchLabInitializeInstruction 判断指令是否为 LaunchLab 创建池子的指令
+func (p *Parser) isRaydiumLaunchLabInitializeInstruction(instruction solana.CompiledInstruction) bool {
+	return p.isProgramDataInstruction(instruction, RAYDIUM_LAUNCHLAB_PROGRAM_ID, RaydiumLaunchLabInitializeDiscriminator[:]) ||
+		p.isProgramDataInstruction(instruction, RAYDIUM_LAUNCHLAB_PROGRAM_ID, RaydiumLaunchLabInitializeV2Discriminator[:]) ||
+		p.isProgramDataInstruction(instruction, RAYDIUM_LAUNCHLAB_PROGRAM_ID, RaydiumLaunchLabInitializeWithToken2022Discriminator[:])
 }
 
-// parseRaydiumLaunchLabInstruction 解析 Raydium LaunchLab 主指令数据
-func (p *Parser) parseRaydiumLaunchLabInstruction(instruction solana.CompiledInstruction) (*RaydiumLaunchLabInstructionData, bool, error) {
-	decodedBytes, err := base58.Decode(instruction.Data.String())
-	if err != nil {
-		return nil, false, fmt.Errorf("error decoding instruction data: %s", err)
+// parseRaydiumLaunchLabInitialize 将创建池子的指令解析为 TokenLaunch，代币元数据取自指令参数
+func (p *Parser) parseRaydiumLaunchLabInitialize(instruction solana.CompiledInstruction) (TokenLaunch, error) {
+	if len(instruction.Accounts) <= raydiumLaunchLabInitializeMintIndex {
+		return TokenLaunch{}, fmt.Errorf("error parsing raydium launchlab initialize: unexpected account count %d", len(instruction.Accounts))
 	}
-
-	if len(decodedBytes) < 32 {
-		return nil, false, fmt.Errorf("instruction data too short")
+	var params raydiumLaunchLabMintParams
+	if err := ag_binary.NewBorshDecoder(instruction.Data[8:]).Decode(&params); err != nil {
+		return TokenLaunch{}, fmt.Errorf("error decoding raydium launchlab initialize instruction: %s", err)
 	}
+	return TokenLaunch{
+		Type:         RAYDIUM_LAUNCHLAB,
+		Platform:     p.launchLabPlatform(p.accountAt(instruction, raydiumLaunchLabPlatformConfigIndex), solana.PublicKey{}),
+		Mint:         p.accountAt(instruction, raydiumLaunchLabInitializeMintIndex),
+		Decimals:     params.Decimals,
+		BondingCurve: p.accountAt(instruction, raydiumLaunchLabInitializePoolIndex),
+		User:         p.accountAt(instruction, 0),
+		Creator:      p.accountAt(instruction, raydiumLaunchLabCreatorIndex),
+		Name:         params.Name,
+		Symbol:       params.Symbol,
+		URI:          params.Uri,
+	}, nil
+}
 
-	// 检查指令类型
-	discriminator := decodedBytes[:8]
-	isBuy := bytes.Equal(discriminator, RaydiumLaunchLabBuyEventDiscriminator[:])
-	isSell := bytes.Equal(discriminator, RaydiumLaunchLabSellEventDiscriminator[:])
-
-	if !isBuy && !isSell {
-		return nil, false, fmt.Errorf("unknown Raydium LaunchLab instruction discriminator: %v", discriminator)
+// parseUint64 将十进制字符串形式的代币数量转换为 uint64，格式错误或溢出时返回错误
+func parseUint64(s string) (uint64, error) {
+	value, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing token amount %q: %w", s, err)
 	}
+	return value, nil
+}
 
-	// 跳过判别器，解析指令参数
-	decoder := ag_binary.NewBorshDecoder(decodedBytes[8:])
+// processRaydiumLaunchLabTransfers 通过分析内部指令中的转账解析交换信息，platform 为交易指令所属的发射平台
+func (p *Parser) processRaydiumLaunchLabTransfers(inner []solana.CompiledInstruction, platform string) []SwapData {
+	var swaps []SwapData
 
-	var instructionData RaydiumLaunchLabInstructionData
-	if err := decoder.Decode(&instructionData); err != nil {
-		return nil, false, fmt.Errorf("error unmarshaling instruction data: %s", err)
+	for _, innerInstruction := range inner {
+		switch {
+		case p.isTransferCheck(innerInstruction):
+			transfer := p.processTransferCheck(innerInstruction)
+			if transfer != nil {
+				swaps = append(swaps, SwapData{Type: RAYDIUM_LAUNCHLAB, Data: transfer, Platform: platform})
+			}
+		case p.isTransfer(innerInstruction):
+			transfer := p.processTransfer(innerInstruction)
+			if transfer != nil {
+				swaps = append(swaps, SwapData{Type: RAYDIUM_LAUNCHLAB, Data: transfer, Platform: platform})
+			}
+		}
 	}
-
-	return &instructionData, isBuy, nil
+	return swaps
 }
+
+// 旧版本导出的名称，保留一个版本后删除
+var (
+	// Deprecated: 使用 RaydiumLaunchLabBuyExactInDiscriminator。旧值并不是 buy_exact_in 的判别器，现已改为正确的值
+	RaydiumLaunchLabBuyEventDiscriminator = RaydiumLaunchLabBuyExactInDiscriminator
+	// Deprecated: 使用 RaydiumLaunchLabSellExactInDiscriminator
+	RaydiumLaunchLabSellEventDiscriminator = RaydiumLaunchLabSellExactInDiscriminator
+)
@@ -0,0 +1,207 @@
+package solanaswapgo
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// Raydium AMM v4 交换指令的第一个字节。V2 版本去掉了 Serum 市场相关的账户
+const (
+	RaydiumV4SwapBaseInInstruction    byte = 9
+	RaydiumV4SwapBaseOutInstruction   byte = 11
+	RaydiumV4SwapBaseInV2Instruction  byte = 16
+	RaydiumV4SwapBaseOutV2Instruction byte = 17
+)
+
+// ray_log 的日志类型
+const (
+	raydiumV4LogSwapBaseIn  = 3
+	raydiumV4LogSwapBaseOut = 4
+)
+
+// raydiumV4LogPrefix 是 Raydium AMM v4 输出 ray_log 的日志行前缀，之后为 base64 编码的日志结构
+const raydiumV4LogPrefix = "Program log: ray_log: "
+
+// RaydiumV4Direction 是 ray_log 中记录的交换方向
+type RaydiumV4Direction uint64
+
+const (
+	RaydiumV4PcToCoin RaydiumV4Direction = 1 // 输入 pc（报价代币），输出 coin
+	RaydiumV4CoinToPc RaydiumV4Direction = 2 // 输入 coin，输出 pc
+)
+
+// RaydiumV4SwapEvent 是由 swapBaseIn / swapBaseOut 指令参数和 ray_log 日志得到的 Raydium AMM v4 交换。
+// AmountIn 和 AmountOut 为实际数量，PoolCoinAmount 和 PoolPcAmount 为交换前的池子储备
+type RaydiumV4SwapEvent struct {
+	Amm       solana.PublicKey
+	User      solana.PublicKey
+	BaseIn    bool // true 为 swapBaseIn（指定输入数量），false 为 swapBaseOut（指定输出数量）
+	Direction RaydiumV4Direction
+
+	// 指令参数中的滑点限制，BaseIn 时为 MinimumAmountOut，否则为 MaxAmountIn
+	MinimumAmountOut uint64
+	MaxAmountIn      uint64
+
+	AmountIn         uint64
+	AmountOut        uint64
+	UserSourceAmount uint64 // 交换前用户输入代币账户的余额
+	PoolCoinAmount   uint64
+	PoolPcAmount     uint64
+
+	TokenInMint      solana.PublicKey
+	TokenInDecimals  uint8
+	TokenOutMint     solana.PublicKey
+	TokenOutDecimals uint8
+}
+
+// raydiumV4SwapInstructionData 是交换指令第一个字节之后的参数。
+// swapBaseIn 依次为 amount_in 和 minimum_amount_out，swapBaseOut 依次为 max_amount_in 和 amount_out
+type raydiumV4SwapInstructionData struct {
+	First  uint64
+	Second uint64
+}
+
+// raydiumV4SwapBaseInLog 是 swapBaseIn 输出的 ray_log
+type raydiumV4SwapBaseInLog struct {
+	LogType    uint8
+	AmountIn   uint64
+	MinimumOut uint64
+	Direction  uint64
+	UserSource uint64
+	PoolCoin   uint64
+	PoolPc     uint64
+	OutAmount  uint64
+}
+
+// raydiumV4SwapBaseOutLog 是 swapBaseOut 输出的 ray_log
+type raydiumV4SwapBaseOutLog struct {
+	LogType    uint8
+	MaxIn      uint64
+	AmountOut  uint64
+	Direction  uint64
+	UserSource uint64
+	PoolCoin   uint64
+	PoolPc     uint64
+	DeductIn   uint64
+}
+
+// isRaydiumV4SwapInstruction 判断指令是否为 Raydium AMM v4 的交换指令
+func (p *Parser) isRaydiumV4SwapInstruction(instruction solana.CompiledInstruction) bool {
+	if int(instruction.ProgramIDIndex) >= len(p.allAccountKeys) || !p.allAccountKeys[instruction.ProgramIDIndex].Equals(RAYDIUM_V4_PROGRAM_ID) {
+		return false
+	}
+	if len(instruction.Data) < 17 {
+		return false
+	}
+	switch instruction.Data[0] {
+	case RaydiumV4SwapBaseInInstruction, RaydiumV4SwapBaseOutInstruction, RaydiumV4SwapBaseInV2Instruction, RaydiumV4SwapBaseOutV2Instruction:
+		return true
+	}
+	return false
+}
+
+// raydiumV4VaultIndexes 返回交换指令中池子 coin 和 pc 代币账户的位置。
+// 带 Serum 账户的版本有 18 个（含 target orders）或 17 个账户，V2 版本有 8 个
+func raydiumV4VaultIndexes(accounts int) (int, int, bool) {
+	switch {
+	case accounts >= 17:
+		return accounts - 13, accounts - 12, true
+	case accounts == 8:
+		return 3, 4, true
+	}
+	return 0, 0, false
+}
+
+// parseRaydiumV4Swap 解析第 instructionIndex 个外层指令（innerIndex 为 -1）或其内部指令中的 Raydium AMM v4 交换，
+// 数量取自该指令输出的 ray_log。没有 ray_log（如日志被截断或未获取日志）时返回 nil
+func (p *Parser) parseRaydiumV4Swap(instructionIndex, innerIndex int, instruction solana.CompiledInstruction) (*RaydiumV4SwapEvent, error) {
+	coinIndex, pcIndex, ok := raydiumV4VaultIndexes(len(instruction.Accounts))
+	if !ok {
+		return nil, fmt.Errorf("error parsing raydium v4 swap: unexpected account count %d", len(instruction.Accounts))
+	}
+
+	var rayLog []byte
+	for _, line := range p.instructionLogs(instructionIndex, innerIndex) {
+		if strings.HasPrefix(line, raydiumV4LogPrefix) {
+			data, err := base64.StdEncoding.DecodeString(line[len(raydiumV4LogPrefix):])
+			if err != nil {
+				return nil, fmt.Errorf("error decoding ray_log: %s", err)
+			}
+			rayLog = data
+		}
+	}
+	if len(rayLog) == 0 {
+		return nil, nil
+	}
+
+	var args raydiumV4SwapInstructionData
+	if err := ag_binary.NewBinDecoder(instruction.Data[1:]).Decode(&args); err != nil {
+		return nil, fmt.Errorf("error decoding raydium v4 swap instruction: %s", err)
+	}
+
+	accounts := len(instruction.Accounts)
+	event := &RaydiumV4SwapEvent{
+		Amm:  p.accountAt(instruction, 1),
+		User: p.accountAt(instruction, accounts-1),
+	}
+	switch instruction.Data[0] {
+	case RaydiumV4SwapBaseInInstruction, RaydiumV4SwapBaseInV2Instruction:
+		var log raydiumV4SwapBaseInLog
+		if err := ag_binary.NewBinDecoder(rayLog).Decode(&log); err != nil {
+			return nil, fmt.Errorf("error decoding swap base in ray_log: %s", err)
+		}
+		if log.LogType != raydiumV4LogSwapBaseIn || log.AmountIn != args.First {
+			return nil, fmt.Errorf("error parsing raydium v4 swap: ray_log does not match swap base in")
+		}
+		event.BaseIn = true
+		event.Direction = RaydiumV4Direction(log.Direction)
+		event.MinimumAmountOut = args.Second
+		event.AmountIn, event.AmountOut = log.AmountIn, log.OutAmount
+		event.UserSourceAmount, event.PoolCoinAmount, event.PoolPcAmount = log.UserSource, log.PoolCoin, log.PoolPc
+	default:
+		var log raydiumV4SwapBaseOutLog
+		if err := ag_binary.NewBinDecoder(rayLog).Decode(&log); err != nil {
+			return nil, fmt.Errorf("error decoding swap base out ray_log: %s", err)
+		}
+		if log.LogType != raydiumV4LogSwapBaseOut || log.AmountOut != args.Second {
+			return nil, fmt.Errorf("error parsing raydium v4 swap: ray_log does not match swap base out")
+		}
+		event.Direction = RaydiumV4Direction(log.Direction)
+		event.MaxAmountIn = args.First
+		event.AmountIn, event.AmountOut = log.DeductIn, log.AmountOut
+		event.UserSourceAmount, event.PoolCoinAmount, event.PoolPcAmount = log.UserSource, log.PoolCoin, log.PoolPc
+	}
+
+	// 代币优先取自池子账户，池子账户没有余额记录时取自用户账户
+	coin := p.splTokenInfoMap[p.accountAt(instruction, coinIndex)]
+	pc := p.splTokenInfoMap[p.accountAt(instruction, pcIndex)]
+	in, out := pc, coin
+	switch event.Direction {
+	case RaydiumV4PcToCoin:
+	case RaydiumV4CoinToPc:
+		in, out = coin, pc
+	default:
+		return nil, fmt.Errorf("error parsing raydium v4 swap: unknown direction %d", event.Direction)
+	}
+	if in.Mint == "" {
+		in = p.splTokenInfoMap[p.accountAt(instruction, accounts-3)]
+	}
+	if out.Mint == "" {
+		out = p.splTokenInfoMap[p.accountAt(instruction, accounts-2)]
+	}
+	inMint, err := solana.PublicKeyFromBase58(in.Mint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing raydium v4 input mint: %s", err)
+	}
+	outMint, err := solana.PublicKeyFromBase58(out.Mint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing raydium v4 output mint: %s", err)
+	}
+	event.TokenInMint, event.TokenInDecimals = inMint, in.Decimals
+	event.TokenOutMint, event.TokenOutDecimals = outMint, out.Decimals
+	return event, nil
+}
@@ -0,0 +1,115 @@
+package solanaswapgo
+
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// TokenLaunch 是交易中在发射平台上创建的一个代币
+type TokenLaunch struct {
+	Type      SwapType // 发射平台
+	Platform  string   // LaunchLab 上按 LaunchLabPlatforms 识别的平台，未识别时为空
+	Signature solana.Signature
+	Timestamp time.Time
+
+	Mint         solana.PublicKey
+	Decimals     uint8
+	BondingCurve solana.PublicKey
+	User         solana.PublicKey // 发起创建的账户
+	Creator      solana.PublicKey // 收取创作者费用的账户
+	Name         string
+	Symbol       string
+	URI          string
+
+	// InitialBuy 为 Pump.fun 创建者在同一笔交易中的首次买入，没有时为 nil
+	InitialBuy *PumpfunTradeEvent
+}
+
+// ParseTokenLaunches 解析交易中的 Pump.fun 代币创建事件和 Raydium LaunchLab 创建池子的指令，每个对应一个 TokenLaunch。
+// 与 ParseTransaction 相互独立，可以对同一个 Parser 先后调用
+func (p *Parser) ParseTokenLaunches() []TokenLaunch {
+	if p.txMeta.Err != nil {
+		return nil
+	}
+
+	var (
+		launches []TokenLaunch
+		trades   []*PumpfunTradeEvent
+	)
+	for i, outer := range p.txInfo.Message.Instructions {
+		if p.isRaydiumLaunchLabInitializeInstruction(outer) {
+			launches = p.appendLaunchLabLaunch(launches, outer)
+		}
+		for _, instruction := range p.innerInstructions[i] {
+			switch {
+			case p.isRaydiumLaunchLabInitializeInstruction(instruction):
+				launches = p.appendLaunchLabLaunch(launches, instruction)
+			case p.isPumpFunCreateEventInstruction(instruction):
+				create, err := p.parsePumpfunCreateEventInstruction(instruction)
+				if err != nil {
+					p.recordDecodeError(PUMP_FUN_PROGRAM_ID, err)
+					continue
+				}
+				launches = append(launches, p.newPumpfunLaunch(create))
+			case p.isPumpFunTradeEventInstruction(instruction):
+				trade, err := p.parsePumpfunTradeEventInstruction(instruction)
+				if err != nil {
+					p.recordDecodeError(PUMP_FUN_PROGRAM_ID, err)
+					continue
+				}
+				trades = append(trades, trade)
+			}
+		}
+	}
+
+	// 首次买入是同一用户对该代币的第一笔买入
+	for i := range launches {
+		for _, trade := range trades {
+			if trade.IsBuy && trade.Mint.Equals(launches[i].Mint) && trade.User.Equals(launches[i].User) {
+				launches[i].InitialBuy = trade
+				break
+			}
+		}
+	}
+	return launches
+}
+
+// appendLaunchLabLaunch 解析 LaunchLab 创建池子的指令并追加到 launches，解析失败时记录解码错误
+func (p *Parser) appendLaunchLabLaunch(launches []TokenLaunch, instruction solana.CompiledInstruction) []TokenLaunch {
+	launch, err := p.parseRaydiumLaunchLabInitialize(instruction)
+	if err != nil {
+		p.recordDecodeError(RAYDIUM_LAUNCHLAB_PROGRAM_ID, err)
+		return launches
+	}
+	if len(p.txInfo.Signatures) > 0 {
+		launch.Signature = p.txInfo.Signatures[0]
+	}
+	if blockTime := p.GetBlockTime(); blockTime != nil {
+		launch.Timestamp = *blockTime
+	}
+	return append(launches, launch)
+}
+
+func (p *Parser) newPumpfunLaunch(create *PumpfunCreateEvent) TokenLaunch {
+	launch := TokenLaunch{
+		Type:         PUMP_FUN,
+		Mint:         create.Mint,
+		Decimals:     p.splDecimalsMap[create.Mint],
+		BondingCurve: create.BondingCurve,
+		User:         create.User,
+		Creator:      create.Creator,
+		Name:         create.Name,
+		Symbol:       create.Symbol,
+		URI:          create.Uri,
+	}
+	if len(p.txInfo.Signatures) > 0 {
+		launch.Signature = p.txInfo.Signatures[0]
+	}
+	if create.Timestamp != 0 {
+		launch.Timestamp = time.Unix(create.Timestamp, 0)
+	} else if blockTime := p.GetBlockTime(); blockTime != nil {
+		launch.Timestamp = *blockTime
+	}
+	return launch
+}
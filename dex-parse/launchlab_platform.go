@@ -0,0 +1,82 @@
+package solanaswapgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// LaunchLabPlatform 是运行在 Raydium LaunchLab 上的一个发射平台。各平台共用 LaunchLab 程序，
+// 由指令中的 platform_config 账户或平台费用接收者区分，两者至少填写一个
+type LaunchLabPlatform struct {
+	Name           string           `json:"name"`
+	PlatformConfig solana.PublicKey `json:"platformConfig"`
+	// FeeRecipient 为交易指令中 platform_fee_vault 位置的账户，只比较该位置，创建池子的指令中没有这个账户
+	FeeRecipient solana.PublicKey `json:"feeRecipient"`
+}
+
+// DefaultLaunchLabPlatforms 是未调用 SetLaunchLabPlatforms 时使用的平台列表
+var DefaultLaunchLabPlatforms = []LaunchLabPlatform{
+	{Name: "bonk", PlatformConfig: solana.MustPublicKeyFromBase58("FfYek5vEz23cMkWsdJwG2oa6EphsvXSHrGpdALN4g6W1")},
+}
+
+var launchLabPlatforms atomic.Pointer[[]LaunchLabPlatform]
+
+// SetLaunchLabPlatforms 设置全局的 LaunchLab 平台列表，传入 nil 则恢复为 DefaultLaunchLabPlatforms。可以与解析并发调用
+func SetLaunchLabPlatforms(platforms []LaunchLabPlatform) {
+	if platforms == nil {
+		launchLabPlatforms.Store(nil)
+		return
+	}
+	platforms = append([]LaunchLabPlatform(nil), platforms...)
+	launchLabPlatforms.Store(&platforms)
+}
+
+// LaunchLabPlatforms 返回当前的 LaunchLab 平台列表
+func LaunchLabPlatforms() []LaunchLabPlatform {
+	if platforms := launchLabPlatforms.Load(); platforms != nil {
+		return *platforms
+	}
+	return DefaultLaunchLabPlatforms
+}
+
+// ReadLaunchLabPlatforms 读取 JSON 格式的平台列表，如
+// [{"name": "bonk", "platformConfig": "FfYek5vEz23cMkWsdJwG2oa6EphsvXSHrGpdALN4g6W1"}]
+func ReadLaunchLabPlatforms(r io.Reader) ([]LaunchLabPlatform, error) {
+	var platforms []LaunchLabPlatform
+	if err := json.NewDecoder(r).Decode(&platforms); err != nil {
+		return nil, fmt.Errorf("error decoding launchlab platforms: %s", err)
+	}
+	for i, platform := range platforms {
+		if platform.Name == "" {
+			return nil, fmt.Errorf("error decoding launchlab platforms: platform %d has no name", i)
+		}
+		if platform.PlatformConfig.IsZero() && platform.FeeRecipient.IsZero() {
+			return nil, fmt.Errorf("error decoding launchlab platforms: %s has neither platformConfig nor feeRecipient", platform.Name)
+		}
+	}
+	return platforms, nil
+}
+
+// launchLabPlatform 返回 LaunchLab 指令所属平台的名称。先按 platform_config 账户匹配，
+// 再按 platform_fee_vault 位置的账户匹配平台费用接收者（指令中没有该账户时为零值），都没有匹配时为空
+func (p *Parser) launchLabPlatform(config, feeVault solana.PublicKey) string {
+	platforms := LaunchLabPlatforms()
+	for _, platform := range platforms {
+		if !platform.PlatformConfig.IsZero() && platform.PlatformConfig.Equals(config) {
+			return platform.Name
+		}
+	}
+	if feeVault.IsZero() {
+		return ""
+	}
+	for _, platform := range platforms {
+		if !platform.FeeRecipient.IsZero() && platform.FeeRecipient.Equals(feeVault) {
+			return platform.Name
+		}
+	}
+	return ""
+}
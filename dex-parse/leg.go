@@ -0,0 +1,185 @@
+package solanaswapgo
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Leg 是单个 SwapData 的扁平表示，供 sink 和 swappb 共用，字段含义与具体的事件类型无关。
+// 转账类的交换（TransferData、TransferCheck）只描述一次代币转移，只填写 Input* 与 Source/Destination/Authority；
+// 精度未知时（如 Pump.fun 事件中的代币）会从交易的 SwapInfo 中按 mint 补全
+type Leg struct {
+	Signature string `json:"signature"`
+	Slot      uint64 `json:"slot"`
+	LegIndex  int    `json:"legIndex"`
+	Protocol  string `json:"protocol"`
+	// Platform 为 SwapData.Platform，即 Raydium LaunchLab 上的发射平台，未识别时为空
+	Platform string `json:"platform"`
+	// DataType 是 SwapData.Data 的类型名，如 PumpfunTradeEvent、TransferData
+	DataType string `json:"dataType"`
+	Pool     string `json:"pool"`
+	Trader   string `json:"trader"`
+
+	InputMint      string `json:"inputMint"`
+	InputAmount    uint64 `json:"inputAmount"`
+	InputDecimals  int    `json:"inputDecimals"`
+	InputUIAmount  string `json:"inputUiAmount"`
+	OutputMint     string `json:"outputMint"`
+	OutputAmount   uint64 `json:"outputAmount"`
+	OutputDecimals int    `json:"outputDecimals"`
+	OutputUIAmount string `json:"outputUiAmount"`
+
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Authority   string `json:"authority"`
+}
+
+// NewLegs 将 BlockSwap 的每个 SwapData 转换为 Leg
+func NewLegs(swap BlockSwap) []Leg {
+	decimals := make(map[string]int)
+	decimals[NATIVE_SOL_MINT_PROGRAM_ID.String()] = 9
+	if info := swap.SwapInfo; info != nil {
+		decimals[info.TokenInMint.String()] = int(info.TokenInDecimals)
+		decimals[info.TokenOutMint.String()] = int(info.TokenOutDecimals)
+	}
+
+	legs := make([]Leg, 0, len(swap.Swaps))
+	for i, data := range swap.Swaps {
+		leg := newLeg(data)
+		leg.Signature = swap.Signature.String()
+		leg.Slot = swap.Slot
+		leg.LegIndex = i
+		leg.Protocol = string(data.Type)
+		leg.Platform = data.Platform
+
+		if leg.InputDecimals == 0 {
+			leg.InputDecimals = decimals[leg.InputMint]
+		}
+		if leg.OutputDecimals == 0 {
+			leg.OutputDecimals = decimals[leg.OutputMint]
+		}
+		if leg.InputMint != "" {
+			leg.InputUIAmount = FormatAmount(leg.InputAmount, uint8(leg.InputDecimals))
+		}
+		if leg.OutputMint != "" {
+			leg.OutputUIAmount = FormatAmount(leg.OutputAmount, uint8(leg.OutputDecimals))
+		}
+		legs = append(legs, leg)
+	}
+	return legs
+}
+
+// newLeg 按事件类型提取输入输出，方向与 ProcessSwapData 一致
+func newLeg(data SwapData) Leg {
+	sol := NATIVE_SOL_MINT_PROGRAM_ID
+	var leg Leg
+	if data.Data != nil {
+		leg.DataType = reflect.Indirect(reflect.ValueOf(data.Data)).Type().Name()
+	}
+
+	setInput := func(mint solana.PublicKey, amount uint64, decimals uint8) {
+		leg.InputMint, leg.InputAmount, leg.InputDecimals = mint.String(), amount, int(decimals)
+	}
+	setOutput := func(mint solana.PublicKey, amount uint64, decimals uint8) {
+		leg.OutputMint, leg.OutputAmount, leg.OutputDecimals = mint.String(), amount, int(decimals)
+	}
+
+	switch event := data.Data.(type) {
+	case *JupiterSwapEventData:
+		leg.Pool = event.Amm.String()
+		setInput(event.InputMint, event.InputAmount, event.InputMintDecimals)
+		setOutput(event.OutputMint, event.OutputAmount, event.OutputMintDecimals)
+
+	case *PumpfunTradeEvent:
+		leg.Trader = event.User.String()
+		if event.IsBuy {
+			setInput(sol, event.SolAmount, 9)
+			setOutput(event.Mint, event.TokenAmount, 0)
+		} else {
+			setInput(event.Mint, event.TokenAmount, 0)
+			setOutput(sol, event.SolAmount, 9)
+		}
+
+	case *PumpfunAMMBuyEvent:
+		leg.Pool, leg.Trader = event.Pool.String(), event.User.String()
+		setInput(event.QuoteMint, event.UserQuoteAmountIn, event.QuoteMintDecimals)
+		setOutput(event.BaseMint, event.BaseAmountOut, event.BaseMintDecimals)
+
+	case *PumpfunAMMSellEvent:
+		leg.Pool, leg.Trader = event.Pool.String(), event.User.String()
+		setInput(event.BaseMint, event.BaseAmountIn, event.BaseMintDecimals)
+		setOutput(event.QuoteMint, event.UserQuoteAmountOut, event.QuoteMintDecimals)
+
+	case *RaydiumLaunchLabTradeEvent:
+		leg.Pool, leg.Trader = event.PoolState.String(), event.User.String()
+		setInput(event.Input())
+		setOutput(event.Output())
+
+	case *RaydiumV4SwapEvent:
+		leg.Pool, leg.Trader = event.Amm.String(), event.User.String()
+		setInput(event.TokenInMint, event.AmountIn, event.TokenInDecimals)
+		setOutput(event.TokenOutMint, event.AmountOut, event.TokenOutDecimals)
+
+	case *RaydiumCLMMSwapEvent:
+		leg.Pool, leg.Trader = event.PoolState.String(), event.Sender.String()
+		setInput(event.Input())
+		setOutput(event.Output())
+
+	case *RaydiumCPMMSwapEvent:
+		leg.Pool, leg.Trader = event.PoolId.String(), event.User.String()
+		setInput(event.Input())
+		setOutput(event.Output())
+
+	case *MeteoraDAMMv2SwapEvent:
+		setInput(event.TokenInMint, event.AmountIn, event.TokenInDecimals)
+		setOutput(event.TokenOutMint, event.ActualAmountOut, event.TokenOutDecimals)
+
+	case *MeteoraDBCSwapEvent:
+		leg.Pool = event.Pool.String()
+		setInput(event.TokenInMint, event.AmountIn, event.TokenInDecimals)
+		setOutput(event.TokenOutMint, event.OutputAmount, event.TokenOutDecimals)
+
+	case *BoopFunSwapEvent:
+		setInput(sol, event.BuyAmount, 9)
+		setOutput(event.TokenMint, event.TokenOut, event.TokenDecimals)
+
+	case *MoonshotTradeInstructionWithMint:
+		if event.TradeType == TradeTypeBuy {
+			setInput(sol, event.CollateralAmount, 9)
+			setOutput(event.Mint, event.TokenAmount, 0)
+		} else {
+			setInput(event.Mint, event.TokenAmount, 0)
+			setOutput(sol, event.CollateralAmount, 9)
+		}
+
+	case *TransferData:
+		leg.InputMint, leg.InputAmount, leg.InputDecimals = event.Mint, event.Info.Amount, int(event.Decimals)
+		leg.Source, leg.Destination, leg.Authority = event.Info.Source, event.Info.Destination, event.Info.Authority
+
+	case *TransferCheck:
+		amount, _ := strconv.ParseUint(event.Info.TokenAmount.Amount, 10, 64)
+		leg.InputMint, leg.InputAmount, leg.InputDecimals = event.Info.Mint, amount, int(event.Info.TokenAmount.Decimals)
+		leg.Source, leg.Destination, leg.Authority = event.Info.Source, event.Info.Destination, event.Info.Authority
+	}
+	return leg
+}
+
+// FormatAmount 按精度格式化整数数量，避免浮点误差
+func FormatAmount(amount uint64, decimals uint8) string {
+	digits := strconv.FormatUint(amount, 10)
+	if decimals == 0 {
+		return digits
+	}
+
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+	whole, fraction := digits[:len(digits)-int(decimals)], strings.TrimRight(digits[len(digits)-int(decimals):], "0")
+	if fraction == "" {
+		return whole
+	}
+	return whole + "." + fraction
+}
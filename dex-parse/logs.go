@@ -0,0 +1,117 @@
+package solanaswapgo
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// programInvocationLogs 按调用顺序返回 program 每次被调用时直接输出的日志（不包括其 CPI 调用的程序输出的日志）。
+// 日志被截断时只返回截断前开始的调用。第一次调用时为所有程序建立索引，之后的查找不再扫描日志
+func (p *Parser) programInvocationLogs(program solana.PublicKey) [][]string {
+	if p.txMeta == nil {
+		return nil
+	}
+	if p.invocationLogs == nil {
+		p.invocationLogs = indexInvocationLogs(p.txMeta.LogMessages)
+	}
+	return p.invocationLogs[program.String()]
+}
+
+// indexInvocationLogs 按程序地址返回每次调用时直接输出的日志
+func indexInvocationLogs(logs []string) map[string][][]string {
+	type invocation struct {
+		program  string
+		position int
+	}
+
+	var (
+		invocations = make(map[string][][]string)
+		// stack 记录当前调用链
+		stack []invocation
+	)
+	for _, line := range logs {
+		// "Program log: ..."、"Program data: ..." 等程序输出的第一个字段以冒号结尾
+		if fields := strings.Fields(line); len(fields) > 2 && fields[0] == "Program" && !strings.HasSuffix(fields[1], ":") {
+			switch {
+			case fields[2] == "invoke":
+				program := fields[1]
+				stack = append(stack, invocation{program: program, position: len(invocations[program])})
+				invocations[program] = append(invocations[program], nil)
+				continue
+			case fields[2] == "success" || fields[2] == "failed:":
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
+				continue
+			}
+		}
+		if len(stack) > 0 {
+			current := stack[len(stack)-1]
+			invocations[current.program][current.position] = append(invocations[current.program][current.position], line)
+		}
+	}
+	return invocations
+}
+
+// instructionLogs 返回第 instructionIndex 个外层指令（innerIndex 为 -1）或其第 innerIndex 个内部指令
+// 执行时直接输出的日志。按执行顺序统计之前调用同一程序的指令数，与日志中的调用一一对应
+func (p *Parser) instructionLogs(instructionIndex, innerIndex int) []string {
+	instructions := p.txInfo.Message.Instructions
+	if instructionIndex < 0 || instructionIndex >= len(instructions) {
+		return nil
+	}
+	target := instructions[instructionIndex]
+	if innerIndex >= 0 {
+		inner := p.getInnerInstructions(instructionIndex)
+		if innerIndex >= len(inner) {
+			return nil
+		}
+		target = inner[innerIndex]
+	}
+	if int(target.ProgramIDIndex) >= len(p.allAccountKeys) {
+		return nil
+	}
+
+	// 执行顺序为外层指令，之后是它的全部内部指令
+	ordinal := 0
+	for i := 0; i <= instructionIndex; i++ {
+		if instructions[i].ProgramIDIndex == target.ProgramIDIndex && (i < instructionIndex || innerIndex >= 0) {
+			ordinal++
+		}
+		for j, inner := range p.getInnerInstructions(i) {
+			if i == instructionIndex && j >= innerIndex {
+				break
+			}
+			if inner.ProgramIDIndex == target.ProgramIDIndex {
+				ordinal++
+			}
+		}
+	}
+
+	invocations := p.programInvocationLogs(p.allAccountKeys[target.ProgramIDIndex])
+	if ordinal >= len(invocations) {
+		return nil
+	}
+	return invocations[ordinal]
+}
+
+// programDataPrefix 是 Anchor emit! 输出事件的日志行前缀，之后为 base64 编码的判别器和事件数据
+const programDataPrefix = "Program data: "
+
+// programDataEvents 返回日志中以 discriminator 开头的事件数据，不包括判别器
+func programDataEvents(logs []string, discriminator []byte) [][]byte {
+	var events [][]byte
+	for _, line := range logs {
+		if !strings.HasPrefix(line, programDataPrefix) {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(line[len(programDataPrefix):])
+		if err != nil || !hasDiscriminator(data, discriminator) {
+			continue
+		}
+		events = append(events, data[len(discriminator):])
+	}
+	return events
+}
@@ -0,0 +1,248 @@
+package solanaswapgo
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	ag_binary "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// migrate 指令中 mint、联合曲线和 PumpSwap 池的账户位置
+const (
+	pumpfunMigrateMintIndex         = 2
+	pumpfunMigrateBondingCurveIndex = 3
+	pumpfunMigratePoolIndex         = 9
+)
+
+// Migration 是 Pump.fun 代币毕业的记录。联合曲线被买完时发出 CompleteEvent（Completed），
+// 之后迁移账户调用 migrate 指令，把曲线上的 SOL 和代币转入新建的 PumpSwap 池（Migrated）。
+// 两步通常在不同的交易中，可以用 MigrationTracker 按 Mint 关联；同一笔交易中同时出现时合并为一条记录
+type Migration struct {
+	Signature solana.Signature
+	Timestamp time.Time
+
+	Mint         solana.PublicKey
+	BondingCurve solana.PublicKey
+	Completed    bool // 交易中联合曲线被买完
+	Migrated     bool // 交易中执行了迁移
+
+	// 以下字段只在 Migrated 时设置
+	Pool         solana.PublicKey
+	LpMint       solana.PublicKey
+	SolAmount    uint64 // 转入池子的 SOL（lamports）
+	TokenAmount  uint64 // 转入池子的代币数量
+	MigrationFee uint64 // 迁移收取的 SOL（lamports）
+}
+
+// ParseMigrations 解析交易中的 Pump.fun 毕业和迁移，按在交易中出现的顺序每个代币返回一条记录
+func (p *Parser) ParseMigrations() []Migration {
+	if p.txMeta.Err != nil {
+		return nil
+	}
+
+	var (
+		migrations []Migration
+		pools      []*PumpfunAMMCreatePoolEvent
+	)
+	index := make(map[solana.PublicKey]int)
+	migration := func(mint solana.PublicKey) *Migration {
+		i, ok := index[mint]
+		if !ok {
+			i = len(migrations)
+			index[mint] = i
+			migrations = append(migrations, Migration{Mint: mint})
+		}
+		return &migrations[i]
+	}
+
+	for i, outer := range p.txInfo.Message.Instructions {
+		instructions := append([]solana.CompiledInstruction{outer}, p.getInnerInstructions(i)...)
+		for _, instruction := range instructions {
+			switch {
+			case p.isProgramDataInstruction(instruction, PUMP_FUN_PROGRAM_ID, PumpfunMigrateDiscriminator[:]):
+				if len(instruction.Accounts) <= pumpfunMigratePoolIndex {
+					continue
+				}
+				m := migration(p.accountAt(instruction, pumpfunMigrateMintIndex))
+				m.Migrated = true
+				m.BondingCurve = p.accountAt(instruction, pumpfunMigrateBondingCurveIndex)
+				m.Pool = p.accountAt(instruction, pumpfunMigratePoolIndex)
+			case p.isProgramDataInstruction(instruction, PUMP_FUN_PROGRAM_ID, PumpfunCompleteEventDiscriminator[:]):
+				var event PumpfunCompleteEvent
+				if err := ag_binary.NewBorshDecoder(instruction.Data[16:]).Decode(&event); err != nil {
+					p.recordDecodeError(PUMP_FUN_PROGRAM_ID, err)
+					continue
+				}
+				m := migration(event.Mint)
+				m.Completed = true
+				m.BondingCurve = event.BondingCurve
+				p.setMigrationTimestamp(m, event.Timestamp)
+			case p.isProgramDataInstruction(instruction, PUMP_FUN_PROGRAM_ID, PumpfunCompleteMigrationEventDiscriminator[:]):
+				var event PumpfunCompleteMigrationEvent
+				if err := ag_binary.NewBorshDecoder(instruction.Data[16:]).Decode(&event); err != nil {
+					p.recordDecodeError(PUMP_FUN_PROGRAM_ID, err)
+					continue
+				}
+				m := migration(event.Mint)
+				m.Migrated = true
+				m.BondingCurve = event.BondingCurve
+				m.Pool = event.Pool
+				m.SolAmount = event.SolAmount
+				m.TokenAmount = event.MintAmount
+				m.MigrationFee = event.PoolMigrationFee
+				p.setMigrationTimestamp(m, event.Timestamp)
+			case p.isProgramDataInstruction(instruction, PUMPFUN_AMM_PROGRAM_ID, PumpfunAMMCreatePoolEventDiscriminator[:]):
+				event, err := p.parsePumpfunAMMCreatePoolEventInstruction(instruction)
+				if err != nil {
+					p.recordDecodeError(PUMPFUN_AMM_PROGRAM_ID, err)
+					continue
+				}
+				pools = append(pools, event)
+			}
+		}
+	}
+
+	// 较早的 migrate 不发出 CompletePumpAmmMigrationEvent，此时从 PumpSwap 的 CreatePoolEvent 中补全池子和数量
+	for _, pool := range pools {
+		i, ok := index[pool.BaseMint]
+		if !ok || !migrations[i].Migrated {
+			continue
+		}
+		m := &migrations[i]
+		m.LpMint = pool.LpMint
+		if m.Pool.IsZero() {
+			m.Pool = pool.Pool
+		}
+		if m.SolAmount == 0 && m.TokenAmount == 0 {
+			m.SolAmount = pool.QuoteAmountIn
+			m.TokenAmount = pool.BaseAmountIn
+		}
+		p.setMigrationTimestamp(m, pool.Timestamp)
+	}
+
+	for i := range migrations {
+		if len(p.txInfo.Signatures) > 0 {
+			migrations[i].Signature = p.txInfo.Signatures[0]
+		}
+		if migrations[i].Timestamp.IsZero() {
+			if blockTime := p.GetBlockTime(); blockTime != nil {
+				migrations[i].Timestamp = *blockTime
+			}
+		}
+	}
+	return migrations
+}
+
+// setMigrationTimestamp 在记录还没有时间戳时使用事件中的时间戳
+func (p *Parser) setMigrationTimestamp(m *Migration, timestamp int64) {
+	if m.Timestamp.IsZero() && timestamp != 0 {
+		m.Timestamp = time.Unix(timestamp, 0)
+	}
+}
+
+// accountAt 返回指令第 index 个账户的地址，越界时返回零值
+func (p *Parser) accountAt(instruction solana.CompiledInstruction, index int) solana.PublicKey {
+	if index >= len(instruction.Accounts) || int(instruction.Accounts[index]) >= len(p.allAccountKeys) {
+		return solana.PublicKey{}
+	}
+	return p.allAccountKeys[instruction.Accounts[index]]
+}
+
+// Graduation 是同一个代币的毕业记录和迁移记录。两步在同一笔交易中时两者相同
+type Graduation struct {
+	Mint     solana.PublicKey
+	Complete Migration // Completed 的记录
+	Migrate  Migration // Migrated 的记录
+}
+
+// DefaultMigrationTrackerMaxPending 是 NewMigrationTracker 设置的 MaxPending
+const DefaultMigrationTrackerMaxPending = 100_000
+
+// MigrationTracker 按 Mint 关联不同交易中的毕业和迁移记录，两步都出现后返回 Graduation。
+// 记录可以按任意顺序加入，可以并发调用。联合曲线被买完后可能一直没有迁移，长时间运行时
+// 应定期调用 Evict 淘汰过旧的记录，MaxPending 限制最多保留的记录数
+type MigrationTracker struct {
+	// MaxPending 为最多保留的等待另一步的记录数，超出时淘汰最早加入的记录，0 表示不限制
+	MaxPending int
+
+	mu      sync.Mutex
+	pending map[solana.PublicKey]*list.Element // 值为 Migration
+	order   *list.List                         // 按加入顺序排列的记录
+}
+
+// NewMigrationTracker 创建 MigrationTracker，最多保留 DefaultMigrationTrackerMaxPending 条记录
+func NewMigrationTracker() *MigrationTracker {
+	return &MigrationTracker{
+		MaxPending: DefaultMigrationTrackerMaxPending,
+		pending:    make(map[solana.PublicKey]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Add 加入一条 ParseMigrations 返回的记录。该代币的毕业和迁移都已出现时返回 Graduation 和 true，
+// 并不再保留该代币；否则保存记录等待另一步
+func (t *MigrationTracker) Add(m Migration) (Graduation, bool) {
+	if m.Completed && m.Migrated {
+		return Graduation{Mint: m.Mint, Complete: m, Migrate: m}, true
+	}
+	if !m.Completed && !m.Migrated {
+		return Graduation{}, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	element, ok := t.pending[m.Mint]
+	if !ok {
+		t.pending[m.Mint] = t.order.PushBack(m)
+		if t.MaxPending > 0 && t.order.Len() > t.MaxPending {
+			t.remove(t.order.Front())
+		}
+		return Graduation{}, false
+	}
+	earlier := element.Value.(Migration)
+	if earlier.Completed == m.Completed {
+		element.Value = m
+		t.order.MoveToBack(element)
+		return Graduation{}, false
+	}
+	t.remove(element)
+
+	graduation := Graduation{Mint: m.Mint, Complete: earlier, Migrate: m}
+	if m.Completed {
+		graduation.Complete, graduation.Migrate = m, earlier
+	}
+	return graduation, true
+}
+
+// Evict 淘汰时间戳早于 before 的记录（没有时间戳的记录也会被淘汰），返回淘汰的记录数。
+// 流式处理时可以定期传入最新区块时间减去保留时长；按时间倒序回填时依靠 MaxPending 限制记录数
+func (t *MigrationTracker) Evict(before time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	evicted := 0
+	for element := t.order.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(Migration).Timestamp.Before(before) {
+			t.remove(element)
+			evicted++
+		}
+		element = next
+	}
+	return evicted
+}
+
+// Pending 返回还在等待另一步的记录个数
+func (t *MigrationTracker) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.order.Len()
+}
+
+// remove 删除一条记录，调用时需持有 mu
+func (t *MigrationTracker) remove(element *list.Element) {
+	delete(t.pending, t.order.Remove(element).(Migration).Mint)
+}
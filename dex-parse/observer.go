@@ -0,0 +1,109 @@
+package solanaswapgo
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// SwapSource 标识交换数据的解析路径
+type SwapSource string
+
+const (
+	SwapSourceEvent       SwapSource = "event"       // 程序通过 CPI 发出的事件
+	SwapSourceInstruction SwapSource = "instruction" // 指令参数，数量和代币由转账补全
+	SwapSourceTransfer    SwapSource = "transfer"    // 仅由代币转账推断
+)
+
+// Source 返回交换数据的解析路径
+func (s SwapData) Source() SwapSource {
+	switch data := s.Data.(type) {
+	case *TransferData, *TransferCheck:
+		return SwapSourceTransfer
+	case *MoonshotTradeInstructionWithMint, *BoopFunSwapEvent, *MeteoraDAMMv2SwapEvent, *MeteoraDBCSwapEvent:
+		return SwapSourceInstruction
+	case *RaydiumLaunchLabTradeEvent:
+		// 没有 TradeEvent 时由指令参数和转账补全
+		if data.Version == 0 {
+			return SwapSourceInstruction
+		}
+	}
+	return SwapSourceEvent
+}
+
+// DecodeError 记录某个程序的事件或指令数据解码失败
+type DecodeError struct {
+	Program solana.PublicKey
+	Err     error
+}
+
+// ParseStats 是一次 ParseTransaction 的统计
+type ParseStats struct {
+	Duration     time.Duration
+	Swaps        []SwapData
+	DecodeErrors []DecodeError
+	// UnknownPrograms 为外层指令中没有解码器处理的程序，不包括 System、Token 等基础程序
+	UnknownPrograms []solana.PublicKey
+}
+
+// Observer 接收每次 ParseTransaction 的统计，用于指标采集。ObserveParse 会被并发调用
+type Observer interface {
+	ObserveParse(stats ParseStats)
+}
+
+// observerHolder 包装 Observer，使 atomic.Pointer 可以存储接口
+type observerHolder struct {
+	observer Observer
+}
+
+var observer atomic.Pointer[observerHolder]
+
+// SetObserver 设置全局的 Observer，传入 nil 则关闭统计。未设置时不产生额外开销
+func SetObserver(o Observer) {
+	if o == nil {
+		observer.Store(nil)
+		return
+	}
+	observer.Store(&observerHolder{observer: o})
+}
+
+var (
+	memoV1ProgramID             = solana.MustPublicKeyFromBase58("Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo")
+	addressLookupTableProgramID = solana.MustPublicKeyFromBase58("AddressLookupTab1e1111111111111111111111111")
+)
+
+// infrastructurePrograms 是交易中常见但不涉及交换的基础程序，不计入未知程序
+var infrastructurePrograms = map[solana.PublicKey]bool{
+	solana.SystemProgramID:                    true,
+	solana.TokenProgramID:                     true,
+	solana.Token2022ProgramID:                 true,
+	solana.SPLAssociatedTokenAccountProgramID: true,
+	solana.ComputeBudget:                      true,
+	solana.MemoProgramID:                      true,
+	memoV1ProgramID:                           true,
+	addressLookupTableProgramID:               true,
+}
+
+// recordDecodeError 记录解码错误并写入日志
+func (p *Parser) recordDecodeError(program solana.PublicKey, err error) {
+	p.Log.Errorf("error decoding %s data: %s", program, err)
+	p.decodeErrors = append(p.decodeErrors, DecodeError{Program: program, Err: err})
+}
+
+// unknownPrograms 返回外层指令中未被识别的程序，按出现顺序去重
+func (p *Parser) unknownPrograms() []solana.PublicKey {
+	var programs []solana.PublicKey
+	seen := make(map[solana.PublicKey]bool)
+	for _, instruction := range p.txInfo.Message.Instructions {
+		if int(instruction.ProgramIDIndex) >= len(p.allAccountKeys) || p.programKindAt(instruction.ProgramIDIndex) != programUnknown {
+			continue
+		}
+		program := p.allAccountKeys[instruction.ProgramIDIndex]
+		if !infrastructurePrograms[program] && !seen[program] {
+			seen[program] = true
+			programs = append(programs, program)
+		}
+	}
+	return programs
+}
@@ -7,7 +7,6 @@ import (
 
 	ag_binary "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
-	"github.com/mr-tron/base58"
 )
 
 type MoonshotTradeInstructionWithMint struct {
@@ -37,6 +36,7 @@ func (p *Parser) processMoonshotSwaps() []SwapData {
 		if p.isMoonshotTrade(instruction) {
 			swapData, err := p.parseMoonshotTradeInstruction(instruction)
 			if err != nil {
+				p.recordDecodeError(MOONSHOT_PROGRAM_ID, err)
 				continue
 			}
 			swaps = append(swaps, *swapData)
@@ -48,17 +48,15 @@ func (p *Parser) processMoonshotSwaps() []SwapData {
 
 // isMoonshotTrade checks if the instruction is a Moonshot trade
 func (p *Parser) isMoonshotTrade(instruction solana.CompiledInstruction) bool {
-	return p.txInfo.Message.AccountKeys[instruction.ProgramIDIndex].Equals(MOONSHOT_PROGRAM_ID) && len(instruction.Data) == 33 && len(instruction.Accounts) == 11
+	if int(instruction.ProgramIDIndex) >= len(p.allAccountKeys) {
+		return false
+	}
+	return p.allAccountKeys[instruction.ProgramIDIndex].Equals(MOONSHOT_PROGRAM_ID) && len(instruction.Data) == 33 && len(instruction.Accounts) == 11
 }
 
 // parseMoonshotTradeInstruction parses a Moonshot trade instruction
 func (p *Parser) parseMoonshotTradeInstruction(instruction solana.CompiledInstruction) (*SwapData, error) {
-	decodedBytes, err := base58.Decode(instruction.Data.String())
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode base58 instruction data: %v", err)
-	}
-
-	discriminator := decodedBytes[:8]
+	discriminator := instruction.Data[:8]
 	var tradeType TradeType
 
 	switch {
@@ -70,7 +68,10 @@ func (p *Parser) parseMoonshotTradeInstruction(instruction solana.CompiledInstru
 		return nil, fmt.Errorf("unknown moonshot trade instruction")
 	}
 
-	moonshotTokenMint := p.txInfo.Message.AccountKeys[instruction.Accounts[6]]
+	if int(instruction.Accounts[6]) >= len(p.allAccountKeys) {
+		return nil, fmt.Errorf("moonshot mint account index out of range: %d", instruction.Accounts[6])
+	}
+	moonshotTokenMint := p.allAccountKeys[instruction.Accounts[6]]
 
 	moonshotTokenBalanceChanges, err := p.getTokenBalanceChanges(moonshotTokenMint)
 	if err != nil {
@@ -26,39 +26,87 @@ type TokenInfo struct {
 }
 
 func (p *Parser) processRaydSwaps(instructionIndex int) []SwapData {
+	if swaps, ok := p.processRaydiumSwapEvents(instructionIndex); ok {
+		return swaps
+	}
+
 	var swaps []SwapData
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				switch {
-				case p.isTransfer(innerInstruction):
-					transfer := p.processTransfer(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: RAYDIUM, Data: transfer})
-					}
-				case p.isTransferCheck(innerInstruction):
-					transfer := p.processTransferCheck(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: RAYDIUM, Data: transfer})
-					}
-				}
+	for _, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		switch {
+		case p.isTransfer(innerInstruction):
+			transfer := p.processTransfer(innerInstruction)
+			if transfer != nil {
+				swaps = append(swaps, SwapData{Type: RAYDIUM, Data: transfer})
+			}
+		case p.isTransferCheck(innerInstruction):
+			transfer := p.processTransferCheck(innerInstruction)
+			if transfer != nil {
+				swaps = append(swaps, SwapData{Type: RAYDIUM, Data: transfer})
 			}
 		}
 	}
 	return swaps
 }
 
+// processRaydiumSwapEvents 从指令参数和日志解析第 instructionIndex 个外层指令及其内部指令中的 Raydium 交换。
+// 只有其中每个 Raydium 池子的调用都能解析时才返回 true，否则由调用方退回到按转账解析
+func (p *Parser) processRaydiumSwapEvents(instructionIndex int) ([]SwapData, bool) {
+	var swaps []SwapData
+	instructions := append([]solana.CompiledInstruction{p.txInfo.Message.Instructions[instructionIndex]}, p.getInnerInstructions(instructionIndex)...)
+	for i, instruction := range instructions {
+		if int(instruction.ProgramIDIndex) >= len(p.allAccountKeys) {
+			continue
+		}
+		switch program := p.allAccountKeys[instruction.ProgramIDIndex]; {
+		case p.isRaydiumV4SwapInstruction(instruction):
+			event, err := p.parseRaydiumV4Swap(instructionIndex, i-1, instruction)
+			if err != nil {
+				p.recordDecodeError(RAYDIUM_V4_PROGRAM_ID, err)
+				return nil, false
+			}
+			if event == nil {
+				return nil, false
+			}
+			swaps = append(swaps, SwapData{Type: RAYDIUM, Data: event})
+		case program.Equals(RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID):
+			isSwap, v2 := p.isRaydiumCLMMSwapInstruction(instruction)
+			if !isSwap {
+				return nil, false
+			}
+			event, err := p.parseRaydiumCLMMSwap(instructionIndex, i-1, instruction, v2)
+			if err != nil {
+				p.recordDecodeError(RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID, err)
+				return nil, false
+			}
+			if event == nil {
+				return nil, false
+			}
+			swaps = append(swaps, SwapData{Type: RAYDIUM, Data: event})
+		case program.Equals(RAYDIUM_CPMM_PROGRAM_ID):
+			if !p.isRaydiumCPMMSwapInstruction(instruction) {
+				return nil, false
+			}
+			event, err := p.parseRaydiumCPMMSwap(instructionIndex, i-1, instruction)
+			if err != nil {
+				p.recordDecodeError(RAYDIUM_CPMM_PROGRAM_ID, err)
+				return nil, false
+			}
+			if event == nil {
+				return nil, false
+			}
+			swaps = append(swaps, SwapData{Type: RAYDIUM, Data: event})
+		}
+	}
+	return swaps, len(swaps) > 0
+}
+
 func (p *Parser) processOrcaSwaps(instructionIndex int) []SwapData {
 	var swaps []SwapData
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				if p.isTransfer(innerInstruction) {
-					transfer := p.processTransfer(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: ORCA, Data: transfer})
-					}
-				}
+	for _, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		if p.isTransfer(innerInstruction) {
+			transfer := p.processTransfer(innerInstruction)
+			if transfer != nil {
+				swaps = append(swaps, SwapData{Type: ORCA, Data: transfer})
 			}
 		}
 	}
@@ -66,12 +114,11 @@ func (p *Parser) processOrcaSwaps(instructionIndex int) []SwapData {
 }
 
 func (p *Parser) processTransfer(instr solana.CompiledInstruction) *TransferData {
-	amount := binary.LittleEndian.Uint64(instr.Data[1:9])
-
-	// Add bounds checking for account indices
-	if len(instr.Accounts) < 3 {
+	// Add bounds checking for data and account indices
+	if len(instr.Data) < 9 || len(instr.Accounts) < 3 {
 		return nil
 	}
+	amount := binary.LittleEndian.Uint64(instr.Data[1:9])
 
 	for _, accountIndex := range instr.Accounts[:3] {
 		if int(accountIndex) >= len(p.allAccountKeys) {
@@ -79,13 +126,13 @@ func (p *Parser) processTransfer(instr solana.CompiledInstruction) *TransferData
 		}
 	}
 
-	destinationKey := p.allAccountKeys[instr.Accounts[1]].String()
+	destinationKey := p.allAccountKeys[instr.Accounts[1]]
 
 	transferData := &TransferData{
 		Info: TransferInfo{
 			Amount:      amount,
 			Source:      p.allAccountKeys[instr.Accounts[0]].String(),
-			Destination: destinationKey,
+			Destination: destinationKey.String(),
 			Authority:   p.allAccountKeys[instr.Accounts[2]].String(),
 		},
 		Type:     "transfer",
@@ -101,7 +148,7 @@ func (p *Parser) processTransfer(instr solana.CompiledInstruction) *TransferData
 }
 
 func (p *Parser) extractSPLTokenInfo() error {
-	splTokenAddresses := make(map[string]TokenInfo)
+	splTokenAddresses := make(map[solana.PublicKey]TokenInfo, len(p.txMeta.PostTokenBalances))
 
 	for _, accountInfo := range p.txMeta.PostTokenBalances {
 		if !accountInfo.Mint.IsZero() {
@@ -109,7 +156,7 @@ func (p *Parser) extractSPLTokenInfo() error {
 			if int(accountInfo.AccountIndex) >= len(p.allAccountKeys) {
 				continue
 			}
-			accountKey := p.allAccountKeys[accountInfo.AccountIndex].String()
+			accountKey := p.allAccountKeys[accountInfo.AccountIndex]
 			splTokenAddresses[accountKey] = TokenInfo{
 				Mint:     accountInfo.Mint.String(),
 				Decimals: accountInfo.UiTokenAmount.Decimals,
@@ -143,8 +190,8 @@ func (p *Parser) extractSPLTokenInfo() error {
 			return
 		}
 
-		source := p.allAccountKeys[instr.Accounts[0]].String()
-		destination := p.allAccountKeys[instr.Accounts[1]].String()
+		source := p.allAccountKeys[instr.Accounts[0]]
+		destination := p.allAccountKeys[instr.Accounts[1]]
 
 		if _, exists := splTokenAddresses[source]; !exists {
 			splTokenAddresses[source] = TokenInfo{Mint: "", Decimals: 0}
@@ -27,21 +27,17 @@ type TransferCheck struct {
 
 func (p *Parser) processMeteoraSwaps(instructionIndex int) []SwapData {
 	var swaps []SwapData
-	for _, innerInstructionSet := range p.txMeta.InnerInstructions {
-		if innerInstructionSet.Index == uint16(instructionIndex) {
-			for _, innerInstruction := range innerInstructionSet.Instructions {
-				switch {
-				case p.isTransferCheck(innerInstruction):
-					transfer := p.processTransferCheck(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: METEORA, Data: transfer})
-					}
-				case p.isTransfer(innerInstruction):
-					transfer := p.processTransfer(innerInstruction)
-					if transfer != nil {
-						swaps = append(swaps, SwapData{Type: METEORA, Data: transfer})
-					}
-				}
+	for _, innerInstruction := range p.getInnerInstructions(instructionIndex) {
+		switch {
+		case p.isTransferCheck(innerInstruction):
+			transfer := p.processTransferCheck(innerInstruction)
+			if transfer != nil {
+				swaps = append(swaps, SwapData{Type: METEORA, Data: transfer})
+			}
+		case p.isTransfer(innerInstruction):
+			transfer := p.processTransfer(innerInstruction)
+			if transfer != nil {
+				swaps = append(swaps, SwapData{Type: METEORA, Data: transfer})
 			}
 		}
 	}
@@ -68,11 +64,12 @@ func (p *Parser) processTransferCheck(instr solana.CompiledInstruction) *Transfe
 
 	transferData.Info.Source = p.allAccountKeys[instr.Accounts[0]].String()
 	transferData.Info.Destination = p.allAccountKeys[instr.Accounts[2]].String()
-	transferData.Info.Mint = p.allAccountKeys[instr.Accounts[1]].String()
+	mint := p.allAccountKeys[instr.Accounts[1]]
+	transferData.Info.Mint = mint.String()
 	transferData.Info.Authority = p.allAccountKeys[instr.Accounts[3]].String()
 
 	transferData.Info.TokenAmount.Amount = fmt.Sprintf("%d", amount)
-	transferData.Info.TokenAmount.Decimals = p.splDecimalsMap[transferData.Info.Mint]
+	transferData.Info.TokenAmount.Decimals = p.splDecimalsMap[mint]
 	uiAmount := float64(amount) / math.Pow10(int(transferData.Info.TokenAmount.Decimals))
 	transferData.Info.TokenAmount.UIAmount = uiAmount
 	transferData.Info.TokenAmount.UIAmountString = strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.9f", uiAmount), "0"), ".")
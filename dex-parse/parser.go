@@ -2,6 +2,8 @@ package solanaswapgo
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strconv"
 	"time"
 
@@ -11,82 +13,87 @@ import (
 )
 
 const (
-	PROTOCOL_RAYDIUM = "raydium"
-	PROTOCOL_ORCA    = "orca"
-	PROTOCOL_METEORA = "meteora"
-	PROTOCOL_PUMPFUN = "pumpfun"
+	PROTOCOL_RAYDIUM  = "raydium"
+	PROTOCOL_ORCA     = "orca"
+	PROTOCOL_METEORA  = "meteora"
+	PROTOCOL_PUMPFUN  = "pumpfun"
+	PROTOCOL_PUMPSWAP = "pumpswap"
+
+	PROTOCOL_RAYDIUM_LAUNCHLAB = "raydium_launchlab"
 )
 
 type TokenTransfer struct {
-	mint     string
+	mint     solana.PublicKey
 	amount   uint64
 	decimals uint8
 }
 
 type Parser struct {
-	txResult        *rpc.GetTransactionResult
-	txMeta          *rpc.TransactionMeta
-	txInfo          *solana.Transaction
-	allAccountKeys  solana.PublicKeySlice
-	splTokenInfoMap map[string]TokenInfo
-	splDecimalsMap  map[string]uint8
-	Log             *logrus.Logger
+	txResult          *rpc.GetTransactionResult
+	txMeta            *rpc.TransactionMeta
+	txInfo            *solana.Transaction
+	allAccountKeys    solana.PublicKeySlice
+	innerInstructions [][]solana.CompiledInstruction
+	splTokenInfoMap   map[solana.PublicKey]TokenInfo
+	splDecimalsMap    map[solana.PublicKey]uint8
+	decodeErrors      []DecodeError
+	invocationLogs    map[string][][]string
+	Log               *logrus.Logger
 }
 
-func NewTransactionParser(tx *rpc.GetTransactionResult) (*Parser, error) {
-	txInfo, err := tx.Transaction.GetTransaction()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction: %w", err)
-	}
+// DefaultLogger 是新建解析器的 Parser.Log，所有解析器共享，避免为每笔交易创建 logrus.Logger。
+// 修改它会影响所有解析器，需要单独配置某个解析器时为它的 Log 赋值新的 Logger
+var DefaultLogger = newLogger(os.Stderr)
 
-	return NewTransactionParserFromTransactionResult(tx, txInfo, tx.Meta)
-}
-
-func NewTransactionParserFromTransactionResult(txResult *rpc.GetTransactionResult, tx *solana.Transaction, txMeta *rpc.TransactionMeta) (*Parser, error) {
-	allAccountKeys := append(tx.Message.AccountKeys, txMeta.LoadedAddresses.Writable...)
-	allAccountKeys = append(allAccountKeys, txMeta.LoadedAddresses.ReadOnly...)
+// DiscardLogger 丢弃所有输出，赋值给 Parser.Log 以关闭单个解析器的日志，如在区块中逐笔解析时
+var DiscardLogger = newLogger(io.Discard)
 
+func newLogger(out io.Writer) *logrus.Logger {
 	log := logrus.New()
+	log.SetOutput(out)
 	log.SetFormatter(&logrus.TextFormatter{
 		TimestampFormat: "2006-01-02 15:04:05",
 		FullTimestamp:   true,
 	})
+	return log
+}
 
-	parser := &Parser{
-		txResult:       txResult,
-		txMeta:         txMeta,
-		txInfo:         tx,
-		allAccountKeys: allAccountKeys,
-		Log:            log,
-	}
-
-	if err := parser.extractSPLTokenInfo(); err != nil {
-		return nil, fmt.Errorf("failed to extract SPL Token Addresses: %w", err)
+func NewTransactionParser(tx *rpc.GetTransactionResult) (*Parser, error) {
+	txInfo, err := tx.Transaction.GetTransaction()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
 	}
 
-	if err := parser.extractSPLDecimals(); err != nil {
-		return nil, fmt.Errorf("failed to extract SPL decimals: %w", err)
-	}
+	return NewTransactionParserFromTransactionResult(tx, txInfo, tx.Meta)
+}
 
-	return parser, nil
+func NewTransactionParserFromTransactionResult(txResult *rpc.GetTransactionResult, tx *solana.Transaction, txMeta *rpc.TransactionMeta) (*Parser, error) {
+	return newParser(txResult, tx, txMeta)
 }
 
 func NewTransactionParserFromTransaction(tx *solana.Transaction, txMeta *rpc.TransactionMeta) (*Parser, error) {
-	allAccountKeys := append(tx.Message.AccountKeys, txMeta.LoadedAddresses.Writable...)
-	allAccountKeys = append(allAccountKeys, txMeta.LoadedAddresses.ReadOnly...)
+	// 在这种情况下我们没有原始结果，所以 txResult 为 nil
+	return newParser(nil, tx, txMeta)
+}
 
-	log := logrus.New()
-	log.SetFormatter(&logrus.TextFormatter{
-		TimestampFormat: "2006-01-02 15:04:05",
-		FullTimestamp:   true,
-	})
+func newParser(txResult *rpc.GetTransactionResult, tx *solana.Transaction, txMeta *rpc.TransactionMeta) (*Parser, error) {
+	if tx == nil || txMeta == nil {
+		return nil, fmt.Errorf("transaction and meta are required")
+	}
+
+	// 重新分配切片，避免 append 写入 tx.Message.AccountKeys 的底层数组
+	allAccountKeys := make(solana.PublicKeySlice, 0, len(tx.Message.AccountKeys)+len(txMeta.LoadedAddresses.Writable)+len(txMeta.LoadedAddresses.ReadOnly))
+	allAccountKeys = append(allAccountKeys, tx.Message.AccountKeys...)
+	allAccountKeys = append(allAccountKeys, txMeta.LoadedAddresses.Writable...)
+	allAccountKeys = append(allAccountKeys, txMeta.LoadedAddresses.ReadOnly...)
 
 	parser := &Parser{
-		txResult:       nil, // 在这种情况下我们没有原始结果，所以设置为 nil
-		txMeta:         txMeta,
-		txInfo:         tx,
-		allAccountKeys: allAccountKeys,
-		Log:            log,
+		txResult:          txResult,
+		txMeta:            txMeta,
+		txInfo:            tx,
+		allAccountKeys:    allAccountKeys,
+		innerInstructions: indexInnerInstructions(len(tx.Message.Instructions), txMeta.InnerInstructions),
+		Log:               DefaultLogger,
 	}
 
 	if err := parser.extractSPLTokenInfo(); err != nil {
@@ -100,6 +107,24 @@ func NewTransactionParserFromTransaction(tx *solana.Transaction, txMeta *rpc.Tra
 	return parser, nil
 }
 
+// indexInnerInstructions 按外层指令下标建立内部指令索引，避免每次查找都遍历 meta
+func indexInnerInstructions(outerCount int, sets []rpc.InnerInstruction) [][]solana.CompiledInstruction {
+	index := make([][]solana.CompiledInstruction, outerCount)
+	for _, set := range sets {
+		if int(set.Index) >= outerCount {
+			continue
+		}
+		if index[set.Index] == nil {
+			index[set.Index] = set.Instructions
+		} else {
+			// 限制容量，避免 append 覆盖 meta 中的原始切片
+			existing := index[set.Index]
+			index[set.Index] = append(existing[:len(existing):len(existing)], set.Instructions...)
+		}
+	}
+	return index
+}
+
 // GetBlockTime 返回区块时间戳，如果可用的话
 func (p *Parser) GetBlockTime() *time.Time {
 	if p.txResult != nil && p.txResult.BlockTime != nil {
@@ -112,37 +137,107 @@ func (p *Parser) GetBlockTime() *time.Time {
 type SwapData struct {
 	Type SwapType
 	Data interface{}
+	// Platform 为交换所属的发射平台，目前只用于 Raydium LaunchLab（按 LaunchLabPlatforms 识别），未识别时为空
+	Platform string `json:",omitempty"`
+}
+
+// programKind 标识程序所属的协议，ParseTransaction 通过查表代替逐个 Equals 比较
+type programKind uint8
+
+const (
+	programUnknown programKind = iota
+	programJupiter
+	programMoonshot
+	programBoopFun
+	programTradingBot
+	programOKX
+	programRaydium
+	programRaydiumProxy
+	programRaydiumLaunchLab
+	programOrca
+	programMeteora
+	programMeteoraDAMMv2
+	programMeteoraDBC
+	programPumpfunAMM
+	programPumpfun
+	programPumpfunProxy
+)
+
+var programKinds = map[solana.PublicKey]programKind{
+	JUPITER_PROGRAM_ID:                        programJupiter,
+	MOONSHOT_PROGRAM_ID:                       programMoonshot,
+	BOOPFUN_PROGRAM_ID:                        programBoopFun,
+	BANANA_GUN_PROGRAM_ID:                     programTradingBot,
+	MINTECH_PROGRAM_ID:                        programTradingBot,
+	BLOOM_PROGRAM_ID:                          programTradingBot,
+	NOVA_PROGRAM_ID:                           programTradingBot,
+	MAESTRO_PROGRAM_ID:                        programTradingBot,
+	OKX_DEX_ROUTER_PROGRAM_ID:                 programOKX,
+	RAYDIUM_V4_PROGRAM_ID:                     programRaydium,
+	RAYDIUM_CPMM_PROGRAM_ID:                   programRaydium,
+	RAYDIUM_AMM_PROGRAM_ID:                    programRaydium,
+	RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID: programRaydium,
+	RAYDIUM_PROXY_PROGRAM_ID:                  programRaydiumProxy,
+	RAYDIUM_LAUNCHLAB_PROGRAM_ID:              programRaydiumLaunchLab,
+	ORCA_PROGRAM_ID:                           programOrca,
+	METEORA_PROGRAM_ID:                        programMeteora,
+	METEORA_POOLS_PROGRAM_ID:                  programMeteora,
+	METEORA_DLMM_PROGRAM_ID:                   programMeteora,
+	METEORA_DAMM_V2_PROGRAM_ID:                programMeteoraDAMMv2,
+	METEORA_DBC_PROGRAM_ID:                    programMeteoraDBC,
+	PUMPFUN_AMM_PROGRAM_ID:                    programPumpfunAMM,
+	PUMP_FUN_PROGRAM_ID:                       programPumpfun,
+	PUMP_FUN_PROXY_PROGRAM_ID:                 programPumpfunProxy,
+}
+
+// programKindAt 返回账户下标对应程序的协议类型，越界时返回 programUnknown
+func (p *Parser) programKindAt(index uint16) programKind {
+	if int(index) >= len(p.allAccountKeys) {
+		return programUnknown
+	}
+	return programKinds[p.allAccountKeys[index]]
 }
 
 func (p *Parser) ParseTransaction() ([]SwapData, error) {
-	var parsedSwaps []SwapData
+	// 重复调用时只保留本次解析的错误
+	p.decodeErrors = nil
+	holder := observer.Load()
+	if holder == nil {
+		return p.parseTransaction()
+	}
+
+	start := time.Now()
+	swaps, err := p.parseTransaction()
+	holder.observer.ObserveParse(ParseStats{
+		Duration:        time.Since(start),
+		Swaps:           swaps,
+		DecodeErrors:    p.decodeErrors,
+		UnknownPrograms: p.unknownPrograms(),
+	})
+	return swaps, err
+}
+
+func (p *Parser) parseTransaction() ([]SwapData, error) {
+	instructions := p.txInfo.Message.Instructions
+	parsedSwaps := make([]SwapData, 0, len(instructions))
 
 	skip := false
-	for i, outerInstruction := range p.txInfo.Message.Instructions {
-		// Add bounds checking for ProgramIDIndex
-		if int(outerInstruction.ProgramIDIndex) >= len(p.allAccountKeys) {
-			continue
-		}
-		progID := p.allAccountKeys[outerInstruction.ProgramIDIndex]
-		switch {
-		case progID.Equals(JUPITER_PROGRAM_ID):
+	for i, outerInstruction := range instructions {
+		switch p.programKindAt(outerInstruction.ProgramIDIndex) {
+		case programJupiter:
 			skip = true
 			parsedSwaps = append(parsedSwaps, p.processJupiterSwaps(i)...)
-		case progID.Equals(MOONSHOT_PROGRAM_ID):
+		case programMoonshot:
 			skip = true
 			parsedSwaps = append(parsedSwaps, p.processMoonshotSwaps()...)
-		case progID.Equals(BOOPFUN_PROGRAM_ID):
+		case programBoopFun:
 			skip = true
 			parsedSwaps = append(parsedSwaps, p.processBoopFunSwaps(i)...)
-		case progID.Equals(BANANA_GUN_PROGRAM_ID) ||
-			progID.Equals(MINTECH_PROGRAM_ID) ||
-			progID.Equals(BLOOM_PROGRAM_ID) ||
-			progID.Equals(NOVA_PROGRAM_ID) ||
-			progID.Equals(MAESTRO_PROGRAM_ID):
+		case programTradingBot:
 			if innerSwaps := p.processRouterSwaps(i); len(innerSwaps) > 0 {
 				parsedSwaps = append(parsedSwaps, innerSwaps...)
 			}
-		case progID.Equals(OKX_DEX_ROUTER_PROGRAM_ID):
+		case programOKX:
 			skip = true
 			parsedSwaps = append(parsedSwaps, p.processOKXSwaps(i)...)
 		}
@@ -151,33 +246,23 @@ func (p *Parser) ParseTransaction() ([]SwapData, error) {
 		return parsedSwaps, nil
 	}
 
-	for i, outerInstruction := range p.txInfo.Message.Instructions {
-		// Add bounds checking for ProgramIDIndex
-		if int(outerInstruction.ProgramIDIndex) >= len(p.allAccountKeys) {
-			continue
-		}
-		progID := p.allAccountKeys[outerInstruction.ProgramIDIndex]
-		switch {
-		case progID.Equals(RAYDIUM_V4_PROGRAM_ID) ||
-			progID.Equals(RAYDIUM_CPMM_PROGRAM_ID) ||
-			progID.Equals(RAYDIUM_AMM_PROGRAM_ID) ||
-			progID.Equals(RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID) ||
-			progID.Equals(solana.MustPublicKeyFromBase58("AP51WLiiqTdbZfgyRMs35PsZpdmLuPDdHYmrB23pEtMU")):
+	for i, outerInstruction := range instructions {
+		switch p.programKindAt(outerInstruction.ProgramIDIndex) {
+		case programRaydium, programRaydiumProxy:
 			parsedSwaps = append(parsedSwaps, p.processRaydSwaps(i)...)
-		case progID.Equals(RAYDIUM_LAUNCHLAB_PROGRAM_ID):
+		case programRaydiumLaunchLab:
 			parsedSwaps = append(parsedSwaps, p.processRaydiumLaunchLabSwaps(i)...)
-		case progID.Equals(ORCA_PROGRAM_ID):
+		case programOrca:
 			parsedSwaps = append(parsedSwaps, p.processOrcaSwaps(i)...)
-		case progID.Equals(METEORA_DAMM_V2_PROGRAM_ID):
+		case programMeteoraDAMMv2:
 			parsedSwaps = append(parsedSwaps, p.processMeteoraDAMMv2Swaps(i)...)
-		case progID.Equals(METEORA_DBC_PROGRAM_ID):
+		case programMeteoraDBC:
 			parsedSwaps = append(parsedSwaps, p.processMeteoraDBCSwaps(i)...)
-		case progID.Equals(METEORA_PROGRAM_ID) || progID.Equals(METEORA_POOLS_PROGRAM_ID) || progID.Equals(METEORA_DLMM_PROGRAM_ID):
+		case programMeteora:
 			parsedSwaps = append(parsedSwaps, p.processMeteoraSwaps(i)...)
-		case progID.Equals(PUMPFUN_AMM_PROGRAM_ID):
+		case programPumpfunAMM:
 			parsedSwaps = append(parsedSwaps, p.processPumpfunAMMSwaps(i)...)
-		case progID.Equals(PUMP_FUN_PROGRAM_ID) ||
-			progID.Equals(solana.MustPublicKeyFromBase58("BSfD6SHZigAfDWSjzD5Q41jw8LmKwtmjskPH9XW1mrRW")):
+		case programPumpfun, programPumpfunProxy:
 			parsedSwaps = append(parsedSwaps, p.processPumpfunSwaps(i)...)
 		}
 	}
@@ -190,6 +275,8 @@ type SwapInfo struct {
 	Signatures []solana.Signature
 	AMMs       []string
 	Timestamp  time.Time
+	// Platform 为第一个带有发射平台的交换腿的 Platform，没有时为空
+	Platform string `json:",omitempty"`
 
 	TokenInMint     solana.PublicKey
 	TokenInAmount   uint64
@@ -198,6 +285,21 @@ type SwapInfo struct {
 	TokenOutMint     solana.PublicKey
 	TokenOutAmount   uint64
 	TokenOutDecimals uint8
+
+	// Fees 为事件中记录的协议费用，事件不包含费用时为 nil
+	Fees *SwapFees
+}
+
+// SwapFees 是交换支付给协议、发射平台和代币创作者的费用，以 Mint 的最小单位计
+type SwapFees struct {
+	Mint        solana.PublicKey
+	Decimals    uint8
+	ProtocolFee uint64
+	CreatorFee  uint64
+	LPFee       uint64 // 留在池子中给流动性提供者的费用
+	PlatformFee uint64 // 发射平台收取的费用
+	// TradeFee 为事件只给出总额、没有拆分给各方的交易费用，不与 LPFee、ProtocolFee 重复计算
+	TradeFee uint64
 }
 
 func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
@@ -208,6 +310,12 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 	swapInfo := &SwapInfo{
 		Signatures: p.txInfo.Signatures,
 	}
+	for _, swapData := range swapDatas {
+		if swapData.Platform != "" {
+			swapInfo.Platform = swapData.Platform
+			break
+		}
+	}
 
 	if p.containsDCAProgram() {
 		if len(p.allAccountKeys) > 2 {
@@ -225,6 +333,8 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 
 	jupiterSwaps := make([]SwapData, 0)
 	pumpfunSwaps := make([]SwapData, 0)
+	pumpSwapSwaps := make([]SwapData, 0)
+	raydiumSwaps := make([]SwapData, 0)
 	raydiumLaunchLabSwaps := make([]SwapData, 0)
 	meteoraDAMMv2Swaps := make([]SwapData, 0)
 	boopFunSwaps := make([]SwapData, 0)
@@ -236,6 +346,15 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 			jupiterSwaps = append(jupiterSwaps, swapData)
 		case PUMP_FUN:
 			pumpfunSwaps = append(pumpfunSwaps, swapData)
+		case PUMP_SWAP:
+			pumpSwapSwaps = append(pumpSwapSwaps, swapData)
+		case RAYDIUM:
+			// 由日志或事件解析出的交换单独处理，按转账解析的交换使用通用逻辑
+			if _, _, ok := raydiumSwapAmounts(swapData); ok {
+				raydiumSwaps = append(raydiumSwaps, swapData)
+			} else {
+				otherSwaps = append(otherSwaps, swapData)
+			}
 		case RAYDIUM_LAUNCHLAB:
 			raydiumLaunchLabSwaps = append(raydiumLaunchLabSwaps, swapData)
 		case METEORA:
@@ -287,44 +406,87 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 				swapInfo.TokenInDecimals = 9
 				swapInfo.TokenOutMint = data.Mint
 				swapInfo.TokenOutAmount = data.TokenAmount
-				swapInfo.TokenOutDecimals = p.splDecimalsMap[data.Mint.String()]
+				swapInfo.TokenOutDecimals = p.splDecimalsMap[data.Mint]
 			} else {
 				swapInfo.TokenInMint = data.Mint
 				swapInfo.TokenInAmount = data.TokenAmount
-				swapInfo.TokenInDecimals = p.splDecimalsMap[data.Mint.String()]
+				swapInfo.TokenInDecimals = p.splDecimalsMap[data.Mint]
 				swapInfo.TokenOutMint = NATIVE_SOL_MINT_PROGRAM_ID
 				swapInfo.TokenOutAmount = data.SolAmount
 				swapInfo.TokenOutDecimals = 9
 			}
 			swapInfo.AMMs = append(swapInfo.AMMs, string(pumpfunSwaps[0].Type))
 			swapInfo.Timestamp = time.Unix(int64(data.Timestamp), 0)
+			if data.Version >= PumpfunTradeEventV3 {
+				swapInfo.Fees = &SwapFees{
+					Mint:        NATIVE_SOL_MINT_PROGRAM_ID,
+					Decimals:    9,
+					ProtocolFee: data.Fee,
+					CreatorFee:  data.CreatorFee,
+				}
+			}
 			return swapInfo, nil
 		default:
 			otherSwaps = append(otherSwaps, pumpfunSwaps...)
 		}
 	}
 
+	if len(pumpSwapSwaps) > 0 {
+		switch data := pumpSwapSwaps[0].Data.(type) {
+		case *PumpfunAMMBuyEvent:
+			swapInfo.TokenInMint = data.QuoteMint
+			swapInfo.TokenInAmount = data.UserQuoteAmountIn
+			swapInfo.TokenInDecimals = data.QuoteMintDecimals
+			swapInfo.TokenOutMint = data.BaseMint
+			swapInfo.TokenOutAmount = data.BaseAmountOut
+			swapInfo.TokenOutDecimals = data.BaseMintDecimals
+			swapInfo.AMMs = append(swapInfo.AMMs, string(pumpSwapSwaps[0].Type))
+			swapInfo.Timestamp = time.Unix(data.Timestamp, 0)
+			swapInfo.Fees = &SwapFees{
+				Mint:        data.QuoteMint,
+				Decimals:    data.QuoteMintDecimals,
+				ProtocolFee: data.ProtocolFee,
+				CreatorFee:  data.CoinCreatorFee,
+				LPFee:       data.LpFee,
+			}
+			return swapInfo, nil
+		case *PumpfunAMMSellEvent:
+			swapInfo.TokenInMint = data.BaseMint
+			swapInfo.TokenInAmount = data.BaseAmountIn
+			swapInfo.TokenInDecimals = data.BaseMintDecimals
+			swapInfo.TokenOutMint = data.QuoteMint
+			swapInfo.TokenOutAmount = data.UserQuoteAmountOut
+			swapInfo.TokenOutDecimals = data.QuoteMintDecimals
+			swapInfo.AMMs = append(swapInfo.AMMs, string(pumpSwapSwaps[0].Type))
+			swapInfo.Timestamp = time.Unix(data.Timestamp, 0)
+			swapInfo.Fees = &SwapFees{
+				Mint:        data.QuoteMint,
+				Decimals:    data.QuoteMintDecimals,
+				ProtocolFee: data.ProtocolFee,
+				CreatorFee:  data.CoinCreatorFee,
+				LPFee:       data.LpFee,
+			}
+			return swapInfo, nil
+		default:
+			otherSwaps = append(otherSwaps, pumpSwapSwaps...)
+		}
+	}
+
 	if len(raydiumLaunchLabSwaps) > 0 {
 		switch data := raydiumLaunchLabSwaps[0].Data.(type) {
-		case *RaydiumLaunchLabBuyEvent:
-			if data.IsBuy {
-				// Raydium LaunchLab 买入交易：SOL -> Token
-				swapInfo.TokenInMint = NATIVE_SOL_MINT_PROGRAM_ID
-				swapInfo.TokenInAmount = data.AmountIn
-				swapInfo.TokenInDecimals = 9
-				swapInfo.TokenOutMint = data.TokenMint
-				swapInfo.TokenOutAmount = data.AmountOut
-				swapInfo.TokenOutDecimals = data.TokenDecimals
-			} else {
-				// Raydium LaunchLab 卖出交易：Token -> SOL
-				swapInfo.TokenInMint = data.TokenMint
-				swapInfo.TokenInAmount = data.AmountIn
-				swapInfo.TokenInDecimals = data.TokenDecimals
-				swapInfo.TokenOutMint = NATIVE_SOL_MINT_PROGRAM_ID
-				swapInfo.TokenOutAmount = data.AmountOut
-				swapInfo.TokenOutDecimals = 9
-			}
+		case *RaydiumLaunchLabTradeEvent:
+			swapInfo.TokenInMint, swapInfo.TokenInAmount, swapInfo.TokenInDecimals = data.Input()
+			swapInfo.TokenOutMint, swapInfo.TokenOutAmount, swapInfo.TokenOutDecimals = data.Output()
 			swapInfo.AMMs = append(swapInfo.AMMs, string(raydiumLaunchLabSwaps[0].Type))
+			if data.Version > 0 {
+				swapInfo.Fees = &SwapFees{
+					Mint:        data.QuoteMint,
+					Decimals:    data.QuoteDecimals,
+					ProtocolFee: data.ProtocolFee,
+					CreatorFee:  data.CreatorFee,
+					PlatformFee: data.PlatformFee,
+				}
+			}
 
 			// 使用区块时间戳，如果不可用则使用当前时间
 			if blockTime := p.GetBlockTime(); blockTime != nil {
@@ -404,9 +566,52 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 		}
 	}
 
+	if len(raydiumSwaps) > 0 {
+		if len(otherSwaps) == 0 {
+			// 交换可能依次经过多个池子：输入为第一个池子的输入代币，输出为最后一个池子的输出代币
+			first, _, _ := raydiumSwapAmounts(raydiumSwaps[0])
+			_, last, _ := raydiumSwapAmounts(raydiumSwaps[len(raydiumSwaps)-1])
+			swapInfo.TokenInMint = first.mint
+			swapInfo.TokenInDecimals = first.decimals
+			swapInfo.TokenOutMint = last.mint
+			swapInfo.TokenOutDecimals = last.decimals
+			for _, swapData := range raydiumSwaps {
+				in, out, _ := raydiumSwapAmounts(swapData)
+				if in.mint == first.mint {
+					swapInfo.TokenInAmount += in.amount
+				}
+				if out.mint == last.mint {
+					swapInfo.TokenOutAmount += out.amount
+				}
+			}
+			swapInfo.AMMs = append(swapInfo.AMMs, string(raydiumSwaps[0].Type))
+
+			// CPMM V2 事件记录以输入代币计的交易费用，包含 LP、协议和基金的份额但没有拆分，计入 TradeFee；
+			// 以输出代币计的创作者费用与 Mint 不同，不计入 SwapFees，见 RaydiumCPMMSwapEvent.OutputCreatorFee
+			if data, ok := raydiumSwaps[0].Data.(*RaydiumCPMMSwapEvent); ok && len(raydiumSwaps) == 1 && data.Version >= RaydiumCPMMSwapEventV2 {
+				swapInfo.Fees = &SwapFees{
+					Mint:     data.InputMint,
+					Decimals: data.InputDecimals,
+					TradeFee: data.TradeFee,
+				}
+				swapInfo.Fees.CreatorFee = data.InputCreatorFee()
+			}
+
+			// 使用区块时间戳，如果不可用则使用当前时间
+			if blockTime := p.GetBlockTime(); blockTime != nil {
+				swapInfo.Timestamp = *blockTime
+			} else {
+				swapInfo.Timestamp = time.Now()
+			}
+			return swapInfo, nil
+		}
+		// 与其他协议的转账混合时只取输入代币，使用通用逻辑
+		otherSwaps = append(otherSwaps, raydiumSwaps...)
+	}
+
 	if len(otherSwaps) > 0 {
 		var uniqueTokens []TokenTransfer
-		seenTokens := make(map[string]bool)
+		seenTokens := make(map[solana.PublicKey]bool)
 
 		for _, swapData := range otherSwaps {
 			transfer := getTransferFromSwapData(swapData)
@@ -420,8 +625,8 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 			inputTransfer := uniqueTokens[0]
 			outputTransfer := uniqueTokens[len(uniqueTokens)-1]
 
-			seenInputs := make(map[string]bool)
-			seenOutputs := make(map[string]bool)
+			seenInputs := make(map[TokenTransfer]bool)
+			seenOutputs := make(map[TokenTransfer]bool)
 			var totalInputAmount uint64 = 0
 			var totalOutputAmount uint64 = 0
 
@@ -431,21 +636,21 @@ func (p *Parser) ProcessSwapData(swapDatas []SwapData) (*SwapInfo, error) {
 					continue
 				}
 
-				amountStr := fmt.Sprintf("%d-%s", transfer.amount, transfer.mint)
-				if transfer.mint == inputTransfer.mint && !seenInputs[amountStr] {
+				amountKey := TokenTransfer{mint: transfer.mint, amount: transfer.amount}
+				if transfer.mint == inputTransfer.mint && !seenInputs[amountKey] {
 					totalInputAmount += transfer.amount
-					seenInputs[amountStr] = true
+					seenInputs[amountKey] = true
 				}
-				if transfer.mint == outputTransfer.mint && !seenOutputs[amountStr] {
+				if transfer.mint == outputTransfer.mint && !seenOutputs[amountKey] {
 					totalOutputAmount += transfer.amount
-					seenOutputs[amountStr] = true
+					seenOutputs[amountKey] = true
 				}
 			}
 
-			swapInfo.TokenInMint = solana.MustPublicKeyFromBase58(inputTransfer.mint)
+			swapInfo.TokenInMint = inputTransfer.mint
 			swapInfo.TokenInAmount = totalInputAmount
 			swapInfo.TokenInDecimals = inputTransfer.decimals
-			swapInfo.TokenOutMint = solana.MustPublicKeyFromBase58(outputTransfer.mint)
+			swapInfo.TokenOutMint = outputTransfer.mint
 			swapInfo.TokenOutAmount = totalOutputAmount
 			swapInfo.TokenOutDecimals = outputTransfer.decimals
 
@@ -475,27 +680,35 @@ func getTransferFromSwapData(swapData SwapData) *TokenTransfer {
 	case *MeteoraDAMMv2SwapEvent:
 		// 对于 Meteora DAMM v2 事件，返回输入代币信息
 		return &TokenTransfer{
-			mint:     data.TokenInMint.String(),
+			mint:     data.TokenInMint,
 			amount:   data.AmountIn,
 			decimals: data.TokenInDecimals,
 		}
 	case *MeteoraDBCSwapEvent:
 		// 对于 Meteora DBC 事件，返回输入代币信息
 		return &TokenTransfer{
-			mint:     data.TokenInMint.String(),
+			mint:     data.TokenInMint,
 			amount:   data.AmountIn,
 			decimals: data.TokenInDecimals,
 		}
 	case *BoopFunSwapEvent:
 		// 对于 Boop.fun 事件，返回输入 SOL 信息
 		return &TokenTransfer{
-			mint:     NATIVE_SOL_MINT_PROGRAM_ID.String(),
+			mint:     NATIVE_SOL_MINT_PROGRAM_ID,
 			amount:   data.BuyAmount,
 			decimals: 9,
 		}
+	case *RaydiumV4SwapEvent, *RaydiumCLMMSwapEvent, *RaydiumCPMMSwapEvent:
+		in, _, _ := raydiumSwapAmounts(swapData)
+		return in
 	case *TransferData:
+		// 转账的代币未知时 Mint 为 "Unknown"，无法作为输入输出
+		mint, err := solana.PublicKeyFromBase58(data.Mint)
+		if err != nil {
+			return nil
+		}
 		return &TokenTransfer{
-			mint:     data.Mint,
+			mint:     mint,
 			amount:   data.Info.Amount,
 			decimals: data.Decimals,
 		}
@@ -504,8 +717,12 @@ func getTransferFromSwapData(swapData SwapData) *TokenTransfer {
 		if err != nil {
 			return nil
 		}
+		mint, err := solana.PublicKeyFromBase58(data.Info.Mint)
+		if err != nil {
+			return nil
+		}
 		return &TokenTransfer{
-			mint:     data.Info.Mint,
+			mint:     mint,
 			amount:   amt,
 			decimals: data.Info.TokenAmount.Decimals,
 		}
@@ -513,6 +730,26 @@ func getTransferFromSwapData(swapData SwapData) *TokenTransfer {
 	return nil
 }
 
+// raydiumSwapAmounts 返回由日志或事件解析出的 Raydium 交换的输入和输出，按转账解析的交换返回 false
+func raydiumSwapAmounts(swapData SwapData) (*TokenTransfer, *TokenTransfer, bool) {
+	switch data := swapData.Data.(type) {
+	case *RaydiumV4SwapEvent:
+		return &TokenTransfer{mint: data.TokenInMint, amount: data.AmountIn, decimals: data.TokenInDecimals},
+			&TokenTransfer{mint: data.TokenOutMint, amount: data.AmountOut, decimals: data.TokenOutDecimals}, true
+	case *RaydiumCLMMSwapEvent:
+		inMint, inAmount, inDecimals := data.Input()
+		outMint, outAmount, outDecimals := data.Output()
+		return &TokenTransfer{mint: inMint, amount: inAmount, decimals: inDecimals},
+			&TokenTransfer{mint: outMint, amount: outAmount, decimals: outDecimals}, true
+	case *RaydiumCPMMSwapEvent:
+		inMint, inAmount, inDecimals := data.Input()
+		outMint, outAmount, outDecimals := data.Output()
+		return &TokenTransfer{mint: inMint, amount: inAmount, decimals: inDecimals},
+			&TokenTransfer{mint: outMint, amount: outAmount, decimals: outDecimals}, true
+	}
+	return nil, nil, false
+}
+
 func (p *Parser) processRouterSwaps(instructionIndex int) []SwapData {
 	var swaps []SwapData
 
@@ -524,46 +761,56 @@ func (p *Parser) processRouterSwaps(instructionIndex int) []SwapData {
 	processedProtocols := make(map[string]bool)
 
 	for _, inner := range innerInstructions {
-		// Add bounds checking for ProgramIDIndex in inner instructions
-		if int(inner.ProgramIDIndex) >= len(p.allAccountKeys) {
-			continue
-		}
-		progID := p.allAccountKeys[inner.ProgramIDIndex]
-
-		switch {
-		case (progID.Equals(RAYDIUM_V4_PROGRAM_ID) ||
-			progID.Equals(RAYDIUM_CPMM_PROGRAM_ID) ||
-			progID.Equals(RAYDIUM_AMM_PROGRAM_ID) ||
-			progID.Equals(RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID)) && !processedProtocols[PROTOCOL_RAYDIUM]:
+		switch p.programKindAt(inner.ProgramIDIndex) {
+		case programRaydium:
+			if processedProtocols[PROTOCOL_RAYDIUM] {
+				continue
+			}
 			processedProtocols[PROTOCOL_RAYDIUM] = true
 			if raydSwaps := p.processRaydSwaps(instructionIndex); len(raydSwaps) > 0 {
 				swaps = append(swaps, raydSwaps...)
 			}
 
-		case progID.Equals(ORCA_PROGRAM_ID) && !processedProtocols[PROTOCOL_ORCA]:
+		case programRaydiumLaunchLab:
+			if processedProtocols[PROTOCOL_RAYDIUM_LAUNCHLAB] {
+				continue
+			}
+			processedProtocols[PROTOCOL_RAYDIUM_LAUNCHLAB] = true
+			if launchLabSwaps := p.processRaydiumLaunchLabSwaps(instructionIndex); len(launchLabSwaps) > 0 {
+				swaps = append(swaps, launchLabSwaps...)
+			}
+
+		case programOrca:
+			if processedProtocols[PROTOCOL_ORCA] {
+				continue
+			}
 			processedProtocols[PROTOCOL_ORCA] = true
 			if orcaSwaps := p.processOrcaSwaps(instructionIndex); len(orcaSwaps) > 0 {
 				swaps = append(swaps, orcaSwaps...)
 			}
 
-		case (progID.Equals(METEORA_PROGRAM_ID) ||
-			progID.Equals(METEORA_POOLS_PROGRAM_ID) ||
-			progID.Equals(METEORA_DLMM_PROGRAM_ID) ||
-			progID.Equals(METEORA_DAMM_V2_PROGRAM_ID) ||
-			progID.Equals(METEORA_DBC_PROGRAM_ID)) && !processedProtocols[PROTOCOL_METEORA]:
+		case programMeteora, programMeteoraDAMMv2, programMeteoraDBC:
+			if processedProtocols[PROTOCOL_METEORA] {
+				continue
+			}
 			processedProtocols[PROTOCOL_METEORA] = true
 			if meteoraSwaps := p.processMeteoraSwaps(instructionIndex); len(meteoraSwaps) > 0 {
 				swaps = append(swaps, meteoraSwaps...)
 			}
 
-		case progID.Equals(PUMPFUN_AMM_PROGRAM_ID) && !processedProtocols[PROTOCOL_PUMPFUN]:
-			processedProtocols[PROTOCOL_PUMPFUN] = true
+		case programPumpfunAMM:
+			if processedProtocols[PROTOCOL_PUMPSWAP] {
+				continue
+			}
+			processedProtocols[PROTOCOL_PUMPSWAP] = true
 			if pumpfunAMMSwaps := p.processPumpfunAMMSwaps(instructionIndex); len(pumpfunAMMSwaps) > 0 {
 				swaps = append(swaps, pumpfunAMMSwaps...)
 			}
 
-		case (progID.Equals(PUMP_FUN_PROGRAM_ID) ||
-			progID.Equals(solana.MustPublicKeyFromBase58("BSfD6SHZigAfDWSjzD5Q41jw8LmKwtmjskPH9XW1mrRW"))) && !processedProtocols[PROTOCOL_PUMPFUN]:
+		case programPumpfun, programPumpfunProxy:
+			if processedProtocols[PROTOCOL_PUMPFUN] {
+				continue
+			}
 			processedProtocols[PROTOCOL_PUMPFUN] = true
 			if pumpfunSwaps := p.processPumpfunSwaps(instructionIndex); len(pumpfunSwaps) > 0 {
 				swaps = append(swaps, pumpfunSwaps...)
@@ -575,15 +822,8 @@ func (p *Parser) processRouterSwaps(instructionIndex int) []SwapData {
 }
 
 func (p *Parser) getInnerInstructions(index int) []solana.CompiledInstruction {
-	if p.txMeta == nil || p.txMeta.InnerInstructions == nil {
+	if index < 0 || index >= len(p.innerInstructions) {
 		return nil
 	}
-
-	for _, inner := range p.txMeta.InnerInstructions {
-		if inner.Index == uint16(index) {
-			return inner.Instructions
-		}
-	}
-
-	return nil
+	return p.innerInstructions[index]
 }
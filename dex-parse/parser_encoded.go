@@ -0,0 +1,438 @@
+package solanaswapgo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/mr-tron/base58"
+)
+
+// NewTransactionParserFromWire 从 wire 格式的交易字节和 meta 构建解析器
+func NewTransactionParserFromWire(wire []byte, txMeta *rpc.TransactionMeta) (*Parser, error) {
+	tx, err := solana.TransactionFromBytes(wire)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding wire transaction: %s", err)
+	}
+	return NewTransactionParserFromTransaction(tx, txMeta)
+}
+
+// NewTransactionParserFromEncoded 从 base58 / base64 / base64+zstd 编码的交易和 meta 构建解析器
+func NewTransactionParserFromEncoded(encoded string, encoding solana.EncodingType, txMeta *rpc.TransactionMeta) (*Parser, error) {
+	wire, err := decodeEncodedTransaction(encoded, encoding)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransactionParserFromWire(wire, txMeta)
+}
+
+// NewTransactionParserFromJSON 从 RPC 返回的 transaction 与 meta JSON 构建解析器。
+// transaction 可以是 ["<data>", "<encoding>"] 形式的编码数组、json 或 jsonParsed 对象；
+// meta 可以是 json 或 jsonParsed 编码。
+func NewTransactionParserFromJSON(txJSON []byte, metaJSON []byte) (*Parser, error) {
+	tx, meta, err := decodeTransactionAndMeta(txJSON, metaJSON)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransactionParserFromTransaction(tx, meta)
+}
+
+// NewTransactionParserFromResultJSON 从完整的 getTransaction 结果 JSON 构建解析器，
+// 与 NewTransactionParserFromJSON 一样支持所有编码，并保留 slot 与区块时间
+func NewTransactionParserFromResultJSON(resultJSON []byte) (*Parser, error) {
+	txResult, tx, err := DecodeTransactionResultJSON(resultJSON)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransactionParserFromTransactionResult(txResult, tx, txResult.Meta)
+}
+
+// DecodeTransactionResultJSON 解码完整的 getTransaction 结果 JSON，transaction 可以是任意编码。
+// 返回的结果中 Transaction 为空，交易单独返回
+func DecodeTransactionResultJSON(resultJSON []byte) (*rpc.GetTransactionResult, *solana.Transaction, error) {
+	var envelope struct {
+		Slot        uint64                  `json:"slot"`
+		BlockTime   *solana.UnixTimeSeconds `json:"blockTime"`
+		Transaction json.RawMessage         `json:"transaction"`
+		Meta        json.RawMessage         `json:"meta"`
+		Version     rpc.TransactionVersion  `json:"version"`
+	}
+	if err := json.Unmarshal(resultJSON, &envelope); err != nil {
+		return nil, nil, fmt.Errorf("error decoding transaction result: %s", err)
+	}
+
+	tx, meta, err := decodeTransactionAndMeta(envelope.Transaction, envelope.Meta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txResult := &rpc.GetTransactionResult{
+		Slot:      envelope.Slot,
+		BlockTime: envelope.BlockTime,
+		Meta:      meta,
+		Version:   envelope.Version,
+	}
+	return txResult, tx, nil
+}
+
+// DecodeTransactionMeta 解码 json 或 jsonParsed 编码的 meta。
+// jsonParsed 的内部指令以公钥引用账户，需要交易的静态账户列表将其还原为账户下标。
+func DecodeTransactionMeta(metaJSON []byte, staticAccountKeys solana.PublicKeySlice) (*rpc.TransactionMeta, error) {
+	return decodeTransactionMeta(metaJSON, staticAccountKeys, rpc.LoadedAddresses{})
+}
+
+func decodeTransactionAndMeta(txJSON []byte, metaJSON []byte) (*solana.Transaction, *rpc.TransactionMeta, error) {
+	tx, lookupAddresses, err := decodeTransactionJSON(txJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta, err := decodeTransactionMeta(metaJSON, tx.Message.AccountKeys, lookupAddresses)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tx, meta, nil
+}
+
+func decodeEncodedTransaction(encoded string, encoding solana.EncodingType) ([]byte, error) {
+	switch encoding {
+	case solana.EncodingBase58, solana.EncodingBase64, solana.EncodingBase64Zstd:
+	default:
+		return nil, fmt.Errorf("unsupported transaction encoding: %s", encoding)
+	}
+
+	// 复用 solana.Data 的解码逻辑，统一处理三种二进制编码
+	raw, err := json.Marshal([]string{encoded, string(encoding)})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding transaction data: %s", err)
+	}
+	var data solana.Data
+	if err := data.UnmarshalJSON(raw); err != nil {
+		return nil, fmt.Errorf("error decoding %s transaction: %s", encoding, err)
+	}
+	return data.Content, nil
+}
+
+// decodeTransactionJSON 解码交易 JSON，jsonParsed 编码时额外返回来自查找表的地址
+func decodeTransactionJSON(txJSON []byte) (*solana.Transaction, rpc.LoadedAddresses, error) {
+	txJSON = bytes.TrimSpace(txJSON)
+	if len(txJSON) == 0 {
+		return nil, rpc.LoadedAddresses{}, fmt.Errorf("transaction is empty")
+	}
+
+	switch txJSON[0] {
+	case '[':
+		var data solana.Data
+		if err := data.UnmarshalJSON(txJSON); err != nil {
+			return nil, rpc.LoadedAddresses{}, fmt.Errorf("error decoding transaction data: %s", err)
+		}
+		tx, err := solana.TransactionFromBytes(data.Content)
+		if err != nil {
+			return nil, rpc.LoadedAddresses{}, fmt.Errorf("error decoding wire transaction: %s", err)
+		}
+		return tx, rpc.LoadedAddresses{}, nil
+
+	case '"':
+		// 旧版 RPC 的 binary 编码直接返回 base58 字符串
+		var encoded string
+		if err := json.Unmarshal(txJSON, &encoded); err != nil {
+			return nil, rpc.LoadedAddresses{}, fmt.Errorf("error decoding transaction string: %s", err)
+		}
+		tx, err := solana.TransactionFromBase58(encoded)
+		if err != nil {
+			return nil, rpc.LoadedAddresses{}, fmt.Errorf("error decoding base58 transaction: %s", err)
+		}
+		return tx, rpc.LoadedAddresses{}, nil
+
+	case '{':
+		var probe struct {
+			Message struct {
+				AccountKeys []json.RawMessage `json:"accountKeys"`
+			} `json:"message"`
+		}
+		if err := json.Unmarshal(txJSON, &probe); err != nil {
+			return nil, rpc.LoadedAddresses{}, fmt.Errorf("error decoding transaction: %s", err)
+		}
+		if len(probe.Message.AccountKeys) > 0 && bytes.HasPrefix(bytes.TrimSpace(probe.Message.AccountKeys[0]), []byte("{")) {
+			return decodeJSONParsedTransaction(txJSON)
+		}
+
+		var tx solana.Transaction
+		if err := json.Unmarshal(txJSON, &tx); err != nil {
+			return nil, rpc.LoadedAddresses{}, fmt.Errorf("error decoding transaction: %s", err)
+		}
+		return &tx, rpc.LoadedAddresses{}, nil
+	}
+
+	return nil, rpc.LoadedAddresses{}, fmt.Errorf("unknown transaction encoding: %q", txJSON[0])
+}
+
+// jsonParsedAccountKey 是 jsonParsed 编码中 message.accountKeys 的元素
+type jsonParsedAccountKey struct {
+	Pubkey   solana.PublicKey `json:"pubkey"`
+	Signer   bool             `json:"signer"`
+	Writable bool             `json:"writable"`
+	Source   string           `json:"source"`
+}
+
+// jsonParsedInstruction 同时覆盖 json 编码（按下标）和 jsonParsed 编码（按公钥）的指令
+type jsonParsedInstruction struct {
+	ProgramIDIndex *uint16          `json:"programIdIndex"`
+	ProgramID      solana.PublicKey `json:"programId"`
+	Accounts       json.RawMessage  `json:"accounts"`
+	Data           string           `json:"data"`
+	Parsed         json.RawMessage  `json:"parsed"`
+}
+
+func decodeJSONParsedTransaction(txJSON []byte) (*solana.Transaction, rpc.LoadedAddresses, error) {
+	var parsed struct {
+		Signatures []solana.Signature `json:"signatures"`
+		Message    struct {
+			AccountKeys         []jsonParsedAccountKey                `json:"accountKeys"`
+			RecentBlockhash     solana.Hash                           `json:"recentBlockhash"`
+			Instructions        []jsonParsedInstruction               `json:"instructions"`
+			AddressTableLookups solana.MessageAddressTableLookupSlice `json:"addressTableLookups"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(txJSON, &parsed); err != nil {
+		return nil, rpc.LoadedAddresses{}, fmt.Errorf("error decoding jsonParsed transaction: %s", err)
+	}
+
+	tx := &solana.Transaction{Signatures: parsed.Signatures}
+	tx.Message.RecentBlockhash = parsed.Message.RecentBlockhash
+	tx.Message.AddressTableLookups = parsed.Message.AddressTableLookups
+	if len(parsed.Message.AddressTableLookups) > 0 {
+		tx.Message.SetVersion(solana.MessageVersionV0)
+	}
+
+	// jsonParsed 的账户列表已包含查找表地址，静态账户放入 message，其余按 meta 的顺序归入 LoadedAddresses
+	var lookupAddresses rpc.LoadedAddresses
+	allKeys := make(solana.PublicKeySlice, 0, len(parsed.Message.AccountKeys))
+	for _, key := range parsed.Message.AccountKeys {
+		allKeys = append(allKeys, key.Pubkey)
+		if key.Source == "lookupTable" {
+			if key.Writable {
+				lookupAddresses.Writable = append(lookupAddresses.Writable, key.Pubkey)
+			} else {
+				lookupAddresses.ReadOnly = append(lookupAddresses.ReadOnly, key.Pubkey)
+			}
+			continue
+		}
+
+		tx.Message.AccountKeys = append(tx.Message.AccountKeys, key.Pubkey)
+		switch {
+		case key.Signer:
+			tx.Message.Header.NumRequiredSignatures++
+			if !key.Writable {
+				tx.Message.Header.NumReadonlySignedAccounts++
+			}
+		case !key.Writable:
+			tx.Message.Header.NumReadonlyUnsignedAccounts++
+		}
+	}
+
+	indexes := accountKeyIndexes(allKeys)
+	tx.Message.Instructions = make([]solana.CompiledInstruction, 0, len(parsed.Message.Instructions))
+	for i, instruction := range parsed.Message.Instructions {
+		compiled, err := compileJSONInstruction(instruction, indexes)
+		if err != nil {
+			return nil, rpc.LoadedAddresses{}, fmt.Errorf("error compiling instruction %d: %s", i, err)
+		}
+		tx.Message.Instructions = append(tx.Message.Instructions, compiled)
+	}
+
+	return tx, lookupAddresses, nil
+}
+
+// decodeTransactionMeta 解码 meta，lookupAddresses 用于补全缺少 loadedAddresses 的 jsonParsed meta
+func decodeTransactionMeta(metaJSON []byte, staticAccountKeys solana.PublicKeySlice, lookupAddresses rpc.LoadedAddresses) (*rpc.TransactionMeta, error) {
+	type metaAlias rpc.TransactionMeta
+	var decoded struct {
+		*metaAlias
+		// 覆盖 rpc.TransactionMeta.InnerInstructions，按指令逐条判断编码
+		InnerInstructions []struct {
+			Index        uint16                  `json:"index"`
+			Instructions []jsonParsedInstruction `json:"instructions"`
+		} `json:"innerInstructions"`
+	}
+	meta := new(rpc.TransactionMeta)
+	decoded.metaAlias = (*metaAlias)(meta)
+	if err := json.Unmarshal(metaJSON, &decoded); err != nil {
+		return nil, fmt.Errorf("error decoding transaction meta: %s", err)
+	}
+
+	if len(meta.LoadedAddresses.Writable) == 0 && len(meta.LoadedAddresses.ReadOnly) == 0 {
+		meta.LoadedAddresses = lookupAddresses
+	}
+
+	allKeys := make(solana.PublicKeySlice, 0, len(staticAccountKeys)+len(meta.LoadedAddresses.Writable)+len(meta.LoadedAddresses.ReadOnly))
+	allKeys = append(allKeys, staticAccountKeys...)
+	allKeys = append(allKeys, meta.LoadedAddresses.Writable...)
+	allKeys = append(allKeys, meta.LoadedAddresses.ReadOnly...)
+	indexes := accountKeyIndexes(allKeys)
+
+	meta.InnerInstructions = make([]rpc.InnerInstruction, 0, len(decoded.InnerInstructions))
+	for _, set := range decoded.InnerInstructions {
+		inner := rpc.InnerInstruction{
+			Index:        set.Index,
+			Instructions: make([]solana.CompiledInstruction, 0, len(set.Instructions)),
+		}
+		for i, instruction := range set.Instructions {
+			compiled, err := compileJSONInstruction(instruction, indexes)
+			if err != nil {
+				return nil, fmt.Errorf("error compiling inner instruction %d.%d: %s", set.Index, i, err)
+			}
+			inner.Instructions = append(inner.Instructions, compiled)
+		}
+		meta.InnerInstructions = append(meta.InnerInstructions, inner)
+	}
+
+	return meta, nil
+}
+
+func accountKeyIndexes(keys solana.PublicKeySlice) map[solana.PublicKey]uint16 {
+	indexes := make(map[solana.PublicKey]uint16, len(keys))
+	for i, key := range keys {
+		if _, exists := indexes[key]; !exists {
+			indexes[key] = uint16(i)
+		}
+	}
+	return indexes
+}
+
+// compileJSONInstruction 将 json / jsonParsed 编码的指令还原为 CompiledInstruction。
+// 已被 RPC 解析的指令没有原始数据，仅还原解析器用到的 SPL Token transfer / transferChecked
+// 与 System transfer，其余只保留程序下标。
+func compileJSONInstruction(instruction jsonParsedInstruction, indexes map[solana.PublicKey]uint16) (solana.CompiledInstruction, error) {
+	var compiled solana.CompiledInstruction
+
+	if instruction.ProgramIDIndex != nil {
+		compiled.ProgramIDIndex = *instruction.ProgramIDIndex
+		if len(instruction.Accounts) > 0 {
+			if err := json.Unmarshal(instruction.Accounts, &compiled.Accounts); err != nil {
+				return compiled, fmt.Errorf("error decoding accounts: %s", err)
+			}
+		}
+		data, err := base58.Decode(instruction.Data)
+		if err != nil {
+			return compiled, fmt.Errorf("error decoding data: %s", err)
+		}
+		compiled.Data = data
+		return compiled, nil
+	}
+
+	programIndex, ok := indexes[instruction.ProgramID]
+	if !ok {
+		return compiled, fmt.Errorf("program %s not found in account keys", instruction.ProgramID)
+	}
+	compiled.ProgramIDIndex = programIndex
+
+	if len(instruction.Parsed) > 0 {
+		accounts, data, ok := encodeParsedInstruction(instruction.ProgramID, instruction.Parsed)
+		if !ok {
+			return compiled, nil
+		}
+		for _, account := range accounts {
+			index, ok := indexes[account]
+			if !ok {
+				return compiled, fmt.Errorf("account %s not found in account keys", account)
+			}
+			compiled.Accounts = append(compiled.Accounts, index)
+		}
+		compiled.Data = data
+		return compiled, nil
+	}
+
+	var accounts []solana.PublicKey
+	if len(instruction.Accounts) > 0 {
+		if err := json.Unmarshal(instruction.Accounts, &accounts); err != nil {
+			return compiled, fmt.Errorf("error decoding accounts: %s", err)
+		}
+	}
+	compiled.Accounts = make([]uint16, 0, len(accounts))
+	for _, account := range accounts {
+		index, ok := indexes[account]
+		if !ok {
+			return compiled, fmt.Errorf("account %s not found in account keys", account)
+		}
+		compiled.Accounts = append(compiled.Accounts, index)
+	}
+	data, err := base58.Decode(instruction.Data)
+	if err != nil {
+		return compiled, fmt.Errorf("error decoding data: %s", err)
+	}
+	compiled.Data = data
+
+	return compiled, nil
+}
+
+// encodeParsedInstruction 按程序布局重新编码 RPC 已解析的指令
+func encodeParsedInstruction(programID solana.PublicKey, parsedJSON json.RawMessage) ([]solana.PublicKey, []byte, bool) {
+	var parsed struct {
+		Type string `json:"type"`
+		Info struct {
+			Source            solana.PublicKey `json:"source"`
+			Destination       solana.PublicKey `json:"destination"`
+			Mint              solana.PublicKey `json:"mint"`
+			Authority         solana.PublicKey `json:"authority"`
+			MultisigAuthority solana.PublicKey `json:"multisigAuthority"`
+			Amount            string           `json:"amount"`
+			Lamports          uint64           `json:"lamports"`
+			TokenAmount       struct {
+				Amount   string `json:"amount"`
+				Decimals uint8  `json:"decimals"`
+			} `json:"tokenAmount"`
+		} `json:"info"`
+	}
+	// memo 等程序的 parsed 是字符串，直接忽略
+	if err := json.Unmarshal(parsedJSON, &parsed); err != nil {
+		return nil, nil, false
+	}
+
+	info := parsed.Info
+	authority := info.Authority
+	if authority.IsZero() {
+		authority = info.MultisigAuthority
+	}
+
+	switch {
+	case programID.Equals(solana.TokenProgramID) || programID.Equals(solana.Token2022ProgramID):
+		switch parsed.Type {
+		case "transfer":
+			amount, err := strconv.ParseUint(info.Amount, 10, 64)
+			if err != nil {
+				return nil, nil, false
+			}
+			data := make([]byte, 9)
+			data[0] = 3
+			binary.LittleEndian.PutUint64(data[1:], amount)
+			return []solana.PublicKey{info.Source, info.Destination, authority}, data, true
+
+		case "transferChecked":
+			amount, err := strconv.ParseUint(info.TokenAmount.Amount, 10, 64)
+			if err != nil {
+				return nil, nil, false
+			}
+			data := make([]byte, 10)
+			data[0] = 12
+			binary.LittleEndian.PutUint64(data[1:9], amount)
+			data[9] = info.TokenAmount.Decimals
+			return []solana.PublicKey{info.Source, info.Mint, info.Destination, authority}, data, true
+		}
+
+	case programID.Equals(solana.SystemProgramID):
+		if parsed.Type == "transfer" {
+			data := make([]byte, 12)
+			data[0] = 2
+			binary.LittleEndian.PutUint64(data[4:], info.Lamports)
+			return []solana.PublicKey{info.Source, info.Destination}, data, true
+		}
+	}
+
+	return nil, nil, false
+}
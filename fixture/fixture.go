@@ -0,0 +1,129 @@
+// Package fixture 录制 getTransaction 结果作为测试语料，并生成对应的解析结果 golden 文件。
+//
+// 语料保存为 <dir>/<protocol>/<sig>.json，golden 文件为同目录下的 <sig>.golden.json。
+// 每个问题交易录制一次后即成为永久的回归测试。
+package fixture
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+// GoldenSuffix 是 golden 文件的后缀，加载语料时需跳过
+const GoldenSuffix = ".golden.json"
+
+// Golden 是 golden 文件的内容
+type Golden struct {
+	Swaps    []solanaswapgo.SwapData `json:"swaps"`
+	SwapInfo *solanaswapgo.SwapInfo  `json:"swapInfo"`
+	Error    string                  `json:"error,omitempty"`
+}
+
+// Path 返回交易语料的路径
+func Path(dir string, protocol string, signature string) string {
+	return filepath.Join(dir, protocol, signature+".json")
+}
+
+// GoldenPath 返回交易语料对应的 golden 文件路径
+func GoldenPath(dir string, protocol string, signature string) string {
+	return filepath.Join(dir, protocol, signature+GoldenSuffix)
+}
+
+// RenderGolden 解析 getTransaction 结果 JSON 并生成 golden 内容。
+// 时间戳取自区块时间，没有区块时间时置零，保证输出与运行时间无关；
+// 解析错误记录在 Error 中，同样作为预期结果比较。
+func RenderGolden(resultJSON []byte) ([]byte, error) {
+	var golden Golden
+
+	parser, err := solanaswapgo.NewTransactionParserFromResultJSON(resultJSON)
+	if err != nil {
+		return nil, err
+	}
+	parser.Log = solanaswapgo.DiscardLogger
+
+	swaps, err := parser.ParseTransaction()
+	if err != nil {
+		golden.Error = fmt.Sprintf("error parsing transaction: %s", err)
+	} else {
+		golden.Swaps = swaps
+		if swapInfo, err := parser.ProcessSwapData(swaps); err != nil {
+			golden.Error = fmt.Sprintf("error processing swap data: %s", err)
+		} else {
+			swapInfo.Timestamp = time.Time{}
+			if blockTime := parser.GetBlockTime(); blockTime != nil {
+				swapInfo.Timestamp = blockTime.UTC()
+			}
+			golden.SwapInfo = swapInfo
+		}
+	}
+
+	data, err := json.MarshalIndent(golden, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding golden: %s", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Fetch 以 json 编码获取 getTransaction 的原始结果，不经过 solana-go 类型的重新编码
+func Fetch(ctx context.Context, client *rpc.Client, signature solana.Signature, commitment rpc.CommitmentType) ([]byte, error) {
+	var raw json.RawMessage
+	err := client.RPCCallForInto(ctx, &raw, "getTransaction", []interface{}{
+		signature.String(),
+		map[string]interface{}{
+			"encoding":                       solana.EncodingJSON,
+			"commitment":                     commitment,
+			"maxSupportedTransactionVersion": 0,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting tx: %s", err)
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, rpc.ErrNotFound
+	}
+	return raw, nil
+}
+
+// Record 获取交易并写入语料和 golden 文件，返回语料路径
+func Record(ctx context.Context, client *rpc.Client, dir string, protocol string, signature solana.Signature, commitment rpc.CommitmentType) (string, error) {
+	if protocol == "" {
+		return "", fmt.Errorf("protocol is required")
+	}
+
+	raw, err := Fetch(ctx, client, signature, commitment)
+	if err != nil {
+		return "", err
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, raw, "", "  "); err != nil {
+		return "", fmt.Errorf("error formatting transaction: %s", err)
+	}
+	indented.WriteByte('\n')
+
+	golden, err := RenderGolden(raw)
+	if err != nil {
+		return "", err
+	}
+
+	path := Path(dir, protocol, signature.String())
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, indented.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(GoldenPath(dir, protocol, signature.String()), golden, 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,120 @@
+// Package metrics 以 Prometheus 指标的形式暴露解析结果的统计。
+//
+// Collector 同时实现 prometheus.Collector 和 solanaswapgo.Observer：
+//
+//	collector := metrics.NewCollector()
+//	prometheus.MustRegister(collector)
+//	solanaswapgo.SetObserver(collector)
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+// OtherProgram 是超过 MaxUnknownPrograms 之后的未知程序使用的标签值
+const OtherProgram = "other"
+
+// Collector 统计解析的交易数、各协议和解析路径的交换数、解码错误、未知程序以及解析耗时
+type Collector struct {
+	// MaxUnknownPrograms 限制未知程序标签的取值个数，避免指标基数无限增长，默认 200
+	MaxUnknownPrograms int
+
+	transactions     prometheus.Counter
+	swapTransactions prometheus.Counter
+	swaps            *prometheus.CounterVec
+	decodeErrors     *prometheus.CounterVec
+	unknownPrograms  *prometheus.CounterVec
+	duration         prometheus.Histogram
+
+	mu           sync.Mutex
+	seenPrograms map[string]bool
+}
+
+// NewCollector 创建 Collector，指标名以 dexparse_parser_ 开头
+func NewCollector() *Collector {
+	return &Collector{
+		MaxUnknownPrograms: 200,
+		transactions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "dexparse_parser_transactions_total",
+			Help: "Transactions passed to ParseTransaction.",
+		}),
+		swapTransactions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "dexparse_parser_swap_transactions_total",
+			Help: "Transactions in which at least one swap was found.",
+		}),
+		swaps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dexparse_parser_swaps_total",
+			Help: "Swap legs found, by swap type and parse path (event, instruction or transfer).",
+		}, []string{"protocol", "source"}),
+		decodeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dexparse_parser_decode_errors_total",
+			Help: "Event or instruction data that failed to decode, by program ID.",
+		}, []string{"program"}),
+		unknownPrograms: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dexparse_parser_unknown_programs_total",
+			Help: "Transactions invoking a top-level program that no decoder handles, by program ID.",
+		}, []string{"program"}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "dexparse_parser_parse_duration_seconds",
+			Help:    "Time spent in ParseTransaction.",
+			Buckets: prometheus.ExponentialBuckets(0.00001, 4, 8), // 10µs ~ 160ms
+		}),
+		seenPrograms: make(map[string]bool),
+	}
+}
+
+// Describe 实现 prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.transactions.Describe(ch)
+	c.swapTransactions.Describe(ch)
+	c.swaps.Describe(ch)
+	c.decodeErrors.Describe(ch)
+	c.unknownPrograms.Describe(ch)
+	c.duration.Describe(ch)
+}
+
+// Collect 实现 prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.transactions.Collect(ch)
+	c.swapTransactions.Collect(ch)
+	c.swaps.Collect(ch)
+	c.decodeErrors.Collect(ch)
+	c.unknownPrograms.Collect(ch)
+	c.duration.Collect(ch)
+}
+
+// ObserveParse 实现 solanaswapgo.Observer
+func (c *Collector) ObserveParse(stats solanaswapgo.ParseStats) {
+	c.transactions.Inc()
+	c.duration.Observe(stats.Duration.Seconds())
+	if len(stats.Swaps) > 0 {
+		c.swapTransactions.Inc()
+	}
+	for _, swap := range stats.Swaps {
+		c.swaps.WithLabelValues(string(swap.Type), string(swap.Source())).Inc()
+	}
+	for _, decodeError := range stats.DecodeErrors {
+		c.decodeErrors.WithLabelValues(decodeError.Program.String()).Inc()
+	}
+	for _, program := range stats.UnknownPrograms {
+		c.unknownPrograms.WithLabelValues(c.programLabel(program.String())).Inc()
+	}
+}
+
+// programLabel 返回未知程序的标签值，超过 MaxUnknownPrograms 的新程序归入 OtherProgram
+func (c *Collector) programLabel(program string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.seenPrograms[program] {
+		return program
+	}
+	if len(c.seenPrograms) >= c.MaxUnknownPrograms {
+		return OtherProgram
+	}
+	c.seenPrograms[program] = true
+	return program
+}
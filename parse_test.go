@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"os"
 	"testing"
 	"time"
 
@@ -39,8 +39,17 @@ Example Transactions:
 - Raydium LaunchLab: 4S9AT3Qc5auU62fYPDdUWCtNb6EDiGXEBAhMjWCRs4ESfqHuYuFyJNXiodTBEjyvPM68prij3a7YKgd1YuL26DPV
 */
 
+// mainnetClient 返回访问主网的 RPC 客户端，这些测试需要网络，未设置 SOLANA_RPC_URL 或使用 -short 时跳过
+func mainnetClient(t *testing.T) *rpc.Client {
+	endpoint := os.Getenv("SOLANA_RPC_URL")
+	if endpoint == "" || testing.Short() {
+		t.Skip("设置 SOLANA_RPC_URL 以访问主网")
+	}
+	return rpc.New(endpoint)
+}
+
 func TestParseTransaction(t *testing.T) {
-	rpcClient := rpc.New("https://mainnet.helius-rpc.com/?api-key=b69296cd-290c-43f7-bf49-9e9ded12b5e0")
+	rpcClient := mainnetClient(t)
 	txSig := solana.MustSignatureFromBase58("3DBswgW6BS4iBsjA3QRJgXwUCPuv68n4HVYvh7cG5T6XA5wz71xtwo7P2XHdfyT4LPmhvWpzhzaRroWoEN81czLV")
 
 	var maxTxVersion uint64 = 0
@@ -53,22 +62,22 @@ func TestParseTransaction(t *testing.T) {
 		},
 	)
 	if err != nil {
-		log.Fatalf("error getting tx: %s", err)
+		t.Fatalf("error getting tx: %s", err)
 	}
 
 	parser, err := solanaswapgo.NewTransactionParser(tx)
 	if err != nil {
-		log.Fatalf("error creating parser: %s", err)
+		t.Fatalf("error creating parser: %s", err)
 	}
 
 	transactionData, err := parser.ParseTransaction()
 	if err != nil {
-		log.Fatalf("error parsing transaction: %s", err)
+		t.Fatalf("error parsing transaction: %s", err)
 	}
 
 	swapInfo, err := parser.ProcessSwapData(transactionData)
 	if err != nil {
-		log.Fatalf("error processing swap data: %s", err)
+		t.Fatalf("error processing swap data: %s", err)
 	}
 
 	marshalledSwapData, _ := json.MarshalIndent(swapInfo, "", "  ")
@@ -76,7 +85,7 @@ func TestParseTransaction(t *testing.T) {
 }
 
 func TestRaydiumLaunchLabTransaction(t *testing.T) {
-	rpcClient := rpc.New("https://mainnet.helius-rpc.com/?api-key=b69296cd-290c-43f7-bf49-9e9ded12b5e0")
+	rpcClient := mainnetClient(t)
 	txSig := solana.MustSignatureFromBase58("4S9AT3Qc5auU62fYPDdUWCtNb6EDiGXEBAhMjWCRs4ESfqHuYuFyJNXiodTBEjyvPM68prij3a7YKgd1YuL26DPV")
 
 	var maxTxVersion uint64 = 0
@@ -89,7 +98,7 @@ func TestRaydiumLaunchLabTransaction(t *testing.T) {
 		},
 	)
 	if err != nil {
-		log.Fatalf("error getting tx: %s", err)
+		t.Fatalf("error getting tx: %s", err)
 	}
 
 	// 打印交易的所有程序 ID 用于分析
@@ -97,7 +106,7 @@ func TestRaydiumLaunchLabTransaction(t *testing.T) {
 
 	txInfo, err := tx.Transaction.GetTransaction()
 	if err != nil {
-		log.Fatalf("failed to get transaction: %s", err)
+		t.Fatalf("failed to get transaction: %s", err)
 	}
 
 	allAccountKeys := append(txInfo.Message.AccountKeys, tx.Meta.LoadedAddresses.Writable...)
@@ -114,12 +123,12 @@ func TestRaydiumLaunchLabTransaction(t *testing.T) {
 	// 尝试解析
 	parser, err := solanaswapgo.NewTransactionParser(tx)
 	if err != nil {
-		log.Fatalf("error creating parser: %s", err)
+		t.Fatalf("error creating parser: %s", err)
 	}
 
 	transactionData, err := parser.ParseTransaction()
 	if err != nil {
-		log.Fatalf("error parsing transaction: %s", err)
+		t.Fatalf("error parsing transaction: %s", err)
 	}
 
 	fmt.Printf("\n解析出的交换数据数量: %d\n", len(transactionData))
@@ -130,7 +139,7 @@ func TestRaydiumLaunchLabTransaction(t *testing.T) {
 	if len(transactionData) > 0 {
 		swapInfo, err := parser.ProcessSwapData(transactionData)
 		if err != nil {
-			log.Printf("error processing swap data: %s", err)
+			t.Logf("error processing swap data: %s", err)
 		} else {
 			marshalledSwapData, _ := json.MarshalIndent(swapInfo, "", "  ")
 			fmt.Println("\n最终交换信息:")
@@ -140,7 +149,7 @@ func TestRaydiumLaunchLabTransaction(t *testing.T) {
 }
 
 func TestRaydiumLaunchLabSellTransaction(t *testing.T) {
-	rpcClient := rpc.New("https://mainnet.helius-rpc.com/?api-key=b69296cd-290c-43f7-bf49-9e9ded12b5e0")
+	rpcClient := mainnetClient(t)
 	txSig := solana.MustSignatureFromBase58("4DvBxPsGWWTXybZsUC7g2Cxzweuu4VaNoqvBgtFLk1gSgrQSiXXCteH6wSHkfuFMyaBC3aA56nPaqhbZRzSv5sEz")
 
 	var maxTxVersion uint64 = 0
@@ -153,7 +162,7 @@ func TestRaydiumLaunchLabSellTransaction(t *testing.T) {
 		},
 	)
 	if err != nil {
-		log.Fatalf("error getting tx: %s", err)
+		t.Fatalf("error getting tx: %s", err)
 	}
 
 	// 打印交易的所有程序 ID 用于分析
@@ -161,7 +170,7 @@ func TestRaydiumLaunchLabSellTransaction(t *testing.T) {
 
 	txInfo, err := tx.Transaction.GetTransaction()
 	if err != nil {
-		log.Fatalf("failed to get transaction: %s", err)
+		t.Fatalf("failed to get transaction: %s", err)
 	}
 
 	allAccountKeys := append(txInfo.Message.AccountKeys, tx.Meta.LoadedAddresses.Writable...)
@@ -186,12 +195,12 @@ func TestRaydiumLaunchLabSellTransaction(t *testing.T) {
 	// 尝试解析
 	parser, err := solanaswapgo.NewTransactionParser(tx)
 	if err != nil {
-		log.Fatalf("error creating parser: %s", err)
+		t.Fatalf("error creating parser: %s", err)
 	}
 
 	transactionData, err := parser.ParseTransaction()
 	if err != nil {
-		log.Fatalf("error parsing transaction: %s", err)
+		t.Fatalf("error parsing transaction: %s", err)
 	}
 
 	fmt.Printf("\n解析出的交换数据数量: %d\n", len(transactionData))
@@ -202,7 +211,7 @@ func TestRaydiumLaunchLabSellTransaction(t *testing.T) {
 	if len(transactionData) > 0 {
 		swapInfo, err := parser.ProcessSwapData(transactionData)
 		if err != nil {
-			log.Printf("error processing swap data: %s", err)
+			t.Logf("error processing swap data: %s", err)
 		} else {
 			marshalledSwapData, _ := json.MarshalIndent(swapInfo, "", "  ")
 			fmt.Println("\n最终交换信息:")
@@ -212,7 +221,7 @@ func TestRaydiumLaunchLabSellTransaction(t *testing.T) {
 }
 
 func TestJupiterTransactionTimestamp(t *testing.T) {
-	rpcClient := rpc.New("https://mainnet.helius-rpc.com/?api-key=b69296cd-290c-43f7-bf49-9e9ded12b5e0")
+	rpcClient := mainnetClient(t)
 
 	// 使用一个已知的 Jupiter 交易签名
 	txSig := solana.MustSignatureFromBase58("87RZvR1MT7VpjT2YuHuFGZvQ63u2YXYsvE7WqVbcNm51JQo43sUgm8DEa6wnjpoodWBWuh1YPHJMmcZ45qehVgu")
@@ -227,22 +236,22 @@ func TestJupiterTransactionTimestamp(t *testing.T) {
 		},
 	)
 	if err != nil {
-		log.Fatalf("error getting tx: %s", err)
+		t.Fatalf("error getting tx: %s", err)
 	}
 
 	parser, err := solanaswapgo.NewTransactionParser(tx)
 	if err != nil {
-		log.Fatalf("error creating parser: %s", err)
+		t.Fatalf("error creating parser: %s", err)
 	}
 
 	transactionData, err := parser.ParseTransaction()
 	if err != nil {
-		log.Fatalf("error parsing transaction: %s", err)
+		t.Fatalf("error parsing transaction: %s", err)
 	}
 
 	swapInfo, err := parser.ProcessSwapData(transactionData)
 	if err != nil {
-		log.Fatalf("error processing swap data: %s", err)
+		t.Fatalf("error processing swap data: %s", err)
 	}
 
 	fmt.Printf("Jupiter 交易时间戳: %s\n", swapInfo.Timestamp.Format(time.RFC3339))
@@ -264,7 +273,7 @@ func TestJupiterTransactionTimestamp(t *testing.T) {
 }
 
 func TestMeteoraDAMMv2Transaction(t *testing.T) {
-	rpcClient := rpc.New("https://mainnet.helius-rpc.com/?api-key=b69296cd-290c-43f7-bf49-9e9ded12b5e0")
+	rpcClient := mainnetClient(t)
 	txSig := solana.MustSignatureFromBase58("3DBswgW6BS4iBsjA3QRJgXwUCPuv68n4HVYvh7cG5T6XA5wz71xtwo7P2XHdfyT4LPmhvWpzhzaRroWoEN81czLV")
 
 	var maxTxVersion uint64 = 0
@@ -277,14 +286,14 @@ func TestMeteoraDAMMv2Transaction(t *testing.T) {
 		},
 	)
 	if err != nil {
-		log.Fatalf("error getting tx: %s", err)
+		t.Fatalf("error getting tx: %s", err)
 	}
 
 	fmt.Println("=== 分析 Meteora DAMM v2 交易 ===")
 
 	txInfo, err := tx.Transaction.GetTransaction()
 	if err != nil {
-		log.Fatalf("failed to get transaction: %s", err)
+		t.Fatalf("failed to get transaction: %s", err)
 	}
 
 	allAccountKeys := append(txInfo.Message.AccountKeys, tx.Meta.LoadedAddresses.Writable...)
@@ -309,12 +318,12 @@ func TestMeteoraDAMMv2Transaction(t *testing.T) {
 
 	parser, err := solanaswapgo.NewTransactionParser(tx)
 	if err != nil {
-		log.Fatalf("error creating parser: %s", err)
+		t.Fatalf("error creating parser: %s", err)
 	}
 
 	transactionData, err := parser.ParseTransaction()
 	if err != nil {
-		log.Fatalf("error parsing transaction: %s", err)
+		t.Fatalf("error parsing transaction: %s", err)
 	}
 
 	fmt.Printf("\n解析出的交换数据数量: %d\n", len(transactionData))
@@ -334,7 +343,7 @@ func TestMeteoraDAMMv2Transaction(t *testing.T) {
 	if len(transactionData) > 0 {
 		swapInfo, err := parser.ProcessSwapData(transactionData)
 		if err != nil {
-			log.Printf("error processing swap data: %s", err)
+			t.Logf("error processing swap data: %s", err)
 		} else {
 			marshalledSwapData, _ := json.MarshalIndent(swapInfo, "", "  ")
 			fmt.Println("\n最终交换信息:")
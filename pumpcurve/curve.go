@@ -0,0 +1,144 @@
+// Package pumpcurve 实现 Pump.fun 联合曲线的计算：按虚拟储备的恒定乘积报价，以及价格、市值和完成进度。
+//
+// 报价函数与链上程序的整数运算一致（不含手续费）：
+//
+//	curve := pumpcurve.FromVirtualReserves(event.VirtualSolReserves, event.VirtualTokenReserves)
+//	tokens := curve.BuyQuote(500_000_000) // 0.5 SOL 能买到的代币
+//	sol := curve.SellQuote(tokens)        // 卖出这些代币得到的 SOL
+package pumpcurve
+
+import "math/bits"
+
+// 新建联合曲线的初始状态，数量均为最小单位
+const (
+	InitialVirtualSolReserves   uint64 = 30_000_000_000
+	InitialVirtualTokenReserves uint64 = 1_073_000_000_000_000
+	InitialRealTokenReserves    uint64 = 793_100_000_000_000
+	TokenTotalSupply            uint64 = 1_000_000_000_000_000
+	TokenDecimals                      = 6
+	SolDecimals                        = 9
+)
+
+// virtualTokenOffset 是虚拟代币储备与真实代币储备之差，在曲线的整个生命周期中不变
+const virtualTokenOffset = InitialVirtualTokenReserves - InitialRealTokenReserves
+
+// Curve 是某一时刻的联合曲线储备
+type Curve struct {
+	VirtualSolReserves   uint64
+	VirtualTokenReserves uint64
+	RealSolReserves      uint64
+	RealTokenReserves    uint64
+}
+
+// New 返回新建代币的联合曲线
+func New() Curve {
+	return Curve{
+		VirtualSolReserves:   InitialVirtualSolReserves,
+		VirtualTokenReserves: InitialVirtualTokenReserves,
+		RealTokenReserves:    InitialRealTokenReserves,
+	}
+}
+
+// FromVirtualReserves 由虚拟储备推算真实储备，适用于使用默认参数创建的曲线
+func FromVirtualReserves(virtualSol, virtualToken uint64) Curve {
+	curve := Curve{VirtualSolReserves: virtualSol, VirtualTokenReserves: virtualToken}
+	if virtualSol > InitialVirtualSolReserves {
+		curve.RealSolReserves = virtualSol - InitialVirtualSolReserves
+	}
+	if virtualToken > virtualTokenOffset {
+		curve.RealTokenReserves = virtualToken - virtualTokenOffset
+	}
+	return curve
+}
+
+// BuyQuote 返回投入 solIn lamports（已扣除手续费）能买到的代币数量，不超过剩余的真实代币储备
+func (c Curve) BuyQuote(solIn uint64) uint64 {
+	if solIn == 0 || c.VirtualTokenReserves == 0 {
+		return 0
+	}
+	remaining := mulDiv(c.VirtualSolReserves, c.VirtualTokenReserves, c.VirtualSolReserves+solIn) + 1
+	if remaining >= c.VirtualTokenReserves {
+		return 0
+	}
+	return min(c.VirtualTokenReserves-remaining, c.RealTokenReserves)
+}
+
+// SellQuote 返回卖出 tokensIn 个代币得到的 lamports（未扣除手续费）
+func (c Curve) SellQuote(tokensIn uint64) uint64 {
+	if tokensIn == 0 {
+		return 0
+	}
+	return mulDiv(tokensIn, c.VirtualSolReserves, c.VirtualTokenReserves+tokensIn)
+}
+
+// BuyCost 返回买到 tokens 个代币需要的 lamports（未加手续费），向上取整。tokens 不小于虚拟代币储备时返回最大值
+func (c Curve) BuyCost(tokens uint64) uint64 {
+	if tokens == 0 {
+		return 0
+	}
+	if tokens >= c.VirtualTokenReserves {
+		return ^uint64(0)
+	}
+	return mulDiv(tokens, c.VirtualSolReserves, c.VirtualTokenReserves-tokens) + 1
+}
+
+// Buy 返回买入后的曲线、买到的代币数量和实际花费的 lamports。
+// 买到的代币受剩余真实代币储备限制时，只花费买完这些代币需要的 SOL，其余退回
+func (c Curve) Buy(solIn uint64) (Curve, uint64, uint64) {
+	tokens := c.BuyQuote(solIn)
+	spent := solIn
+	if tokens == c.RealTokenReserves {
+		spent = min(c.BuyCost(tokens), solIn)
+	}
+	c.VirtualSolReserves += spent
+	c.RealSolReserves += spent
+	c.VirtualTokenReserves -= tokens
+	c.RealTokenReserves -= tokens
+	return c, tokens, spent
+}
+
+// Sell 返回卖出后的曲线和得到的 lamports
+func (c Curve) Sell(tokensIn uint64) (Curve, uint64) {
+	sol := min(c.SellQuote(tokensIn), c.RealSolReserves)
+	c.VirtualSolReserves -= sol
+	c.RealSolReserves -= sol
+	c.VirtualTokenReserves += tokensIn
+	c.RealTokenReserves += tokensIn
+	return c, sol
+}
+
+// SpotPrice 返回当前每个代币的 SOL 价格
+func (c Curve) SpotPrice() float64 {
+	if c.VirtualTokenReserves == 0 {
+		return 0
+	}
+	return (float64(c.VirtualSolReserves) / 1e9) / (float64(c.VirtualTokenReserves) / 1e6)
+}
+
+// MarketCap 返回按总供应量计算的市值（SOL）
+func (c Curve) MarketCap() float64 {
+	return c.SpotPrice() * float64(TokenTotalSupply) / 1e6
+}
+
+// Progress 返回联合曲线的完成百分比（0 ~ 100），即初始真实代币储备中已被买走的比例
+func (c Curve) Progress() float64 {
+	if c.RealTokenReserves >= InitialRealTokenReserves {
+		return 0
+	}
+	return float64(InitialRealTokenReserves-c.RealTokenReserves) / float64(InitialRealTokenReserves) * 100
+}
+
+// Complete 返回曲线上的代币是否已被买完
+func (c Curve) Complete() bool {
+	return c.RealTokenReserves == 0
+}
+
+// mulDiv 计算 a * b / c，中间结果按 128 位计算。商超出 64 位时返回最大值
+func mulDiv(a, b, c uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	if hi >= c {
+		return ^uint64(0)
+	}
+	quotient, _ := bits.Div64(hi, lo, c)
+	return quotient
+}
@@ -0,0 +1,316 @@
+package rpctest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/mr-tron/base58"
+)
+
+type transactionOpts struct {
+	Encoding                       solana.EncodingType `json:"encoding"`
+	TransactionDetails             string              `json:"transactionDetails"`
+	MaxSupportedTransactionVersion *uint64             `json:"maxSupportedTransactionVersion"`
+}
+
+func (s *Server) getTransaction(params []json.RawMessage) (interface{}, *rpcError) {
+	var signatureStr string
+	if err := param(params, 0, &signatureStr); err != nil {
+		return nil, err
+	}
+	signature, err := solana.SignatureFromBase58(signatureStr)
+	if err != nil {
+		return nil, invalidParams("invalid signature: %s", err)
+	}
+	var opts transactionOpts
+	if err := param(params, 1, &opts); err != nil {
+		return nil, err
+	}
+
+	tx, ok := s.transactions[signature]
+	if !ok {
+		return nil, nil
+	}
+	if err := checkVersion(tx.version, opts.MaxSupportedTransactionVersion); err != nil {
+		return nil, err
+	}
+	return encodeTransactionResult(tx.raw, opts.Encoding)
+}
+
+func (s *Server) getBlock(params []json.RawMessage) (interface{}, *rpcError) {
+	var slot uint64
+	if err := param(params, 0, &slot); err != nil {
+		return nil, err
+	}
+	var opts transactionOpts
+	if err := param(params, 1, &opts); err != nil {
+		return nil, err
+	}
+
+	raw, ok := s.blocks[slot]
+	if !ok {
+		return nil, &rpcError{Code: codeBlockNotAvailable, Message: fmt.Sprintf("Block not available for slot %d", slot)}
+	}
+
+	var block map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &block); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid block fixture: %s", err)}
+	}
+	var txs []json.RawMessage
+	if err := json.Unmarshal(block["transactions"], &txs); err != nil && block["transactions"] != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid block fixture: %s", err)}
+	}
+
+	switch opts.TransactionDetails {
+	case "", "full":
+		encoded := make([]json.RawMessage, 0, len(txs))
+		for _, tx := range txs {
+			var envelope struct {
+				Version json.RawMessage `json:"version"`
+			}
+			json.Unmarshal(tx, &envelope)
+			if err := checkVersion(envelope.Version, opts.MaxSupportedTransactionVersion); err != nil {
+				return nil, err
+			}
+
+			result, err := encodeTransactionResult(tx, opts.Encoding)
+			if err != nil {
+				return nil, err
+			}
+			encoded = append(encoded, result)
+		}
+		block["transactions"], _ = json.Marshal(encoded)
+
+	case "signatures":
+		signatures := make([]string, 0, len(txs))
+		for _, tx := range txs {
+			var envelope struct {
+				Transaction struct {
+					Signatures []string `json:"signatures"`
+				} `json:"transaction"`
+			}
+			json.Unmarshal(tx, &envelope)
+			if len(envelope.Transaction.Signatures) > 0 {
+				signatures = append(signatures, envelope.Transaction.Signatures[0])
+			}
+		}
+		delete(block, "transactions")
+		block["signatures"], _ = json.Marshal(signatures)
+
+	case "none":
+		delete(block, "transactions")
+
+	default:
+		return nil, invalidParams("unsupported transactionDetails %q", opts.TransactionDetails)
+	}
+
+	return block, nil
+}
+
+type signatureInfo struct {
+	Signature          string          `json:"signature"`
+	Slot               uint64          `json:"slot"`
+	Err                json.RawMessage `json:"err"`
+	Memo               *string         `json:"memo"`
+	BlockTime          *int64          `json:"blockTime"`
+	ConfirmationStatus string          `json:"confirmationStatus"`
+}
+
+// getSignaturesForAddress 按 slot 从新到旧返回签名，同一 slot 内后添加的交易在前
+func (s *Server) getSignaturesForAddress(params []json.RawMessage) (interface{}, *rpcError) {
+	var addressStr string
+	if err := param(params, 0, &addressStr); err != nil {
+		return nil, err
+	}
+	address, err := solana.PublicKeyFromBase58(addressStr)
+	if err != nil {
+		return nil, invalidParams("invalid address: %s", err)
+	}
+	var opts struct {
+		Limit  *int   `json:"limit"`
+		Before string `json:"before"`
+		Until  string `json:"until"`
+	}
+	if err := param(params, 1, &opts); err != nil {
+		return nil, err
+	}
+
+	limit := defaultSignaturesLimit
+	if opts.Limit != nil {
+		limit = *opts.Limit
+	}
+	if limit < 1 || limit > maxSignaturesForAddressPage {
+		return nil, invalidParams("limit must be between 1 and %d", maxSignaturesForAddressPage)
+	}
+
+	indexed := s.addressIndex[address]
+	txs := make([]*transaction, len(indexed))
+	for i, tx := range indexed {
+		txs[len(indexed)-1-i] = tx
+	}
+	sort.SliceStable(txs, func(i, j int) bool {
+		return txs[i].slot > txs[j].slot
+	})
+
+	result := make([]signatureInfo, 0)
+	started := opts.Before == ""
+	for _, tx := range txs {
+		signature := tx.signature.String()
+		if !started {
+			started = signature == opts.Before
+			continue
+		}
+		if signature == opts.Until || len(result) == limit {
+			break
+		}
+
+		err := tx.err
+		if len(err) == 0 {
+			err = json.RawMessage("null")
+		}
+		result = append(result, signatureInfo{
+			Signature:          signature,
+			Slot:               tx.slot,
+			Err:                err,
+			BlockTime:          tx.blockTime,
+			ConfirmationStatus: "finalized",
+		})
+	}
+	return result, nil
+}
+
+type accountOpts struct {
+	Encoding solana.EncodingType `json:"encoding"`
+}
+
+func (s *Server) getAccountInfo(params []json.RawMessage) (interface{}, *rpcError) {
+	var pubkeyStr string
+	if err := param(params, 0, &pubkeyStr); err != nil {
+		return nil, err
+	}
+	var opts accountOpts
+	if err := param(params, 1, &opts); err != nil {
+		return nil, err
+	}
+
+	value, err := s.accountValue(pubkeyStr, opts.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	return s.contextValue(value), nil
+}
+
+func (s *Server) getMultipleAccounts(params []json.RawMessage) (interface{}, *rpcError) {
+	var pubkeys []string
+	if err := param(params, 0, &pubkeys); err != nil {
+		return nil, err
+	}
+	if len(pubkeys) > 100 {
+		return nil, invalidParams("too many inputs provided; max 100")
+	}
+	var opts accountOpts
+	if err := param(params, 1, &opts); err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, 0, len(pubkeys))
+	for _, pubkeyStr := range pubkeys {
+		value, err := s.accountValue(pubkeyStr, opts.Encoding)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return s.contextValue(values), nil
+}
+
+// accountValue 返回账户的 RPC 表示，账户不存在时为 nil（JSON null）。
+// jsonParsed 与真实节点对无法解析的账户一样退回 base64。
+func (s *Server) accountValue(pubkeyStr string, encoding solana.EncodingType) (interface{}, *rpcError) {
+	pubkey, err := solana.PublicKeyFromBase58(pubkeyStr)
+	if err != nil {
+		return nil, invalidParams("invalid pubkey: %s", err)
+	}
+
+	account, ok := s.accounts[pubkey]
+	if !ok {
+		return nil, nil
+	}
+
+	var data []string
+	switch encoding {
+	case "", solana.EncodingBase64, solana.EncodingJSONParsed:
+		data = []string{base64.StdEncoding.EncodeToString(account.Data), string(solana.EncodingBase64)}
+	case solana.EncodingBase58:
+		data = []string{base58.Encode(account.Data), string(solana.EncodingBase58)}
+	default:
+		return nil, invalidParams("unsupported encoding %q", encoding)
+	}
+
+	return map[string]interface{}{
+		"data":       data,
+		"executable": account.Executable,
+		"lamports":   account.Lamports,
+		"owner":      account.Owner.String(),
+		"rentEpoch":  account.RentEpoch,
+		"space":      len(account.Data),
+	}, nil
+}
+
+// checkVersion 与节点一致：版本化交易要求客户端传入 maxSupportedTransactionVersion
+func checkVersion(version json.RawMessage, maxSupported *uint64) *rpcError {
+	var number uint64
+	if len(version) == 0 || json.Unmarshal(version, &number) != nil {
+		// legacy
+		return nil
+	}
+	if maxSupported == nil || number > *maxSupported {
+		return &rpcError{
+			Code:    codeUnsupportedTxVersion,
+			Message: fmt.Sprintf("Transaction version (%d) is not supported by the requesting client. Please try the request again with the following configuration parameter: \"maxSupportedTransactionVersion\": %d", number, number),
+		}
+	}
+	return nil
+}
+
+// encodeTransactionResult 将 json 编码的语料按请求的编码输出，base58/base64 会重新序列化交易
+func encodeTransactionResult(raw json.RawMessage, encoding solana.EncodingType) (json.RawMessage, *rpcError) {
+	switch encoding {
+	case "", solana.EncodingJSON:
+		return raw, nil
+	case solana.EncodingBase64, solana.EncodingBase58:
+	default:
+		return nil, invalidParams("unsupported encoding %q (fixtures are stored as json)", encoding)
+	}
+
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid transaction fixture: %s", err)}
+	}
+
+	var tx solana.Transaction
+	if err := json.Unmarshal(result["transaction"], &tx); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid transaction fixture: %s", err)}
+	}
+	// json 编码的消息没有版本前缀，没有查找表的 v0 交易需要按 version 字段还原
+	var version uint64
+	if json.Unmarshal(result["version"], &version) == nil {
+		tx.Message.SetVersion(solana.MessageVersionV0)
+	}
+	wire, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("error encoding transaction: %s", err)}
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(wire)
+	if encoding == solana.EncodingBase58 {
+		encoded = base58.Encode(wire)
+	}
+	result["transaction"], _ = json.Marshal([]string{encoded, string(encoding)})
+
+	out, _ := json.Marshal(result)
+	return out, nil
+}
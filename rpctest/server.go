@@ -0,0 +1,322 @@
+// Package rpctest 提供基于 httptest 的本地 Solana JSON-RPC 服务，数据来自磁盘上的语料，
+// 可以直接交给 rpc.New 使用，让集成测试在没有主网访问的情况下走完整的拉取与解析流程。
+//
+// 支持的方法: getTransaction、getBlock、getSignaturesForAddress、getAccountInfo、getMultipleAccounts、getHealth。
+package rpctest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/zzispp/solana-dex-parse/fixture"
+)
+
+// JSON-RPC 错误码，与 Solana 节点一致
+const (
+	codeMethodNotFound          = -32601
+	codeInvalidParams           = -32602
+	codeBlockNotAvailable       = -32004
+	codeUnsupportedTxVersion    = -32015
+	defaultSignaturesLimit      = 1000
+	maxSignaturesForAddressPage = 1000
+)
+
+// Server 是本地 JSON-RPC 服务，所有方法可在测试运行中并发调用
+type Server struct {
+	*httptest.Server
+
+	mu           sync.RWMutex
+	slot         uint64
+	transactions map[solana.Signature]*transaction
+	blocks       map[uint64]json.RawMessage
+	accounts     map[solana.PublicKey]*Account
+	addressIndex map[solana.PublicKey][]*transaction
+	requests     map[string]int
+}
+
+// transaction 是一笔 json 编码的 getTransaction 结果
+type transaction struct {
+	signature solana.Signature
+	slot      uint64
+	blockTime *int64
+	version   json.RawMessage
+	err       json.RawMessage
+	raw       json.RawMessage
+}
+
+// Account 是 getAccountInfo 返回的账户
+type Account struct {
+	Lamports   uint64
+	Owner      solana.PublicKey
+	Data       []byte
+	Executable bool
+	RentEpoch  uint64
+}
+
+// NewServer 启动一个空的服务，调用方负责 Close
+func NewServer() *Server {
+	s := &Server{
+		transactions: make(map[solana.Signature]*transaction),
+		blocks:       make(map[uint64]json.RawMessage),
+		accounts:     make(map[solana.PublicKey]*Account),
+		addressIndex: make(map[solana.PublicKey][]*transaction),
+		requests:     make(map[string]int),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client 返回连接到本服务的 RPC 客户端
+func (s *Server) Client() *rpc.Client {
+	return rpc.New(s.URL)
+}
+
+// Requests 返回某个方法被调用的次数
+func (s *Server) Requests(method string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.requests[method]
+}
+
+// Load 加载目录中的语料：<dir>/blocks/<slot>.json 为 getBlock 结果，
+// <dir>/accounts/<pubkey>.json 为账户（getAccountInfo 的 value），
+// 其余 <dir>/<protocol>/<sig>.json 为 getTransaction 结果；*.golden.json 会被跳过。
+func (s *Server) Load(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if strings.HasSuffix(path, fixture.GoldenSuffix) {
+			continue
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		switch filepath.Base(filepath.Dir(path)) {
+		case "blocks":
+			slot, err := strconv.ParseUint(name, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%s: block file name must be a slot: %s", path, err)
+			}
+			s.AddBlock(slot, raw)
+		case "accounts":
+			pubkey, err := solana.PublicKeyFromBase58(name)
+			if err != nil {
+				return fmt.Errorf("%s: account file name must be a public key: %s", path, err)
+			}
+			if err := s.addAccountJSON(pubkey, raw); err != nil {
+				return fmt.Errorf("%s: %s", path, err)
+			}
+		default:
+			if err := s.AddTransaction(raw); err != nil {
+				return fmt.Errorf("%s: %s", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// AddTransaction 添加一笔 json 编码的 getTransaction 结果，
+// 交易涉及的所有账户（含查找表加载的地址）都会被索引，供 getSignaturesForAddress 查询
+func (s *Server) AddTransaction(raw []byte) error {
+	var envelope struct {
+		Slot        uint64          `json:"slot"`
+		BlockTime   *int64          `json:"blockTime"`
+		Version     json.RawMessage `json:"version"`
+		Transaction struct {
+			Signatures []solana.Signature `json:"signatures"`
+			Message    struct {
+				AccountKeys []solana.PublicKey `json:"accountKeys"`
+			} `json:"message"`
+		} `json:"transaction"`
+		Meta *struct {
+			Err             json.RawMessage     `json:"err"`
+			LoadedAddresses rpc.LoadedAddresses `json:"loadedAddresses"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return fmt.Errorf("error decoding transaction result (fixtures must use json encoding): %s", err)
+	}
+	if len(envelope.Transaction.Signatures) == 0 {
+		return fmt.Errorf("transaction has no signatures")
+	}
+
+	tx := &transaction{
+		signature: envelope.Transaction.Signatures[0],
+		slot:      envelope.Slot,
+		blockTime: envelope.BlockTime,
+		version:   envelope.Version,
+		raw:       append(json.RawMessage(nil), raw...),
+	}
+	keys := envelope.Transaction.Message.AccountKeys
+	if envelope.Meta != nil {
+		tx.err = envelope.Meta.Err
+		keys = append(keys, envelope.Meta.LoadedAddresses.Writable...)
+		keys = append(keys, envelope.Meta.LoadedAddresses.ReadOnly...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transactions[tx.signature] = tx
+	indexed := make(map[solana.PublicKey]bool, len(keys))
+	for _, key := range keys {
+		if indexed[key] {
+			continue
+		}
+		indexed[key] = true
+		s.addressIndex[key] = append(s.addressIndex[key], tx)
+	}
+	if tx.slot > s.slot {
+		s.slot = tx.slot
+	}
+	return nil
+}
+
+// AddBlock 添加一个 json 编码、transactionDetails 为 full 的 getBlock 结果
+func (s *Server) AddBlock(slot uint64, raw []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blocks[slot] = append(json.RawMessage(nil), raw...)
+	if slot > s.slot {
+		s.slot = slot
+	}
+}
+
+// AddAccount 添加或替换一个账户
+func (s *Server) AddAccount(pubkey solana.PublicKey, account Account) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accounts[pubkey] = &account
+}
+
+func (s *Server) addAccountJSON(pubkey solana.PublicKey, raw []byte) error {
+	var value rpc.Account
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return fmt.Errorf("error decoding account: %s", err)
+	}
+
+	account := Account{
+		Lamports:   value.Lamports,
+		Owner:      value.Owner,
+		Executable: value.Executable,
+	}
+	if value.RentEpoch != nil {
+		account.RentEpoch = value.RentEpoch.Uint64()
+	}
+	if value.Data != nil {
+		account.Data = value.Data.GetBinary()
+	}
+	s.AddAccount(pubkey, account)
+	return nil
+}
+
+type request struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+func invalidParams(format string, args ...interface{}) *rpcError {
+	return &rpcError{Code: codeInvalidParams, Message: "Invalid params: " + fmt.Sprintf(format, args...)}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests[req.Method]++
+	s.mu.Unlock()
+
+	result, rpcErr := s.dispatch(req)
+
+	response := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      req.ID,
+	}
+	if rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *Server) dispatch(req request) (interface{}, *rpcError) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	switch req.Method {
+	case "getTransaction":
+		return s.getTransaction(req.Params)
+	case "getBlock":
+		return s.getBlock(req.Params)
+	case "getSignaturesForAddress":
+		return s.getSignaturesForAddress(req.Params)
+	case "getAccountInfo":
+		return s.getAccountInfo(req.Params)
+	case "getMultipleAccounts":
+		return s.getMultipleAccounts(req.Params)
+	case "getHealth":
+		return "ok", nil
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "Method not found"}
+	}
+}
+
+// param 解码第 index 个参数，参数缺失时保持 out 不变
+func param(params []json.RawMessage, index int, out interface{}) *rpcError {
+	if index >= len(params) || string(params[index]) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params[index], out); err != nil {
+		return invalidParams("%s", err)
+	}
+	return nil
+}
+
+func (s *Server) contextValue(value interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"context": map[string]interface{}{"slot": s.slot},
+		"value":   value,
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+// metrics 是服务自身的请求与解析计数，注册到 Config.Registry 并由 /metrics 输出
+type metrics struct {
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	transactions    prometheus.Counter
+	swapTransaction prometheus.Counter
+	swaps           *prometheus.CounterVec
+	errors          prometheus.Counter
+}
+
+func newMetrics(registerer prometheus.Registerer) *metrics {
+	m := &metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dexparse_http_requests_total",
+			Help: "Parse requests by endpoint and status code.",
+		}, []string{"endpoint", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "dexparse_http_request_duration_seconds",
+			Help:    "Time spent handling parse requests by endpoint.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 4, 8), // 1ms ~ 16s
+		}, []string{"endpoint"}),
+		transactions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "dexparse_transactions_parsed_total",
+			Help: "Transactions parsed.",
+		}),
+		swapTransaction: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "dexparse_swap_transactions_total",
+			Help: "Parsed transactions containing at least one swap.",
+		}),
+		swaps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dexparse_swaps_total",
+			Help: "Parsed swap legs by protocol.",
+		}, []string{"protocol"}),
+		errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "dexparse_parse_errors_total",
+			Help: "Transactions that failed to parse.",
+		}),
+	}
+	registerer.MustRegister(m.requests, m.requestDuration, m.transactions, m.swapTransaction, m.swaps, m.errors)
+	return m
+}
+
+func (m *metrics) observeRequest(endpoint string, code int, duration time.Duration) {
+	m.requests.WithLabelValues(endpoint, strconv.Itoa(code)).Inc()
+	m.requestDuration.WithLabelValues(endpoint).Observe(duration.Seconds())
+}
+
+// observeTransaction 记录一笔已解析的交易，按协议统计交换腿数
+func (m *metrics) observeTransaction(swaps []solanaswapgo.SwapData) {
+	m.transactions.Inc()
+	if len(swaps) > 0 {
+		m.swapTransaction.Inc()
+	}
+	for _, swap := range swaps {
+		m.swaps.WithLabelValues(string(swap.Type)).Inc()
+	}
+}
+
+func (m *metrics) observeError() {
+	m.errors.Inc()
+}
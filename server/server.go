@@ -0,0 +1,373 @@
+// Package server 将解析器以 HTTP 服务的形式提供，cmd/dexparse-server 是它的可执行入口。
+//
+// 所有响应都是带版本号的 JSON：{"version":"v1","data":...} 或 {"version":"v1","error":{...}}。
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+// APIVersion 是响应中的版本号，也是路由前缀
+const APIVersion = "v1"
+
+// Config 是服务配置，零值字段使用默认值
+type Config struct {
+	// RPC 用于按签名拉取交易，为 nil 时按签名解析返回 503
+	RPC *rpc.Client
+	// Commitment 为 getTransaction 的 commitment，默认 confirmed
+	Commitment rpc.CommitmentType
+
+	// MaxBodyBytes 为请求体的最大字节数，默认 8 MiB
+	MaxBodyBytes int64
+	// MaxConcurrent 为同时处理的解析请求数，超出时返回 503，默认 64
+	MaxConcurrent int
+	// RequestTimeout 为单个请求的处理时限（含 RPC 拉取），默认 30 秒
+	RequestTimeout time.Duration
+	// BlockWorkers 为解析区块时的并发数，默认 4
+	BlockWorkers int
+
+	// Registry 为 /metrics 输出的指标集合，服务自身的请求与解析计数也注册到其中。
+	// 为 nil 时新建一个只包含服务指标的 Registry；解析器的 metrics.Collector 需由调用方注册
+	Registry *prometheus.Registry
+
+	Log *logrus.Logger
+}
+
+// Server 是解析服务的 http.Handler
+type Server struct {
+	config  Config
+	mux     *http.ServeMux
+	slots   chan struct{}
+	metrics *metrics
+}
+
+// New 根据配置创建服务
+func New(config Config) *Server {
+	if config.Commitment == "" {
+		config.Commitment = rpc.CommitmentConfirmed
+	}
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = 8 << 20
+	}
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = 64
+	}
+	if config.RequestTimeout <= 0 {
+		config.RequestTimeout = 30 * time.Second
+	}
+	if config.BlockWorkers <= 0 {
+		config.BlockWorkers = 4
+	}
+	if config.Registry == nil {
+		config.Registry = prometheus.NewRegistry()
+	}
+	if config.Log == nil {
+		config.Log = logrus.New()
+		config.Log.SetFormatter(&logrus.TextFormatter{
+			TimestampFormat: "2006-01-02 15:04:05",
+			FullTimestamp:   true,
+		})
+	}
+
+	s := &Server{
+		config:  config,
+		mux:     http.NewServeMux(),
+		slots:   make(chan struct{}, config.MaxConcurrent),
+		metrics: newMetrics(config.Registry),
+	}
+
+	s.mux.Handle("GET /"+APIVersion+"/transactions/{signature}", s.limited("transaction", s.handleSignature))
+	s.mux.Handle("POST /"+APIVersion+"/transactions", s.limited("transaction_raw", s.handleTransaction))
+	s.mux.Handle("POST /"+APIVersion+"/blocks", s.limited("block", s.handleBlock))
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("GET /readyz", s.handleReady)
+	s.mux.Handle("GET /metrics", promhttp.HandlerFor(config.Registry, promhttp.HandlerOpts{}))
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not_found", "no such endpoint")
+	})
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// TransactionResponse 是单笔交易的解析结果；交易失败或不含交换时 Swaps 为空、SwapInfo 为 null
+type TransactionResponse struct {
+	Signature string                  `json:"signature,omitempty"`
+	Slot      uint64                  `json:"slot"`
+	SwapInfo  *solanaswapgo.SwapInfo  `json:"swapInfo"`
+	Swaps     []solanaswapgo.SwapData `json:"swaps"`
+}
+
+// BlockResponse 是区块的解析结果，Errors 为单笔交易的解码错误
+type BlockResponse struct {
+	Slot         uint64             `json:"slot"`
+	Transactions []BlockTransaction `json:"transactions"`
+	Errors       []string           `json:"errors,omitempty"`
+}
+
+// BlockTransaction 是区块中一笔包含交换的交易
+type BlockTransaction struct {
+	TransactionResponse
+	TransactionIndex int `json:"transactionIndex"`
+}
+
+type envelope struct {
+	Version string      `json:"version"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   *apiError   `json:"error,omitempty"`
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// statusWriter 记录状态码用于指标统计
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// limited 为解析端点加上并发限制、请求体大小限制、超时和指标统计
+func (s *Server) limited(endpoint string, handler func(http.ResponseWriter, *http.Request)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			duration := time.Since(start)
+			s.metrics.observeRequest(endpoint, sw.status, duration)
+			s.config.Log.WithFields(logrus.Fields{
+				"endpoint": endpoint,
+				"status":   sw.status,
+				"duration": duration,
+			}).Debug("request handled")
+		}()
+
+		select {
+		case s.slots <- struct{}{}:
+			defer func() { <-s.slots }()
+		default:
+			sw.Header().Set("Retry-After", "1")
+			writeError(sw, http.StatusServiceUnavailable, "overloaded", "too many concurrent requests")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), s.config.RequestTimeout)
+		defer cancel()
+
+		r.Body = http.MaxBytesReader(sw, r.Body, s.config.MaxBodyBytes)
+		handler(sw, r.WithContext(ctx))
+	})
+}
+
+func (s *Server) handleSignature(w http.ResponseWriter, r *http.Request) {
+	signature, err := solana.SignatureFromBase58(r.PathValue("signature"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_signature", err.Error())
+		return
+	}
+	if s.config.RPC == nil {
+		writeError(w, http.StatusServiceUnavailable, "rpc_not_configured", "parsing by signature requires an RPC endpoint")
+		return
+	}
+
+	maxTxVersion := uint64(0)
+	tx, err := s.config.RPC.GetTransaction(r.Context(), signature, &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     s.config.Commitment,
+		MaxSupportedTransactionVersion: &maxTxVersion,
+	})
+	if err != nil {
+		if errors.Is(err, rpc.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "not_found", "transaction not found")
+			return
+		}
+		if r.Context().Err() != nil {
+			writeError(w, http.StatusGatewayTimeout, "timeout", "timed out fetching transaction")
+			return
+		}
+		writeError(w, http.StatusBadGateway, "rpc_error", fmt.Sprintf("error getting tx: %s", err))
+		return
+	}
+
+	swap, err := solanaswapgo.ParseTransactionResult(tx)
+	if err != nil {
+		s.metrics.observeError()
+		writeError(w, http.StatusUnprocessableEntity, "parse_error", err.Error())
+		return
+	}
+
+	response := TransactionResponse{Signature: signature.String(), Slot: tx.Slot, Swaps: []solanaswapgo.SwapData{}}
+	if swap != nil {
+		response.SwapInfo = swap.SwapInfo
+		response.Swaps = swap.Swaps
+	}
+	s.metrics.observeTransaction(response.Swaps)
+	writeData(w, response)
+}
+
+// handleTransaction 解析请求体中的交易与 meta，格式与 getTransaction 的结果相同，
+// transaction 可以是任意编码，slot 与 blockTime 可省略；与按签名解析一样跳过失败的交易
+func (s *Server) handleTransaction(w http.ResponseWriter, r *http.Request) {
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	var header struct {
+		Meta json.RawMessage `json:"meta"`
+	}
+	if err := json.Unmarshal(body, &header); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+	if len(header.Meta) == 0 || string(header.Meta) == "null" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "meta is required")
+		return
+	}
+
+	txResult, tx, err := solanaswapgo.DecodeTransactionResultJSON(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_transaction", err.Error())
+		return
+	}
+
+	swap, err := solanaswapgo.ParseDecodedTransactionResult(txResult, tx)
+	if err != nil {
+		s.metrics.observeError()
+		writeError(w, http.StatusUnprocessableEntity, "parse_error", err.Error())
+		return
+	}
+
+	response := TransactionResponse{Slot: txResult.Slot, Swaps: []solanaswapgo.SwapData{}}
+	if len(tx.Signatures) > 0 {
+		response.Signature = tx.Signatures[0].String()
+	}
+	if swap != nil {
+		response.SwapInfo = swap.SwapInfo
+		response.Swaps = swap.Swaps
+	}
+	s.metrics.observeTransaction(response.Swaps)
+	writeData(w, response)
+}
+
+// handleBlock 解析请求体中的 getBlock 结果，slot 通过查询参数传入（getBlock 的响应中不包含）
+func (s *Server) handleBlock(w http.ResponseWriter, r *http.Request) {
+	slot, err := strconv.ParseUint(r.URL.Query().Get("slot"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_slot", "slot query parameter is required")
+		return
+	}
+
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	var block rpc.GetBlockResult
+	if err := json.Unmarshal(body, &block); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_block", err.Error())
+		return
+	}
+
+	swaps, err := solanaswapgo.ParseBlockConcurrent(r.Context(), slot, &block, s.config.BlockWorkers)
+	if r.Context().Err() != nil {
+		writeError(w, http.StatusGatewayTimeout, "timeout", "timed out parsing block")
+		return
+	}
+
+	response := BlockResponse{Slot: slot, Transactions: make([]BlockTransaction, 0, len(swaps))}
+	for _, swap := range swaps {
+		response.Transactions = append(response.Transactions, BlockTransaction{
+			TransactionResponse: TransactionResponse{
+				Signature: swap.Signature.String(),
+				Slot:      swap.Slot,
+				SwapInfo:  swap.SwapInfo,
+				Swaps:     swap.Swaps,
+			},
+			TransactionIndex: swap.TransactionIndex,
+		})
+		s.metrics.observeTransaction(swap.Swaps)
+	}
+	if err != nil {
+		for _, txErr := range unwrapJoined(err) {
+			s.metrics.observeError()
+			response.Errors = append(response.Errors, txErr.Error())
+		}
+	}
+
+	writeData(w, response)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeData(w, map[string]string{"status": "ok"})
+}
+
+// handleReady 在配置了 RPC 时检查节点健康状态
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if s.config.RPC != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+		if _, err := s.config.RPC.GetHealth(ctx); err != nil {
+			writeError(w, http.StatusServiceUnavailable, "rpc_unhealthy", err.Error())
+			return
+		}
+	}
+	writeData(w, map[string]string{"status": "ready"})
+}
+
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "body_too_large", fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit))
+			return nil, false
+		}
+		writeError(w, http.StatusBadRequest, "invalid_body", err.Error())
+		return nil, false
+	}
+	return body, true
+}
+
+func unwrapJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, envelope{Version: APIVersion, Data: data})
+}
+
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	writeJSON(w, status, envelope{Version: APIVersion, Error: &apiError{Code: code, Message: message}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body envelope) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,129 @@
+package sink
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+// JSONL 每行写入一个 Row
+type JSONL struct {
+	file *os.File
+	buf  *bufio.Writer
+	enc  *json.Encoder
+}
+
+// NewJSONL 写入任意 io.Writer，Close 不会关闭 w
+func NewJSONL(w io.Writer) *JSONL {
+	buf := bufio.NewWriter(w)
+	return &JSONL{buf: buf, enc: json.NewEncoder(buf)}
+}
+
+// CreateJSONL 以追加方式打开文件，回填恢复时不会覆盖已有数据
+func CreateJSONL(path string) (*JSONL, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %s", path, err)
+	}
+	s := NewJSONL(file)
+	s.file = file
+	return s, nil
+}
+
+func (s *JSONL) Write(swap solanaswapgo.BlockSwap) error {
+	return s.enc.Encode(NewRow(swap))
+}
+
+func (s *JSONL) Flush() error {
+	return flushFile(s.buf, s.file)
+}
+
+func (s *JSONL) Close() error {
+	return closeFile(s.buf, s.file)
+}
+
+// CSV 写入带表头的 CSV，列见 Columns
+type CSV struct {
+	file   *os.File
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSV 写入任意 io.Writer，第一行为表头；Close 不会关闭 w
+func NewCSV(w io.Writer) *CSV {
+	return &CSV{w: csv.NewWriter(w), header: true}
+}
+
+// CreateCSV 以追加方式打开文件，只在文件为空时写入表头
+func CreateCSV(path string) (*CSV, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %s", path, err)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error opening %s: %s", path, err)
+	}
+
+	s := NewCSV(file)
+	s.file = file
+	s.header = stat.Size() == 0
+	return s, nil
+}
+
+func (s *CSV) Write(swap solanaswapgo.BlockSwap) error {
+	if s.header {
+		if err := s.w.Write(Columns); err != nil {
+			return err
+		}
+		s.header = false
+	}
+	return s.w.Write(NewRow(swap).Values())
+}
+
+func (s *CSV) Flush() error {
+	s.w.Flush()
+	if err := s.w.Error(); err != nil {
+		return err
+	}
+	if s.file != nil {
+		return s.file.Sync()
+	}
+	return nil
+}
+
+func (s *CSV) Close() error {
+	err := s.Flush()
+	if s.file != nil {
+		if closeErr := s.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func flushFile(buf *bufio.Writer, file *os.File) error {
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+	if file != nil {
+		return file.Sync()
+	}
+	return nil
+}
+
+func closeFile(buf *bufio.Writer, file *os.File) error {
+	err := flushFile(buf, file)
+	if file != nil {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
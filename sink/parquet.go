@@ -0,0 +1,242 @@
+package sink
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+// ParquetOptions 控制 Parquet 文件的切分
+type ParquetOptions struct {
+	// SlotsPerFile 为每个文件覆盖的 slot 范围，交换所在的范围变化时切换到新文件；0 表示不按 slot 切分
+	SlotsPerFile uint64
+	// RowGroupSize 为每个 row group 的最大行数，默认 65536
+	RowGroupSize int64
+}
+
+// Parquet 将交易写入 <prefix>-transactions-*.parquet（schema 为 Row），
+// 将交换腿写入 <prefix>-legs-*.parquet（schema 为 LegRow），两者按 signature 关联。
+//
+// Parquet 文件只有写完 footer 才能读取，因此 Flush 会关闭当前文件，之后的写入从新的 part 开始；
+// 文件先写入 .tmp 再重命名，读取方不会看到未完成的文件。用于回填时将 backfill.Runner 的 FlushSlots
+// 设为 SlotsPerFile，每个 slot 范围只在写完时 Flush 一次
+type Parquet struct {
+	prefix  string
+	options ParquetOptions
+
+	open   bool
+	bucket uint64
+	files  []string
+
+	txFile, legFile *os.File
+	txPath, legPath string
+	txWriter        *parquet.GenericWriter[Row]
+	legWriter       *parquet.GenericWriter[LegRow]
+	txRows, legRows int64
+}
+
+// NewParquet 创建 Parquet 输出。path 的扩展名会被去掉作为前缀，
+// 如 out/swaps.parquet 会写出 out/swaps-transactions-000300000000-000300099999.parquet
+func NewParquet(path string, options ParquetOptions) (*Parquet, error) {
+	if options.RowGroupSize <= 0 {
+		options.RowGroupSize = 65536
+	}
+	prefix := strings.TrimSuffix(path, filepath.Ext(path))
+	if err := os.MkdirAll(filepath.Dir(prefix), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating %s: %s", filepath.Dir(prefix), err)
+	}
+	return &Parquet{prefix: prefix, options: options}, nil
+}
+
+// Files 返回已完成（已关闭）的文件，按写出顺序排列
+func (s *Parquet) Files() []string {
+	return s.files
+}
+
+func (s *Parquet) Write(swap solanaswapgo.BlockSwap) error {
+	var bucket uint64
+	if s.options.SlotsPerFile > 0 {
+		bucket = swap.Slot / s.options.SlotsPerFile
+	}
+	if s.open && bucket != s.bucket {
+		if err := s.closeFiles(); err != nil {
+			return err
+		}
+	}
+	if !s.open {
+		if err := s.openFiles(bucket); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.txWriter.Write([]Row{NewRow(swap)}); err != nil {
+		return fmt.Errorf("error writing %s: %s", s.txPath, err)
+	}
+	s.txRows++
+	if legs := NewLegRows(swap); len(legs) > 0 {
+		if _, err := s.legWriter.Write(legs); err != nil {
+			return fmt.Errorf("error writing %s: %s", s.legPath, err)
+		}
+		s.legRows += int64(len(legs))
+	}
+	return nil
+}
+
+// LegRow 是 Parquet legs 文件的 schema，字段与 solanaswapgo.Leg 一一对应
+type LegRow struct {
+	Signature string `json:"signature" parquet:"signature"`
+	Slot      uint64 `json:"slot" parquet:"slot"`
+	LegIndex  int    `json:"legIndex" parquet:"leg_index"`
+	Protocol  string `json:"protocol" parquet:"protocol"`
+	Platform  string `json:"platform" parquet:"platform"`
+	DataType  string `json:"dataType" parquet:"data_type"`
+	Pool      string `json:"pool" parquet:"pool"`
+	Trader    string `json:"trader" parquet:"trader"`
+
+	InputMint      string `json:"inputMint" parquet:"input_mint"`
+	InputAmount    uint64 `json:"inputAmount" parquet:"input_amount"`
+	InputDecimals  int    `json:"inputDecimals" parquet:"input_decimals"`
+	InputUIAmount  string `json:"inputUiAmount" parquet:"input_ui_amount"`
+	OutputMint     string `json:"outputMint" parquet:"output_mint"`
+	OutputAmount   uint64 `json:"outputAmount" parquet:"output_amount"`
+	OutputDecimals int    `json:"outputDecimals" parquet:"output_decimals"`
+	OutputUIAmount string `json:"outputUiAmount" parquet:"output_ui_amount"`
+
+	Source      string `json:"source" parquet:"source"`
+	Destination string `json:"destination" parquet:"destination"`
+	Authority   string `json:"authority" parquet:"authority"`
+}
+
+// NewLegRows 将 BlockSwap 的交换腿（见 solanaswapgo.NewLegs）转换为 LegRow
+func NewLegRows(swap solanaswapgo.BlockSwap) []LegRow {
+	legs := solanaswapgo.NewLegs(swap)
+	rows := make([]LegRow, 0, len(legs))
+	for _, leg := range legs {
+		rows = append(rows, LegRow{
+			Signature:      leg.Signature,
+			Slot:           leg.Slot,
+			LegIndex:       leg.LegIndex,
+			Protocol:       leg.Protocol,
+			Platform:       leg.Platform,
+			DataType:       leg.DataType,
+			Pool:           leg.Pool,
+			Trader:         leg.Trader,
+			InputMint:      leg.InputMint,
+			InputAmount:    leg.InputAmount,
+			InputDecimals:  leg.InputDecimals,
+			InputUIAmount:  leg.InputUIAmount,
+			OutputMint:     leg.OutputMint,
+			OutputAmount:   leg.OutputAmount,
+			OutputDecimals: leg.OutputDecimals,
+			OutputUIAmount: leg.OutputUIAmount,
+			Source:         leg.Source,
+			Destination:    leg.Destination,
+			Authority:      leg.Authority,
+		})
+	}
+	return rows
+}
+
+func (s *Parquet) Flush() error {
+	return s.closeFiles()
+}
+
+func (s *Parquet) Close() error {
+	return s.closeFiles()
+}
+
+// openFiles 为 bucket 对应的 slot 范围打开一对新文件，同名文件已存在时递增 part 后缀
+func (s *Parquet) openFiles(bucket uint64) error {
+	name := s.prefix + "-%s"
+	if s.options.SlotsPerFile > 0 {
+		start := bucket * s.options.SlotsPerFile
+		name = fmt.Sprintf("%s-%%s-%012d-%012d", s.prefix, start, start+s.options.SlotsPerFile-1)
+	}
+
+	for part := 0; ; part++ {
+		base := name
+		if part > 0 {
+			base = fmt.Sprintf("%s-%d", name, part)
+		}
+		s.txPath = fmt.Sprintf(base, "transactions") + ".parquet"
+		s.legPath = fmt.Sprintf(base, "legs") + ".parquet"
+		if !fileExists(s.txPath) && !fileExists(s.legPath) {
+			break
+		}
+	}
+
+	var err error
+	if s.txFile, err = os.Create(s.txPath + ".tmp"); err != nil {
+		return fmt.Errorf("error creating %s: %s", s.txPath, err)
+	}
+	if s.legFile, err = os.Create(s.legPath + ".tmp"); err != nil {
+		s.txFile.Close()
+		os.Remove(s.txFile.Name())
+		return fmt.Errorf("error creating %s: %s", s.legPath, err)
+	}
+
+	options := []parquet.WriterOption{
+		parquet.MaxRowsPerRowGroup(s.options.RowGroupSize),
+		parquet.Compression(&parquet.Zstd),
+		parquet.CreatedBy("solana-dex-parse", "", ""),
+	}
+	s.txWriter = parquet.NewGenericWriter[Row](s.txFile, options...)
+	s.legWriter = parquet.NewGenericWriter[LegRow](s.legFile, options...)
+	s.txRows, s.legRows = 0, 0
+	s.bucket, s.open = bucket, true
+	return nil
+}
+
+// closeFiles 写出 footer 并将临时文件重命名为最终文件名，没有行的文件会被删除
+func (s *Parquet) closeFiles() error {
+	if !s.open {
+		return nil
+	}
+	s.open = false
+
+	if err := finishFile(s.txWriter, s.txFile, s.txPath, s.txRows); err != nil {
+		finishFile(s.legWriter, s.legFile, s.legPath, 0)
+		return err
+	}
+	if s.txRows > 0 {
+		s.files = append(s.files, s.txPath)
+	}
+	if err := finishFile(s.legWriter, s.legFile, s.legPath, s.legRows); err != nil {
+		return err
+	}
+	if s.legRows > 0 {
+		s.files = append(s.files, s.legPath)
+	}
+	return nil
+}
+
+func finishFile(writer interface{ Close() error }, file *os.File, path string, rows int64) error {
+	tmp := file.Name()
+	err := writer.Close()
+	if err == nil && rows > 0 {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || rows == 0 {
+		os.Remove(tmp)
+		if err != nil {
+			return fmt.Errorf("error writing %s: %s", path, err)
+		}
+		return nil
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error writing %s: %s", path, err)
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,208 @@
+// Package sink 将解析出的交换写入文件或数据库：JSONL、CSV、SQLite 和 Parquet。
+//
+// 所有实现共用 Row 的扁平结构，每笔包含交换的交易一行，列定义见 Columns 和 SQLiteSchema；
+// Parquet 另外按 LegRow 输出每个交换腿。
+package sink
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+// Sink 接收解析出的交换。Write 可以缓冲，Flush 之后的数据保证已落盘；Close 会先 Flush。
+// Sink 满足 backfill.Sink，可以直接作为回填的输出
+type Sink interface {
+	Write(swap solanaswapgo.BlockSwap) error
+	Flush() error
+	Close() error
+}
+
+// Row 是一笔交换交易的扁平表示，也是 Parquet transactions 文件的 schema。
+// 无法汇总为 SwapInfo 的交易只有签名、slot、协议和手续费；原始数量为链上整数，UI 数量按精度换算为十进制字符串，不经过浮点
+type Row struct {
+	Signature        string `json:"signature" parquet:"signature"`
+	Slot             uint64 `json:"slot" parquet:"slot"`
+	TransactionIndex int    `json:"transactionIndex" parquet:"transaction_index"`
+	BlockTime        int64  `json:"blockTime" parquet:"block_time"`
+	Trader           string `json:"trader" parquet:"trader"`
+	Protocol         string `json:"protocol" parquet:"protocol"`
+	Platform         string `json:"platform" parquet:"platform"` // 发射平台，见 SwapInfo.Platform
+	Pool             string `json:"pool" parquet:"pool"`
+
+	TokenInMint     string `json:"tokenInMint" parquet:"token_in_mint"`
+	TokenInAmount   uint64 `json:"tokenInAmount" parquet:"token_in_amount"`
+	TokenInDecimals int    `json:"tokenInDecimals" parquet:"token_in_decimals"`
+	TokenInUIAmount string `json:"tokenInUiAmount" parquet:"token_in_ui_amount"`
+
+	TokenOutMint     string `json:"tokenOutMint" parquet:"token_out_mint"`
+	TokenOutAmount   uint64 `json:"tokenOutAmount" parquet:"token_out_amount"`
+	TokenOutDecimals int    `json:"tokenOutDecimals" parquet:"token_out_decimals"`
+	TokenOutUIAmount string `json:"tokenOutUiAmount" parquet:"token_out_ui_amount"`
+
+	Fee uint64 `json:"fee" parquet:"fee"`
+
+	// 交换费用来自 SwapInfo.Fees，均以 FeeMint 计；协议未给出费用时 FeeMint 为空、数量为 0
+	FeeMint     string `json:"feeMint" parquet:"fee_mint"`
+	FeeDecimals int    `json:"feeDecimals" parquet:"fee_decimals"`
+	ProtocolFee uint64 `json:"protocolFee" parquet:"protocol_fee"`
+	CreatorFee  uint64 `json:"creatorFee" parquet:"creator_fee"`
+	LPFee       uint64 `json:"lpFee" parquet:"lp_fee"`
+	PlatformFee uint64 `json:"platformFee" parquet:"platform_fee"`
+	TradeFee    uint64 `json:"tradeFee" parquet:"trade_fee"`
+
+	Legs int `json:"legs" parquet:"legs"`
+}
+
+// Columns 是 CSV 表头和 SQLite 列名，顺序与 Row 的字段一致
+var Columns = []string{
+	"signature",
+	"slot",
+	"transaction_index",
+	"block_time",
+	"trader",
+	"protocol",
+	"platform",
+	"pool",
+	"token_in_mint",
+	"token_in_amount",
+	"token_in_decimals",
+	"token_in_ui_amount",
+	"token_out_mint",
+	"token_out_amount",
+	"token_out_decimals",
+	"token_out_ui_amount",
+	"fee",
+	"fee_mint",
+	"fee_decimals",
+	"protocol_fee",
+	"creator_fee",
+	"lp_fee",
+	"platform_fee",
+	"trade_fee",
+	"legs",
+}
+
+// NewRow 将 BlockSwap 转换为 Row
+func NewRow(swap solanaswapgo.BlockSwap) Row {
+	row := Row{
+		Signature:        swap.Signature.String(),
+		Slot:             swap.Slot,
+		TransactionIndex: swap.TransactionIndex,
+		Pool:             poolAddress(swap.Swaps),
+		Fee:              swap.Fee,
+		Legs:             len(swap.Swaps),
+	}
+
+	info := swap.SwapInfo
+	if info == nil {
+		row.Protocol = legProtocols(swap.Swaps)
+		return row
+	}
+
+	if !info.Timestamp.IsZero() {
+		row.BlockTime = info.Timestamp.Unix()
+	}
+	if len(info.Signers) > 0 {
+		row.Trader = info.Signers[0].String()
+	}
+	row.Protocol = strings.Join(info.AMMs, ",")
+	row.Platform = info.Platform
+	if row.Protocol == "" {
+		row.Protocol = legProtocols(swap.Swaps)
+	}
+
+	row.TokenInMint = info.TokenInMint.String()
+	row.TokenInAmount = info.TokenInAmount
+	row.TokenInDecimals = int(info.TokenInDecimals)
+	row.TokenInUIAmount = solanaswapgo.FormatAmount(info.TokenInAmount, info.TokenInDecimals)
+
+	row.TokenOutMint = info.TokenOutMint.String()
+	row.TokenOutAmount = info.TokenOutAmount
+	row.TokenOutDecimals = int(info.TokenOutDecimals)
+	row.TokenOutUIAmount = solanaswapgo.FormatAmount(info.TokenOutAmount, info.TokenOutDecimals)
+
+	if fees := info.Fees; fees != nil {
+		row.FeeMint = fees.Mint.String()
+		row.FeeDecimals = int(fees.Decimals)
+		row.ProtocolFee = fees.ProtocolFee
+		row.CreatorFee = fees.CreatorFee
+		row.LPFee = fees.LPFee
+		row.PlatformFee = fees.PlatformFee
+		row.TradeFee = fees.TradeFee
+	}
+	return row
+}
+
+// Values 按 Columns 的顺序返回字段的字符串形式
+func (r Row) Values() []string {
+	return []string{
+		r.Signature,
+		strconv.FormatUint(r.Slot, 10),
+		strconv.Itoa(r.TransactionIndex),
+		strconv.FormatInt(r.BlockTime, 10),
+		r.Trader,
+		r.Protocol,
+		r.Platform,
+		r.Pool,
+		r.TokenInMint,
+		strconv.FormatUint(r.TokenInAmount, 10),
+		strconv.Itoa(r.TokenInDecimals),
+		r.TokenInUIAmount,
+		r.TokenOutMint,
+		strconv.FormatUint(r.TokenOutAmount, 10),
+		strconv.Itoa(r.TokenOutDecimals),
+		r.TokenOutUIAmount,
+		strconv.FormatUint(r.Fee, 10),
+		r.FeeMint,
+		strconv.Itoa(r.FeeDecimals),
+		strconv.FormatUint(r.ProtocolFee, 10),
+		strconv.FormatUint(r.CreatorFee, 10),
+		strconv.FormatUint(r.LPFee, 10),
+		strconv.FormatUint(r.PlatformFee, 10),
+		strconv.FormatUint(r.TradeFee, 10),
+		strconv.Itoa(r.Legs),
+	}
+}
+
+// Open 按文件扩展名创建 Sink：.jsonl、.csv、.db/.sqlite/.sqlite3、.parquet（使用默认的 ParquetOptions）
+func Open(path string) (Sink, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".parquet":
+		return NewParquet(path, ParquetOptions{})
+	case ".jsonl":
+		return CreateJSONL(path)
+	case ".csv":
+		return CreateCSV(path)
+	case ".db", ".sqlite", ".sqlite3":
+		return OpenSQLite(path)
+	default:
+		return nil, fmt.Errorf("unsupported sink %q: expected .jsonl, .csv, .db, .sqlite, .sqlite3 or .parquet", path)
+	}
+}
+
+// poolAddress 取第一个带有池子地址的交换，基于转账推断的交换没有池子信息
+func poolAddress(swaps []solanaswapgo.SwapData) string {
+	for _, leg := range solanaswapgo.NewLegs(solanaswapgo.BlockSwap{Swaps: swaps}) {
+		if leg.Pool != "" {
+			return leg.Pool
+		}
+	}
+	return ""
+}
+
+// legProtocols 按出现顺序去重各个交换的类型
+func legProtocols(swaps []solanaswapgo.SwapData) string {
+	var protocols []string
+	seen := make(map[solanaswapgo.SwapType]bool)
+	for _, swap := range swaps {
+		if !seen[swap.Type] {
+			seen[swap.Type] = true
+			protocols = append(protocols, string(swap.Type))
+		}
+	}
+	return strings.Join(protocols, ",")
+}
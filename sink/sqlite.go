@@ -0,0 +1,165 @@
+package sink
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteSchema 是 swaps 表的定义。
+//
+// 原始数量（含交换费用）可能超过 int64，以十进制字符串保存（需要计算时 CAST 或使用 UI 数量）；
+// UI 数量为按精度换算后的 REAL，block_time 为 Unix 秒，未知时为 NULL。
+// 签名为主键，重复写入同一笔交易会覆盖旧行，回填恢复时不会产生重复数据
+const SQLiteSchema = `CREATE TABLE IF NOT EXISTS swaps (
+	signature           TEXT PRIMARY KEY,
+	slot                INTEGER NOT NULL,
+	transaction_index   INTEGER NOT NULL,
+	block_time          INTEGER,
+	trader              TEXT NOT NULL,
+	protocol            TEXT NOT NULL,
+	platform            TEXT NOT NULL,
+	pool                TEXT NOT NULL,
+	token_in_mint       TEXT NOT NULL,
+	token_in_amount     TEXT NOT NULL,
+	token_in_decimals   INTEGER NOT NULL,
+	token_in_ui_amount  REAL,
+	token_out_mint      TEXT NOT NULL,
+	token_out_amount    TEXT NOT NULL,
+	token_out_decimals  INTEGER NOT NULL,
+	token_out_ui_amount REAL,
+	fee                 INTEGER NOT NULL,
+	fee_mint            TEXT NOT NULL,
+	fee_decimals        INTEGER NOT NULL,
+	protocol_fee        TEXT NOT NULL,
+	creator_fee         TEXT NOT NULL,
+	lp_fee              TEXT NOT NULL,
+	platform_fee        TEXT NOT NULL,
+	trade_fee           TEXT NOT NULL,
+	legs                INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS swaps_slot ON swaps (slot);
+CREATE INDEX IF NOT EXISTS swaps_trader ON swaps (trader);
+CREATE INDEX IF NOT EXISTS swaps_token_in_mint ON swaps (token_in_mint);
+CREATE INDEX IF NOT EXISTS swaps_token_out_mint ON swaps (token_out_mint);
+`
+
+// SQLite 将交换写入 SQLite 数据库（纯 Go 驱动 modernc.org/sqlite）。
+// 写入在事务中累积，Flush 时提交
+type SQLite struct {
+	db     *sql.DB
+	tx     *sql.Tx
+	insert *sql.Stmt
+}
+
+// OpenSQLite 打开或创建数据库并建表
+func OpenSQLite(path string) (*SQLite, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %s", path, err)
+	}
+	// 单连接保证事务与 PRAGMA 作用在同一个连接上
+	db.SetMaxOpenConns(1)
+
+	for _, statement := range []string{"PRAGMA journal_mode = WAL", "PRAGMA busy_timeout = 5000", SQLiteSchema} {
+		if _, err := db.Exec(statement); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("error initializing %s: %s", path, err)
+		}
+	}
+	return &SQLite{db: db}, nil
+}
+
+// DB 返回底层连接，用于查询已写入的数据
+func (s *SQLite) DB() *sql.DB {
+	return s.db
+}
+
+func (s *SQLite) Write(swap solanaswapgo.BlockSwap) error {
+	if s.tx == nil {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return fmt.Errorf("error beginning transaction: %s", err)
+		}
+		insert, err := tx.Prepare("INSERT OR REPLACE INTO swaps (" + strings.Join(Columns, ", ") + ") VALUES (?" + strings.Repeat(", ?", len(Columns)-1) + ")")
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("error preparing insert: %s", err)
+		}
+		s.tx, s.insert = tx, insert
+	}
+
+	row := NewRow(swap)
+	var blockTime interface{}
+	if row.BlockTime != 0 {
+		blockTime = row.BlockTime
+	}
+	_, err := s.insert.Exec(
+		row.Signature,
+		int64(row.Slot),
+		row.TransactionIndex,
+		blockTime,
+		row.Trader,
+		row.Protocol,
+		row.Platform,
+		row.Pool,
+		row.TokenInMint,
+		strconv.FormatUint(row.TokenInAmount, 10),
+		row.TokenInDecimals,
+		uiAmount(row.TokenInUIAmount),
+		row.TokenOutMint,
+		strconv.FormatUint(row.TokenOutAmount, 10),
+		row.TokenOutDecimals,
+		uiAmount(row.TokenOutUIAmount),
+		int64(row.Fee),
+		row.FeeMint,
+		row.FeeDecimals,
+		strconv.FormatUint(row.ProtocolFee, 10),
+		strconv.FormatUint(row.CreatorFee, 10),
+		strconv.FormatUint(row.LPFee, 10),
+		strconv.FormatUint(row.PlatformFee, 10),
+		strconv.FormatUint(row.TradeFee, 10),
+		row.Legs,
+	)
+	if err != nil {
+		return fmt.Errorf("error inserting swap %s: %s", row.Signature, err)
+	}
+	return nil
+}
+
+func (s *SQLite) Flush() error {
+	if s.tx == nil {
+		return nil
+	}
+	s.insert.Close()
+	err := s.tx.Commit()
+	s.tx, s.insert = nil, nil
+	if err != nil {
+		return fmt.Errorf("error committing swaps: %s", err)
+	}
+	return nil
+}
+
+func (s *SQLite) Close() error {
+	err := s.Flush()
+	if closeErr := s.db.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// uiAmount 将十进制字符串转换为 REAL，没有数量时写入 NULL
+func uiAmount(amount string) interface{} {
+	if amount == "" {
+		return nil
+	}
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,100 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// signatureSet 记录最近处理过的签名，超过容量时淘汰最早加入的签名
+type signatureSet struct {
+	order []solana.Signature
+	next  int
+	seen  map[solana.Signature]struct{}
+}
+
+func newSignatureSet(capacity int) *signatureSet {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &signatureSet{
+		order: make([]solana.Signature, 0, capacity),
+		seen:  make(map[solana.Signature]struct{}, capacity),
+	}
+}
+
+func (s *signatureSet) Contains(signature solana.Signature) bool {
+	_, ok := s.seen[signature]
+	return ok
+}
+
+func (s *signatureSet) Add(signature solana.Signature) {
+	if s.Contains(signature) {
+		return
+	}
+
+	if len(s.order) < cap(s.order) {
+		s.order = append(s.order, signature)
+	} else {
+		delete(s.seen, s.order[s.next])
+		s.order[s.next] = signature
+		s.next = (s.next + 1) % len(s.order)
+	}
+	s.seen[signature] = struct{}{}
+}
+
+// signatureTracker 在 signatureSet 之上记录正在拉取的签名，可以被读取循环和拉取 worker 并发使用
+type signatureTracker struct {
+	mu       sync.Mutex
+	seen     *signatureSet
+	inflight map[solana.Signature]struct{}
+}
+
+func newSignatureTracker(capacity int) *signatureTracker {
+	return &signatureTracker{
+		seen:     newSignatureSet(capacity),
+		inflight: make(map[solana.Signature]struct{}),
+	}
+}
+
+// Add 记录签名，签名已处理过或正在拉取时返回 false
+func (t *signatureTracker) Add(signature solana.Signature) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.contains(signature) {
+		return false
+	}
+	t.seen.Add(signature)
+	return true
+}
+
+// Claim 将签名标记为正在拉取，签名已处理过或正在拉取时返回 false
+func (t *signatureTracker) Claim(signature solana.Signature) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.contains(signature) {
+		return false
+	}
+	t.inflight[signature] = struct{}{}
+	return true
+}
+
+// Release 结束拉取；fetched 为 false 时不记录签名，之后重放的通知会再次拉取
+func (t *signatureTracker) Release(signature solana.Signature, fetched bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.inflight, signature)
+	if fetched {
+		t.seen.Add(signature)
+	}
+}
+
+func (t *signatureTracker) contains(signature solana.Signature) bool {
+	if _, ok := t.inflight[signature]; ok {
+		return true
+	}
+	return t.seen.Contains(signature)
+}
@@ -0,0 +1,112 @@
+package stream
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+// fetchJob 是一个待拉取的签名，done 关闭后 swap 可读，不含交换或拉取失败时 swap 为 nil
+type fetchJob struct {
+	signature solana.Signature
+	swap      *solanaswapgo.BlockSwap
+	done      chan struct{}
+}
+
+// fetchPool 用 FetchWorkers 个 goroutine 并发拉取并解析 logsSubscribe 通知中的交易，
+// 读取循环只负责分发签名。有序输出时按分发顺序排队，队列写满后分发阻塞，由此对读取循环形成背压
+type fetchPool struct {
+	subscriber *Subscriber
+	seen       *signatureTracker
+	out        chan<- solanaswapgo.BlockSwap
+
+	jobs  chan *fetchJob
+	order chan *fetchJob // 无序输出时为 nil
+
+	workers sync.WaitGroup
+	emitter sync.WaitGroup
+}
+
+func (s *Subscriber) startFetchPool(ctx context.Context, seen *signatureTracker, out chan<- solanaswapgo.BlockSwap) *fetchPool {
+	workers := s.FetchWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	pool := &fetchPool{
+		subscriber: s,
+		seen:       seen,
+		out:        out,
+		jobs:       make(chan *fetchJob),
+	}
+	if !s.Unordered {
+		pool.order = make(chan *fetchJob, workers)
+		pool.emitter.Add(1)
+		go pool.emitInOrder(ctx)
+	}
+	for w := 0; w < workers; w++ {
+		pool.workers.Add(1)
+		go pool.work(ctx)
+	}
+	return pool
+}
+
+// Dispatch 将签名交给 worker，所有 worker 都忙或有序队列已满时阻塞
+func (p *fetchPool) Dispatch(ctx context.Context, signature solana.Signature) error {
+	job := &fetchJob{signature: signature, done: make(chan struct{})}
+	if p.order != nil {
+		select {
+		case p.order <- job:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	select {
+	case p.jobs <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close 停止分发并等待 worker 退出，调用前 ctx 应已取消
+func (p *fetchPool) Close() {
+	close(p.jobs)
+	p.workers.Wait()
+	if p.order != nil {
+		close(p.order)
+		p.emitter.Wait()
+	}
+}
+
+func (p *fetchPool) work(ctx context.Context) {
+	defer p.workers.Done()
+	for job := range p.jobs {
+		job.swap = p.subscriber.fetchSwap(ctx, p.seen, job.signature)
+		close(job.done)
+
+		if p.order == nil && job.swap != nil {
+			emit(ctx, p.out, *job.swap)
+		}
+	}
+}
+
+func (p *fetchPool) emitInOrder(ctx context.Context) {
+	defer p.emitter.Done()
+	for job := range p.order {
+		select {
+		case <-job.done:
+		case <-ctx.Done():
+			return
+		}
+		if job.swap == nil {
+			continue
+		}
+		if err := emit(ctx, p.out, *job.swap); err != nil {
+			return
+		}
+	}
+}
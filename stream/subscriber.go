@@ -0,0 +1,413 @@
+// Package stream 通过 Solana RPC 的 websocket 订阅（logsSubscribe / blockSubscribe）实时解析交换。
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+// Mode 决定订阅方式
+type Mode int
+
+const (
+	// ModeLogs 使用 logsSubscribe 获取签名，再通过 getTransaction 拉取完整交易
+	ModeLogs Mode = iota
+	// ModeBlock 使用 blockSubscribe 直接接收包含完整交易的区块，节点需开启 --rpc-pubsub-enable-block-subscription
+	ModeBlock
+)
+
+// TransactionFetcher 按签名获取完整交易，*rpc.Client 满足该接口
+type TransactionFetcher interface {
+	GetTransaction(ctx context.Context, txSig solana.Signature, opts *rpc.GetTransactionOpts) (*rpc.GetTransactionResult, error)
+}
+
+// DefaultProgramIDs 是默认订阅的 DEX 程序
+var DefaultProgramIDs = []solana.PublicKey{
+	solanaswapgo.JUPITER_PROGRAM_ID,
+	solanaswapgo.PUMP_FUN_PROGRAM_ID,
+	solanaswapgo.PUMPFUN_AMM_PROGRAM_ID,
+	solanaswapgo.RAYDIUM_V4_PROGRAM_ID,
+	solanaswapgo.RAYDIUM_AMM_PROGRAM_ID,
+	solanaswapgo.RAYDIUM_CPMM_PROGRAM_ID,
+	solanaswapgo.RAYDIUM_CONCENTRATED_LIQUIDITY_PROGRAM_ID,
+	solanaswapgo.RAYDIUM_LAUNCHLAB_PROGRAM_ID,
+	solanaswapgo.METEORA_PROGRAM_ID,
+	solanaswapgo.METEORA_POOLS_PROGRAM_ID,
+	solanaswapgo.METEORA_DLMM_PROGRAM_ID,
+	solanaswapgo.METEORA_DAMM_V2_PROGRAM_ID,
+	solanaswapgo.METEORA_DBC_PROGRAM_ID,
+	solanaswapgo.MOONSHOT_PROGRAM_ID,
+	solanaswapgo.ORCA_PROGRAM_ID,
+	solanaswapgo.OKX_DEX_ROUTER_PROGRAM_ID,
+	solanaswapgo.BOOPFUN_PROGRAM_ID,
+}
+
+// Subscriber 订阅 websocket 通知并输出解析出的交换，断线后自动重连并重新订阅
+type Subscriber struct {
+	endpoint string
+	fetcher  TransactionFetcher
+
+	Mode       Mode
+	ProgramIDs []solana.PublicKey
+	Commitment rpc.CommitmentType
+
+	// ReconnectDelay 为首次重连的等待时间，之后每次翻倍直到 MaxReconnectDelay；订阅成功后重置
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+	// PingInterval 为保活 ping 的间隔，超过两个间隔没有任何消息或 pong 时视为断线
+	PingInterval time.Duration
+
+	// FetchAttempts 为 ModeLogs 下 getTransaction 的最大尝试次数，通知到达时交易可能尚未可查
+	FetchAttempts   int
+	FetchRetryDelay time.Duration
+	// FetchWorkers 为 ModeLogs 下并发拉取交易的 goroutine 数量，拉取不在 websocket 读取循环中进行
+	FetchWorkers int
+	// Unordered 为 true 时交换按拉取完成的顺序输出，否则按通知到达的顺序输出
+	Unordered bool
+
+	// DedupeSize 为按签名去重时保留的最近签名数量
+	DedupeSize int
+
+	Log *logrus.Logger
+}
+
+// NewSubscriber 创建订阅器，endpoint 为 ws(s):// 地址；ModeLogs 下 fetcher 用于拉取完整交易
+func NewSubscriber(endpoint string, fetcher TransactionFetcher) *Subscriber {
+	log := logrus.New()
+	log.SetFormatter(&logrus.TextFormatter{
+		TimestampFormat: "2006-01-02 15:04:05",
+		FullTimestamp:   true,
+	})
+
+	return &Subscriber{
+		endpoint:          endpoint,
+		fetcher:           fetcher,
+		Mode:              ModeLogs,
+		ProgramIDs:        DefaultProgramIDs,
+		Commitment:        rpc.CommitmentConfirmed,
+		ReconnectDelay:    time.Second,
+		MaxReconnectDelay: 30 * time.Second,
+		PingInterval:      30 * time.Second,
+		FetchAttempts:     5,
+		FetchRetryDelay:   500 * time.Millisecond,
+		FetchWorkers:      16,
+		DedupeSize:        100000,
+		Log:               log,
+	}
+}
+
+// Run 持续订阅并将每笔包含交换的交易写入 out，直到 ctx 取消。
+// out 写满时停止读取 websocket，由 TCP 流控对节点形成背压；同一签名只输出一次，
+// 包括多个程序订阅命中同一交易以及重连后节点重放的通知。
+func (s *Subscriber) Run(ctx context.Context, out chan<- solanaswapgo.BlockSwap) error {
+	if len(s.ProgramIDs) == 0 {
+		return fmt.Errorf("no program IDs to subscribe")
+	}
+	if s.Mode == ModeLogs && s.fetcher == nil {
+		return fmt.Errorf("logs mode requires a transaction fetcher")
+	}
+
+	seen := newSignatureTracker(s.DedupeSize)
+	// worker 跨越重连持续运行，断线前已分发的签名仍会被拉取和输出
+	var pool *fetchPool
+	if s.Mode == ModeLogs {
+		pool = s.startFetchPool(ctx, seen, out)
+		defer pool.Close()
+	}
+
+	delay := s.ReconnectDelay
+	for {
+		subscribed, err := s.runConnection(ctx, seen, pool, out)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if subscribed {
+			delay = s.ReconnectDelay
+		}
+
+		s.Log.Warnf("websocket disconnected: %s, reconnecting in %s", err, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		delay *= 2
+		if delay > s.MaxReconnectDelay {
+			delay = s.MaxReconnectDelay
+		}
+	}
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcMessage struct {
+	ID    *int `json:"id"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	Method string `json:"method"`
+	Params struct {
+		Result json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+type logsNotification struct {
+	Value struct {
+		Signature string          `json:"signature"`
+		Err       json.RawMessage `json:"err"`
+	} `json:"value"`
+}
+
+type blockNotification struct {
+	Value struct {
+		Slot  uint64              `json:"slot"`
+		Block *rpc.GetBlockResult `json:"block"`
+	} `json:"value"`
+}
+
+// runConnection 建立一次连接并处理通知直到出错；subscribed 表示全部订阅是否已确认
+func (s *Subscriber) runConnection(ctx context.Context, seen *signatureTracker, pool *fetchPool, out chan<- solanaswapgo.BlockSwap) (subscribed bool, err error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, s.endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("error dialing %s: %s", s.endpoint, err)
+	}
+	defer conn.Close()
+
+	// ReadMessage 不感知 ctx，取消时关闭连接使其返回
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if s.PingInterval > 0 {
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(2 * s.PingInterval))
+		})
+		done := make(chan struct{})
+		defer close(done)
+		go s.keepAlive(conn, done)
+	}
+
+	for i, programID := range s.ProgramIDs {
+		if err := conn.WriteJSON(s.subscribeRequest(i+1, programID)); err != nil {
+			return false, fmt.Errorf("error sending subscribe request: %s", err)
+		}
+	}
+
+	pending := len(s.ProgramIDs)
+	for {
+		// 截止时间在每次读取前刷新，消费端阻塞期间不会被计为超时
+		if s.PingInterval > 0 {
+			conn.SetReadDeadline(time.Now().Add(2 * s.PingInterval))
+		}
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			return subscribed, fmt.Errorf("error reading message: %s", err)
+		}
+
+		var msg rpcMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			s.Log.Errorf("error decoding message: %s", err)
+			continue
+		}
+
+		switch {
+		case msg.ID != nil:
+			if msg.Error != nil {
+				return subscribed, fmt.Errorf("error subscribing: %s", msg.Error.Message)
+			}
+			if pending--; pending == 0 {
+				subscribed = true
+			}
+
+		case msg.Method == "logsNotification":
+			if err := s.handleLogs(ctx, msg.Params.Result, seen, pool); err != nil {
+				return subscribed, err
+			}
+
+		case msg.Method == "blockNotification":
+			if err := s.handleBlock(ctx, msg.Params.Result, seen, out); err != nil {
+				return subscribed, err
+			}
+		}
+	}
+}
+
+func (s *Subscriber) keepAlive(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(s.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(s.PingInterval)); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+func (s *Subscriber) subscribeRequest(id int, programID solana.PublicKey) rpcRequest {
+	if s.Mode == ModeBlock {
+		maxTxVersion := uint64(0)
+		return rpcRequest{
+			JSONRPC: "2.0",
+			ID:      id,
+			Method:  "blockSubscribe",
+			Params: []interface{}{
+				map[string]string{"mentionsAccountOrProgram": programID.String()},
+				map[string]interface{}{
+					"commitment":                     s.Commitment,
+					"encoding":                       solana.EncodingBase64,
+					"transactionDetails":             rpc.TransactionDetailsFull,
+					"showRewards":                    false,
+					"maxSupportedTransactionVersion": maxTxVersion,
+				},
+			},
+		}
+	}
+
+	return rpcRequest{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  "logsSubscribe",
+		Params: []interface{}{
+			map[string][]string{"mentions": {programID.String()}},
+			map[string]interface{}{"commitment": s.Commitment},
+		},
+	}
+}
+
+// handleLogs 将通知中的签名交给 pool 拉取，只在 pool 忙碌时阻塞读取循环
+func (s *Subscriber) handleLogs(ctx context.Context, raw json.RawMessage, seen *signatureTracker, pool *fetchPool) error {
+	var notification logsNotification
+	if err := json.Unmarshal(raw, &notification); err != nil {
+		s.Log.Errorf("error decoding logs notification: %s", err)
+		return nil
+	}
+	// 失败交易的 err 不为 null
+	if len(notification.Value.Err) > 0 && string(notification.Value.Err) != "null" {
+		return nil
+	}
+
+	signature, err := solana.SignatureFromBase58(notification.Value.Signature)
+	if err != nil {
+		s.Log.Errorf("invalid signature %q: %s", notification.Value.Signature, err)
+		return nil
+	}
+	if !seen.Claim(signature) {
+		return nil
+	}
+	return pool.Dispatch(ctx, signature)
+}
+
+// fetchSwap 拉取并解析交易，拉取失败、不含交换或解析失败时返回 nil。
+// 拉取失败的签名不计入去重，重连后重放的通知会再次拉取
+func (s *Subscriber) fetchSwap(ctx context.Context, seen *signatureTracker, signature solana.Signature) *solanaswapgo.BlockSwap {
+	txResult, err := s.fetchTransaction(ctx, signature)
+	seen.Release(signature, err == nil)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.Log.Errorf("error fetching transaction %s: %s", signature, err)
+		}
+		return nil
+	}
+
+	swap, err := solanaswapgo.ParseTransactionResult(txResult)
+	if err != nil {
+		s.Log.Errorf("error parsing transaction %s: %s", signature, err)
+		return nil
+	}
+	return swap
+}
+
+func (s *Subscriber) handleBlock(ctx context.Context, raw json.RawMessage, seen *signatureTracker, out chan<- solanaswapgo.BlockSwap) error {
+	var notification blockNotification
+	if err := json.Unmarshal(raw, &notification); err != nil {
+		s.Log.Errorf("error decoding block notification: %s", err)
+		return nil
+	}
+	if notification.Value.Block == nil {
+		return nil
+	}
+
+	swaps, err := solanaswapgo.ParseBlock(notification.Value.Slot, notification.Value.Block)
+	if err != nil {
+		s.Log.Errorf("error parsing block %d: %s", notification.Value.Slot, err)
+	}
+
+	for _, swap := range swaps {
+		if !seen.Add(swap.Signature) {
+			continue
+		}
+		if err := emit(ctx, out, swap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchTransaction 拉取完整交易，交易尚未可查时按 FetchRetryDelay 重试
+func (s *Subscriber) fetchTransaction(ctx context.Context, signature solana.Signature) (*rpc.GetTransactionResult, error) {
+	commitment := s.Commitment
+	// getTransaction 不支持 processed
+	if commitment == rpc.CommitmentProcessed {
+		commitment = rpc.CommitmentConfirmed
+	}
+	maxTxVersion := uint64(0)
+	opts := &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     commitment,
+		MaxSupportedTransactionVersion: &maxTxVersion,
+	}
+
+	attempts := s.FetchAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(s.FetchRetryDelay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		txResult, err := s.fetcher.GetTransaction(ctx, signature, opts)
+		if err == nil && txResult != nil {
+			return txResult, nil
+		}
+		if err == nil {
+			err = rpc.ErrNotFound
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// emit 阻塞写入 out，消费端跟不上时暂停读取
+func emit(ctx context.Context, out chan<- solanaswapgo.BlockSwap, swap solanaswapgo.BlockSwap) error {
+	select {
+	case out <- swap:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
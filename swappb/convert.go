@@ -0,0 +1,103 @@
+// Package swappb 是解析结果的 protobuf 表示（swap.proto），以及从库中结构体转换的函数。
+//
+// 修改 swap.proto 后使用 protoc-gen-go v1.34.2 重新生成 swap.pb.go：
+//
+//	protoc --go_out=. --go_opt=paths=source_relative swappb/swap.proto
+package swappb
+
+import (
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FromBlockSwap 将 BlockSwap 转换为 ParseResult，交换腿按 solanaswapgo.NewLegs 规范化
+func FromBlockSwap(swap solanaswapgo.BlockSwap) *ParseResult {
+	result := &ParseResult{
+		Transaction: &TransactionMeta{
+			Signature:        swap.Signature.String(),
+			Slot:             swap.Slot,
+			TransactionIndex: int64(swap.TransactionIndex),
+			Fees:             &Fees{NetworkFee: swap.Fee},
+		},
+		SwapInfo: FromSwapInfo(swap.SwapInfo),
+	}
+	if info := swap.SwapInfo; info != nil && !info.Timestamp.IsZero() {
+		result.Transaction.BlockTime = timestamppb.New(info.Timestamp)
+	}
+
+	for _, leg := range solanaswapgo.NewLegs(swap) {
+		result.Legs = append(result.Legs, fromLeg(leg))
+	}
+	return result
+}
+
+// FromBlockSwaps 将一个区块的解析结果转换为 BlockResult
+func FromBlockSwaps(slot uint64, swaps []solanaswapgo.BlockSwap) *BlockResult {
+	result := &BlockResult{Slot: slot, Transactions: make([]*ParseResult, 0, len(swaps))}
+	for _, swap := range swaps {
+		result.Transactions = append(result.Transactions, FromBlockSwap(swap))
+	}
+	return result
+}
+
+// FromSwapInfo 将 SwapInfo 转换为 protobuf 消息，info 为 nil 时返回 nil
+func FromSwapInfo(info *solanaswapgo.SwapInfo) *SwapInfo {
+	if info == nil {
+		return nil
+	}
+
+	result := &SwapInfo{
+		Signers:    make([]string, 0, len(info.Signers)),
+		Signatures: make([]string, 0, len(info.Signatures)),
+		Amms:       append([]string(nil), info.AMMs...),
+		TokenIn:    tokenAmount(info.TokenInMint.String(), info.TokenInAmount, info.TokenInDecimals),
+		TokenOut:   tokenAmount(info.TokenOutMint.String(), info.TokenOutAmount, info.TokenOutDecimals),
+		Platform:   info.Platform,
+	}
+	if fees := info.Fees; fees != nil {
+		result.Fees = &SwapFees{
+			ProtocolFee: tokenAmount(fees.Mint.String(), fees.ProtocolFee, fees.Decimals),
+			CreatorFee:  tokenAmount(fees.Mint.String(), fees.CreatorFee, fees.Decimals),
+			LpFee:       tokenAmount(fees.Mint.String(), fees.LPFee, fees.Decimals),
+			PlatformFee: tokenAmount(fees.Mint.String(), fees.PlatformFee, fees.Decimals),
+			TradeFee:    tokenAmount(fees.Mint.String(), fees.TradeFee, fees.Decimals),
+		}
+	}
+	for _, signer := range info.Signers {
+		result.Signers = append(result.Signers, signer.String())
+	}
+	for _, signature := range info.Signatures {
+		result.Signatures = append(result.Signatures, signature.String())
+	}
+	return result
+}
+
+func fromLeg(leg solanaswapgo.Leg) *SwapLeg {
+	result := &SwapLeg{
+		Index:       uint32(leg.LegIndex),
+		Protocol:    leg.Protocol,
+		DataType:    leg.DataType,
+		Pool:        leg.Pool,
+		Trader:      leg.Trader,
+		Source:      leg.Source,
+		Destination: leg.Destination,
+		Authority:   leg.Authority,
+		Platform:    leg.Platform,
+	}
+	if leg.InputMint != "" {
+		result.Input = tokenAmount(leg.InputMint, leg.InputAmount, uint8(leg.InputDecimals))
+	}
+	if leg.OutputMint != "" {
+		result.Output = tokenAmount(leg.OutputMint, leg.OutputAmount, uint8(leg.OutputDecimals))
+	}
+	return result
+}
+
+func tokenAmount(mint string, amount uint64, decimals uint8) *TokenAmount {
+	return &TokenAmount{
+		Mint:     mint,
+		Amount:   amount,
+		Decimals: uint32(decimals),
+		UiAmount: solanaswapgo.FormatAmount(amount, decimals),
+	}
+}
@@ -0,0 +1,205 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/zzispp/solana-dex-parse/backfill"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/rpctest"
+)
+
+// memorySink 记录写入的交换，failAt 大于 0 时第 failAt 次写入返回错误
+type memorySink struct {
+	swaps   []solanaswapgo.BlockSwap
+	flushed int
+	failAt  int
+}
+
+func (s *memorySink) Write(swap solanaswapgo.BlockSwap) error {
+	if s.failAt > 0 && len(s.swaps)+1 == s.failAt {
+		return fmt.Errorf("sink full")
+	}
+	s.swaps = append(s.swaps, swap)
+	return nil
+}
+
+func (s *memorySink) Flush() error {
+	s.flushed = len(s.swaps)
+	return nil
+}
+
+func (s *memorySink) signatures() []string {
+	signatures := make([]string, 0, len(s.swaps))
+	for _, swap := range s.swaps {
+		signatures = append(signatures, swap.Signature.String())
+	}
+	return signatures
+}
+
+// addressHistory 返回语料中涉及 address 的签名，顺序与 getSignaturesForAddress 相同（从新到旧）
+func addressHistory(t *testing.T, corpus []corpusTx, address solana.PublicKey) []corpusTx {
+	t.Helper()
+
+	var history []corpusTx
+	for _, ctx := range corpus {
+		keys := append(append(append(solana.PublicKeySlice{}, ctx.tx.Message.AccountKeys...), ctx.result.Meta.LoadedAddresses.Writable...), ctx.result.Meta.LoadedAddresses.ReadOnly...)
+		if keys.Contains(address) {
+			history = append(history, ctx)
+		}
+	}
+	// 与 rpctest 一致：同一 slot 内后添加的交易在前
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+	sort.SliceStable(history, func(i, j int) bool { return history[i].result.Slot > history[j].result.Slot })
+	return history
+}
+
+func newTestRunner(server *rpctest.Server, address solana.PublicKey, sink backfill.Sink, checkpointPath string) *backfill.Runner {
+	runner := backfill.NewRunner(address, server.Client(), sink)
+	runner.CheckpointPath = checkpointPath
+	runner.PageSize = 2
+	runner.Concurrency = 3
+	runner.FetchRetryDelay = 10 * time.Millisecond
+	runner.Log.SetOutput(io.Discard)
+	return runner
+}
+
+func TestBackfill(t *testing.T) {
+	server := newRPCTestServer(t)
+	address := solana.TokenProgramID
+	history := addressHistory(t, loadCorpus(t), address)
+	if len(history) < 3 {
+		t.Fatalf("语料中涉及 %s 的交易太少", address)
+	}
+
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+	sink := &memorySink{}
+	checkpoint, err := newTestRunner(server, address, sink, checkpointPath).Run(context.Background())
+	if err != nil {
+		t.Fatalf("error running backfill: %s", err)
+	}
+
+	got := sink.signatures()
+	if len(got) != len(history) {
+		t.Fatalf("应写入 %d 笔交换，实际 %d", len(history), len(got))
+	}
+	for i, ctx := range history {
+		if got[i] != ctx.signature {
+			t.Errorf("第 %d 笔应为 %s，实际 %s", i, ctx.signature, got[i])
+		}
+		if sink.swaps[i].Slot != ctx.result.Slot || sink.swaps[i].SwapInfo == nil {
+			t.Errorf("%s: slot 或 SwapInfo 不正确", ctx.signature)
+		}
+	}
+	if sink.flushed != len(history) {
+		t.Errorf("完成时所有写入都应已 Flush，实际 %d", sink.flushed)
+	}
+
+	oldest := history[len(history)-1]
+	if !checkpoint.Done || checkpoint.Processed != uint64(len(history)) || checkpoint.Swaps != uint64(len(history)) {
+		t.Errorf("检查点统计不正确: %+v", checkpoint)
+	}
+	if checkpoint.Before != oldest.signature || checkpoint.Slot != oldest.result.Slot {
+		t.Errorf("检查点应停在最旧的签名 %s，实际 %s", oldest.signature, checkpoint.Before)
+	}
+	saved, err := backfill.LoadCheckpoint(checkpointPath)
+	if err != nil || saved == nil || *saved != *checkpoint {
+		t.Errorf("检查点文件与返回值不一致: %+v %v", saved, err)
+	}
+
+	// 已完成的检查点不再请求 RPC
+	calls := server.Requests("getSignaturesForAddress") + server.Requests("getTransaction")
+	if _, err := newTestRunner(server, address, &memorySink{}, checkpointPath).Run(context.Background()); err != nil {
+		t.Fatalf("error rerunning backfill: %s", err)
+	}
+	if after := server.Requests("getSignaturesForAddress") + server.Requests("getTransaction"); after != calls {
+		t.Errorf("已完成的回填不应再请求 RPC，多出 %d 次", after-calls)
+	}
+
+	if _, err := newTestRunner(server, solana.SystemProgramID, &memorySink{}, checkpointPath).Run(context.Background()); err == nil {
+		t.Error("其他地址的检查点应返回错误")
+	}
+}
+
+func TestBackfillResume(t *testing.T) {
+	server := newRPCTestServer(t)
+	address := solana.TokenProgramID
+	history := addressHistory(t, loadCorpus(t), address)
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	// 第二页的第一笔写入失败，检查点应停在第一页末尾
+	failing := &memorySink{failAt: 3}
+	if _, err := newTestRunner(server, address, failing, checkpointPath).Run(context.Background()); err == nil {
+		t.Fatal("sink 写入失败时应返回错误")
+	}
+	saved, err := backfill.LoadCheckpoint(checkpointPath)
+	if err != nil || saved == nil {
+		t.Fatalf("应保存检查点: %v", err)
+	}
+	if saved.Done || saved.Processed != 2 || saved.Before != history[1].signature {
+		t.Fatalf("检查点应停在第一页末尾 %s: %+v", history[1].signature, saved)
+	}
+
+	resumed := &memorySink{}
+	checkpoint, err := newTestRunner(server, address, resumed, checkpointPath).Run(context.Background())
+	if err != nil {
+		t.Fatalf("error resuming backfill: %s", err)
+	}
+	got := resumed.signatures()
+	if len(got) != len(history)-2 {
+		t.Fatalf("恢复后应写入 %d 笔交换，实际 %d", len(history)-2, len(got))
+	}
+	for i, signature := range got {
+		if signature != history[i+2].signature {
+			t.Errorf("恢复后第 %d 笔应为 %s，实际 %s", i, history[i+2].signature, signature)
+		}
+	}
+	if !checkpoint.Done || checkpoint.Processed != uint64(len(history)) {
+		t.Errorf("恢复后的检查点统计不正确: %+v", checkpoint)
+	}
+}
+
+func TestBackfillBounds(t *testing.T) {
+	server := newRPCTestServer(t)
+	address := solana.TokenProgramID
+	history := addressHistory(t, loadCorpus(t), address)
+
+	sink := &memorySink{}
+	runner := newTestRunner(server, address, sink, "")
+	runner.Until = history[2].tx.Signatures[0]
+	runner.RateLimit = 1000
+	checkpoint, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("error running backfill: %s", err)
+	}
+	if got := sink.signatures(); len(got) != 2 || got[0] != history[0].signature || got[1] != history[1].signature {
+		t.Errorf("Until 应在 %s 之前停止，实际 %v", history[2].signature, got)
+	}
+	if !checkpoint.Done {
+		t.Error("到达 Until 后应标记完成")
+	}
+
+	minSlot := history[0].result.Slot
+	sink = &memorySink{}
+	runner = newTestRunner(server, address, sink, "")
+	runner.MinSlot = minSlot
+	if _, err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("error running backfill: %s", err)
+	}
+	for _, swap := range sink.swaps {
+		if swap.Slot < minSlot {
+			t.Errorf("%s: slot %d 小于 MinSlot %d", swap.Signature, swap.Slot, minSlot)
+		}
+	}
+	if len(sink.swaps) == 0 || len(sink.swaps) == len(history) {
+		t.Errorf("MinSlot 应截断回填，实际写入 %d 笔", len(sink.swaps))
+	}
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+// corpusBlock 将语料中的交易按文件顺序拼成一个 getBlock 响应
+func corpusBlock(t *testing.T, corpus []corpusTx) *rpc.GetBlockResult {
+	t.Helper()
+
+	var block rpc.GetBlockResult
+	if err := json.Unmarshal(corpusBlockJSON(t, corpus), &block); err != nil {
+		t.Fatalf("error decoding block: %s", err)
+	}
+	return &block
+}
+
+// corpusBlockJSON 将语料拼成一个 getBlock 响应
+func corpusBlockJSON(t *testing.T, corpus []corpusTx) []byte {
+	t.Helper()
+
+	type rawTx struct {
+		Transaction json.RawMessage `json:"transaction"`
+		Meta        json.RawMessage `json:"meta"`
+		Version     json.RawMessage `json:"version"`
+	}
+
+	txs := make([]rawTx, 0, len(corpus))
+	for _, ctx := range corpus {
+		var tx rawTx
+		if err := json.Unmarshal(ctx.raw, &tx); err != nil {
+			t.Fatalf("error decoding %s: %s", ctx.signature, err)
+		}
+		txs = append(txs, tx)
+	}
+
+	raw, err := json.Marshal(map[string]interface{}{
+		"blockhash":         "11111111111111111111111111111111",
+		"previousBlockhash": "11111111111111111111111111111111",
+		"parentSlot":        279999999,
+		"blockTime":         1716000000,
+		"transactions":      txs,
+	})
+	if err != nil {
+		t.Fatalf("error encoding block: %s", err)
+	}
+	return raw
+}
+
+func TestParseBlock(t *testing.T) {
+	corpus := loadCorpus(t)
+	block := corpusBlock(t, corpus)
+
+	results, err := solanaswapgo.ParseBlock(280000000, block)
+	if err != nil {
+		t.Fatalf("error parsing block: %s", err)
+	}
+
+	if len(results) != len(corpus) {
+		t.Fatalf("应该解析出 %d 笔交换交易，实际 %d", len(corpus), len(results))
+	}
+	for i, result := range results {
+		if result.TransactionIndex != i {
+			t.Errorf("交易下标应为 %d，实际 %d", i, result.TransactionIndex)
+		}
+		if result.Slot != 280000000 {
+			t.Errorf("slot 应为 280000000，实际 %d", result.Slot)
+		}
+		if result.Signature.String() != corpus[i].signature {
+			t.Errorf("签名应为 %s，实际 %s", corpus[i].signature, result.Signature)
+		}
+		if result.SwapInfo == nil || result.SwapInfo.Timestamp.IsZero() {
+			t.Errorf("交易 %d 应该有 SwapInfo 和区块时间", i)
+		}
+	}
+}
+
+func TestParseBlockConcurrent(t *testing.T) {
+	corpus := loadCorpus(t)
+	block := corpusBlock(t, corpus)
+
+	expected, err := solanaswapgo.ParseBlock(280000000, block)
+	if err != nil {
+		t.Fatalf("error parsing block: %s", err)
+	}
+
+	results, err := solanaswapgo.ParseBlockConcurrent(context.Background(), 280000000, block, 4)
+	if err != nil {
+		t.Fatalf("error parsing block concurrently: %s", err)
+	}
+
+	if len(results) != len(expected) {
+		t.Fatalf("并发结果数量应为 %d，实际 %d", len(expected), len(results))
+	}
+	for i := range results {
+		if results[i].Signature != expected[i].Signature || results[i].TransactionIndex != expected[i].TransactionIndex {
+			t.Errorf("第 %d 个结果顺序不一致", i)
+		}
+	}
+}
+
+func TestParseBlockConcurrentCanceled(t *testing.T) {
+	block := corpusBlock(t, loadCorpus(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := solanaswapgo.ParseBlockConcurrent(ctx, 280000000, block, 2)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("取消后应返回 context.Canceled，实际 %v", err)
+	}
+}
@@ -0,0 +1,277 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/parquet-go/parquet-go"
+	"github.com/zzispp/solana-dex-parse/backfill"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/sink"
+)
+
+// buildCommand 编译 cmd 下的命令到临时目录
+func buildCommand(t *testing.T, name string) string {
+	t.Helper()
+
+	binary := filepath.Join(t.TempDir(), name)
+	build := exec.Command("go", "build", "-o", binary, "../cmd/"+name)
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("error building %s: %s\n%s", name, err, output)
+	}
+	return binary
+}
+
+type cliRecord struct {
+	Input     string                  `json:"input"`
+	Signature string                  `json:"signature"`
+	Slot      uint64                  `json:"slot"`
+	SwapInfo  *solanaswapgo.SwapInfo  `json:"swapInfo"`
+	Swaps     []solanaswapgo.SwapData `json:"swaps"`
+	Error     string                  `json:"error"`
+}
+
+func runCLI(t *testing.T, binary string, stdin string, args ...string) (string, int) {
+	t.Helper()
+
+	cmd := exec.Command(binary, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+
+	exitCode := 0
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		exitCode = exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatalf("error running %s: %s", binary, err)
+	}
+	return stdout.String(), exitCode
+}
+
+func TestDexparseCLI(t *testing.T) {
+	corpus := loadCorpus(t)
+	binary := buildCommand(t, "dexparse")
+
+	checkRecord := func(t *testing.T, ctx corpusTx, rec cliRecord) {
+		t.Helper()
+		if rec.Error != "" || rec.SwapInfo == nil {
+			t.Fatalf("%s: 解析失败: %s", ctx.signature, rec.Error)
+		}
+		if rec.Signature != ctx.signature || rec.Slot != ctx.result.Slot {
+			t.Errorf("%s: 签名或 slot 不正确: %s %d", ctx.signature, rec.Signature, rec.Slot)
+		}
+		if rec.SwapInfo.Timestamp.Unix() != int64(*ctx.result.BlockTime) {
+			t.Errorf("%s: 时间戳应取区块时间: %s", ctx.signature, rec.SwapInfo.Timestamp)
+		}
+
+		expected, err := parseCorpusTx(ctx)
+		if err != nil {
+			t.Fatalf("error parsing transaction: %s", err)
+		}
+		got, want := *rec.SwapInfo, *expected
+		got.Timestamp, want.Timestamp = time.Time{}, time.Time{}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: CLI 输出与解析结果不一致\n got: %+v\nwant: %+v", ctx.signature, got, want)
+		}
+	}
+
+	t.Run("json files", func(t *testing.T) {
+		args := []string{"--legs"}
+		for _, ctx := range corpus {
+			args = append(args, filepath.Join(syntheticDir, ctx.protocol, ctx.signature+".json"))
+		}
+
+		stdout, exitCode := runCLI(t, binary, "", args...)
+		if exitCode != 0 {
+			t.Fatalf("退出码应为 0，实际 %d", exitCode)
+		}
+
+		var records []cliRecord
+		if err := json.Unmarshal([]byte(stdout), &records); err != nil {
+			t.Fatalf("输出应为 JSON 数组: %s", err)
+		}
+		if len(records) != len(corpus) {
+			t.Fatalf("应输出 %d 条记录，实际 %d", len(corpus), len(records))
+		}
+		for i, rec := range records {
+			checkRecord(t, corpus[i], rec)
+			if len(rec.Swaps) == 0 {
+				t.Errorf("%s: --legs 应输出全部交换", rec.Signature)
+			}
+		}
+	})
+
+	t.Run("jsonl stdin", func(t *testing.T) {
+		var stdin strings.Builder
+		for _, ctx := range corpus {
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, ctx.raw); err != nil {
+				t.Fatalf("error compacting %s: %s", ctx.signature, err)
+			}
+			stdin.Write(compact.Bytes())
+			stdin.WriteString("\n")
+		}
+		// 没有 --rpc 时签名行应输出错误记录，且退出码为 1
+		stdin.WriteString(corpus[0].signature + "\n")
+
+		stdout, exitCode := runCLI(t, binary, stdin.String(), "--format", "jsonl")
+		if exitCode != 1 {
+			t.Errorf("存在失败记录时退出码应为 1，实际 %d", exitCode)
+		}
+
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if len(lines) != len(corpus)+1 {
+			t.Fatalf("应输出 %d 行，实际 %d", len(corpus)+1, len(lines))
+		}
+		for i, line := range lines {
+			var rec cliRecord
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				t.Fatalf("第 %d 行不是 JSON: %s", i+1, err)
+			}
+			if i == len(corpus) {
+				if rec.Error == "" || rec.Signature != corpus[0].signature {
+					t.Errorf("签名行应输出错误记录: %+v", rec)
+				}
+				continue
+			}
+			checkRecord(t, corpus[i], rec)
+			if rec.Swaps != nil {
+				t.Errorf("%s: 未指定 --legs 时不应输出交换明细", rec.Signature)
+			}
+		}
+	})
+
+	t.Run("rpc signatures", func(t *testing.T) {
+		server := newRPCTestServer(t)
+
+		args := []string{"--format", "jsonl", "--rpc", server.URL}
+		for _, ctx := range corpus {
+			args = append(args, ctx.signature)
+		}
+		stdout, exitCode := runCLI(t, binary, "", args...)
+		if exitCode != 0 {
+			t.Fatalf("退出码应为 0，实际 %d", exitCode)
+		}
+
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if len(lines) != len(corpus) {
+			t.Fatalf("应输出 %d 行，实际 %d", len(corpus), len(lines))
+		}
+		for i, line := range lines {
+			var rec cliRecord
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				t.Fatalf("第 %d 行不是 JSON: %s", i+1, err)
+			}
+			checkRecord(t, corpus[i], rec)
+		}
+		if calls := server.Requests("getTransaction"); calls != len(corpus) {
+			t.Errorf("应请求 %d 次 getTransaction，实际 %d", len(corpus), calls)
+		}
+	})
+
+	t.Run("backfill", func(t *testing.T) {
+		server := newRPCTestServer(t)
+		history := addressHistory(t, corpus, solana.TokenProgramID)
+		out := filepath.Join(t.TempDir(), "swaps.csv")
+
+		args := []string{"backfill", "--rpc", server.URL, "--address", solana.TokenProgramID.String(), "--out", out, "--page-size", "2"}
+		stdout, exitCode := runCLI(t, binary, "", args...)
+		if exitCode != 0 {
+			t.Fatalf("退出码应为 0，实际 %d", exitCode)
+		}
+		if !strings.Contains(stdout, "done=true") {
+			t.Errorf("应输出回填统计: %s", stdout)
+		}
+
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatalf("error reading output: %s", err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		if len(lines) != len(history)+1 {
+			t.Errorf("CSV 应包含表头和 %d 行数据，实际 %d 行", len(history), len(lines))
+		}
+		checkpoint, err := backfill.LoadCheckpoint(out + ".checkpoint.json")
+		if err != nil || checkpoint == nil || !checkpoint.Done {
+			t.Errorf("应在输出旁保存已完成的检查点: %+v %v", checkpoint, err)
+		}
+
+		// 已完成的回填再次运行不会追加数据
+		if _, exitCode := runCLI(t, binary, "", args...); exitCode != 0 {
+			t.Fatalf("退出码应为 0，实际 %d", exitCode)
+		}
+		if again, _ := os.ReadFile(out); !bytes.Equal(again, data) {
+			t.Error("已完成的回填不应再写入数据")
+		}
+
+		if _, exitCode := runCLI(t, binary, "", "backfill", "--rpc", server.URL, "--address", solana.TokenProgramID.String(), "--out", "swaps.txt"); exitCode != 2 {
+			t.Errorf("不支持的输出格式退出码应为 2，实际 %d", exitCode)
+		}
+	})
+
+	t.Run("backfill parquet", func(t *testing.T) {
+		server := newRPCTestServer(t)
+		history := addressHistory(t, corpus, solana.TokenProgramID)
+		dir := t.TempDir()
+
+		stdout, exitCode := runCLI(t, binary, "", "backfill", "--rpc", server.URL, "--address", solana.TokenProgramID.String(),
+			"--out", filepath.Join(dir, "swaps.parquet"), "--slots-per-file", "100000000", "--row-group-size", "4")
+		if exitCode != 0 {
+			t.Fatalf("退出码应为 0，实际 %d", exitCode)
+		}
+		if !strings.Contains(stdout, "done=true") {
+			t.Errorf("应输出回填统计: %s", stdout)
+		}
+
+		files, _ := filepath.Glob(filepath.Join(dir, "swaps-transactions-*.parquet"))
+		var rows int
+		for _, file := range files {
+			part, err := parquet.ReadFile[sink.Row](file)
+			if err != nil {
+				t.Fatalf("error reading %s: %s", file, err)
+			}
+			rows += len(part)
+		}
+		if rows != len(history) {
+			t.Errorf("Parquet 应包含 %d 笔交易，实际 %d（%v）", len(history), rows, files)
+		}
+		if legs, _ := filepath.Glob(filepath.Join(dir, "swaps-legs-*.parquet")); len(legs) == 0 {
+			t.Error("应写出交换腿文件")
+		}
+	})
+
+	t.Run("table", func(t *testing.T) {
+		ctx := corpus[0]
+		stdout, exitCode := runCLI(t, binary, "", "--format", "table", filepath.Join(syntheticDir, ctx.protocol, ctx.signature+".json"))
+		if exitCode != 0 {
+			t.Fatalf("退出码应为 0，实际 %d", exitCode)
+		}
+
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[0], "SIGNATURE") {
+			t.Fatalf("表格应包含表头和一行数据:\n%s", stdout)
+		}
+		expected, _ := parseCorpusTx(ctx)
+		if !strings.Contains(lines[1], strings.Join(expected.AMMs, ",")) {
+			t.Errorf("表格应包含 AMM 名称:\n%s", stdout)
+		}
+	})
+
+	t.Run("invalid flags", func(t *testing.T) {
+		if _, exitCode := runCLI(t, binary, "", "--format", "xml"); exitCode != 2 {
+			t.Errorf("未知格式的退出码应为 2，实际 %d", exitCode)
+		}
+		if _, exitCode := runCLI(t, binary, "", "--commitment", "processed"); exitCode != 2 {
+			t.Errorf("不支持的 commitment 退出码应为 2，实际 %d", exitCode)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/fixture"
+)
+
+// corpusTx 是 testdata 中录制的一笔交易，预先解码以便基准测试只计算解析开销
+type corpusTx struct {
+	protocol  string
+	signature string
+	raw       []byte
+	result    *rpc.GetTransactionResult
+	tx        *solana.Transaction
+}
+
+// syntheticDir 是合成语料的目录，交易由真实的程序 ID 和事件布局构造，不是链上记录
+var syntheticDir = filepath.Join("testdata", "synthetic")
+
+// loadCorpus 读取 testdata/synthetic/<protocol>/<sig>.json 下的所有 getTransaction 结果
+func loadCorpus(tb testing.TB) []corpusTx {
+	tb.Helper()
+
+	paths, err := filepath.Glob(filepath.Join(syntheticDir, "*", "*.json"))
+	if err != nil {
+		tb.Fatalf("error listing corpus: %s", err)
+	}
+	sort.Strings(paths)
+
+	corpus := make([]corpusTx, 0, len(paths))
+	for _, path := range paths {
+		if strings.HasSuffix(path, fixture.GoldenSuffix) {
+			continue
+		}
+		corpus = append(corpus, loadCorpusFile(tb, path))
+	}
+
+	if len(corpus) == 0 {
+		tb.Fatalf("%s 中没有交易", syntheticDir)
+	}
+	return corpus
+}
+
+// loadCorpusFile 读取一个 getTransaction 结果文件，protocol 取自所在目录名
+func loadCorpusFile(tb testing.TB, path string) corpusTx {
+	tb.Helper()
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("error reading %s: %s", path, err)
+	}
+
+	var result rpc.GetTransactionResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		tb.Fatalf("error decoding %s: %s", path, err)
+	}
+
+	tx, err := result.Transaction.GetTransaction()
+	if err != nil {
+		tb.Fatalf("error decoding transaction %s: %s", path, err)
+	}
+
+	return corpusTx{
+		protocol:  filepath.Base(filepath.Dir(path)),
+		signature: strings.TrimSuffix(filepath.Base(path), ".json"),
+		raw:       raw,
+		result:    &result,
+		tx:        tx,
+	}
+}
+
+// loadCorpusTx 读取 testdata/synthetic/<protocol>/<signature>.json
+func loadCorpusTx(tb testing.TB, protocol string, signature string) corpusTx {
+	tb.Helper()
+	return loadCorpusFile(tb, fixture.Path(syntheticDir, protocol, signature))
+}
+
+// newCorpusParser 为语料中的交易创建不输出日志的解析器
+func newCorpusParser(tb testing.TB, ctx corpusTx) *solanaswapgo.Parser {
+	tb.Helper()
+
+	parser, err := solanaswapgo.NewTransactionParserFromTransactionResult(ctx.result, ctx.tx, ctx.result.Meta)
+	if err != nil {
+		tb.Fatalf("%s: error creating parser: %s", ctx.signature, err)
+	}
+	parser.Log = solanaswapgo.DiscardLogger
+	return parser
+}
+
+// parseSwaps 依次执行 ParseTransaction 和 ProcessSwapData，出错时终止测试
+func parseSwaps(tb testing.TB, parser *solanaswapgo.Parser) ([]solanaswapgo.SwapData, *solanaswapgo.SwapInfo) {
+	tb.Helper()
+	parser.Log = solanaswapgo.DiscardLogger
+
+	swaps, err := parser.ParseTransaction()
+	if err != nil {
+		tb.Fatalf("error parsing transaction: %s", err)
+	}
+	swapInfo, err := parser.ProcessSwapData(swaps)
+	if err != nil {
+		tb.Fatalf("error processing swap data: %s", err)
+	}
+	return swaps, swapInfo
+}
+
+// parseSwapInfo 对语料中的交易执行完整的解析流程，出错时终止测试
+func parseSwapInfo(tb testing.TB, ctx corpusTx) ([]solanaswapgo.SwapData, *solanaswapgo.SwapInfo) {
+	tb.Helper()
+	return parseSwaps(tb, newCorpusParser(tb, ctx))
+}
+
+// parseCorpusTx 对单笔交易执行完整的解析流程
+func parseCorpusTx(ctx corpusTx) (*solanaswapgo.SwapInfo, error) {
+	parser, err := solanaswapgo.NewTransactionParserFromTransactionResult(ctx.result, ctx.tx, ctx.result.Meta)
+	if err != nil {
+		return nil, err
+	}
+	parser.Log = solanaswapgo.DiscardLogger
+
+	transactionData, err := parser.ParseTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	return parser.ProcessSwapData(transactionData)
+}
+
+func TestCorpus(t *testing.T) {
+	for _, ctx := range loadCorpus(t) {
+		t.Run(ctx.protocol+"/"+ctx.signature, func(t *testing.T) {
+			swapInfo, err := parseCorpusTx(ctx)
+			if err != nil {
+				t.Fatalf("error parsing transaction: %s", err)
+			}
+			if len(swapInfo.AMMs) == 0 {
+				t.Error("应该解析出 AMM 信息")
+			}
+			if swapInfo.TokenInAmount == 0 || swapInfo.TokenOutAmount == 0 {
+				t.Errorf("输入输出数量不应为 0: in=%d out=%d", swapInfo.TokenInAmount, swapInfo.TokenOutAmount)
+			}
+		})
+	}
+}
+
+// BenchmarkParseTransaction 统计录制的主网交易的解析吞吐 (tx/s) 与内存分配。
+// 没有录制时退回到生成的语料，它不能代表真实交易的规模，结果只用于比较前后两次修改
+func BenchmarkParseTransaction(b *testing.B) {
+	corpus := mainnetCorpus(b)
+	if len(corpus) == 0 {
+		b.Logf("%s 中没有录制的主网交易，使用生成的语料", mainnetDir)
+		corpus = loadCorpus(b)
+	}
+
+	b.Run("all", func(b *testing.B) {
+		benchmarkCorpus(b, corpus)
+	})
+
+	byProtocol := make(map[string][]corpusTx)
+	var protocols []string
+	for _, ctx := range corpus {
+		if _, ok := byProtocol[ctx.protocol]; !ok {
+			protocols = append(protocols, ctx.protocol)
+		}
+		byProtocol[ctx.protocol] = append(byProtocol[ctx.protocol], ctx)
+	}
+
+	for _, protocol := range protocols {
+		txs := byProtocol[protocol]
+		b.Run(protocol, func(b *testing.B) {
+			benchmarkCorpus(b, txs)
+		})
+	}
+}
+
+func benchmarkCorpus(b *testing.B, corpus []corpusTx) {
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, ctx := range corpus {
+			if _, err := parseCorpusTx(ctx); err != nil {
+				b.Fatalf("error parsing %s: %s", ctx.signature, err)
+			}
+		}
+	}
+
+	b.StopTimer()
+	if elapsed := b.Elapsed().Seconds(); elapsed > 0 {
+		b.ReportMetric(float64(b.N*len(corpus))/elapsed, "tx/s")
+	}
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+// replaceProgram 将交易中的 program 账户替换为 replacement，模拟一个尚未支持的 DEX
+func replaceProgram(ctx corpusTx, program, replacement solana.PublicKey) bool {
+	replaced := false
+	for i, key := range ctx.tx.Message.AccountKeys {
+		if key.Equals(program) {
+			ctx.tx.Message.AccountKeys[i] = replacement
+			replaced = true
+		}
+	}
+	return replaced
+}
+
+func analyzeCorpus(t *testing.T, analyzer *solanaswapgo.CoverageAnalyzer, corpus []corpusTx) {
+	t.Helper()
+
+	for _, ctx := range corpus {
+		parser := newCorpusParser(t, ctx)
+		swaps, err := parser.ParseTransaction()
+		if err != nil {
+			t.Fatalf("%s: error parsing transaction: %s", ctx.signature, err)
+		}
+		analyzer.Add(parser, swaps)
+	}
+}
+
+func TestCoverageAnalyzer(t *testing.T) {
+	corpus := loadCorpus(t)
+
+	// 语料全部可以解析，不应有未覆盖的交易
+	analyzer := solanaswapgo.NewCoverageAnalyzer()
+	analyzeCorpus(t, analyzer, corpus)
+	report := analyzer.Report()
+	if report.Transactions != len(corpus) || report.WithSwaps != len(corpus) || report.Uncovered != 0 || len(report.Programs) != 0 {
+		t.Fatalf("语料应全部被覆盖: %+v", report)
+	}
+
+	// 把 Pump.fun 和 Raydium 换成未知程序，这些交易应被统计为未覆盖
+	pumpfun := solana.MustPublicKeyFromBase58("Dex1111111111111111111111111111111111111111")
+	raydium := solana.MustPublicKeyFromBase58("Dex2222222222222222222222222222222222222222")
+	var replaced []corpusTx
+	for _, ctx := range corpus {
+		switch ctx.protocol {
+		case "pumpfun":
+			if replaceProgram(ctx, solanaswapgo.PUMP_FUN_PROGRAM_ID, pumpfun) {
+				replaced = append(replaced, ctx)
+			}
+		case "raydium":
+			if replaceProgram(ctx, solanaswapgo.RAYDIUM_V4_PROGRAM_ID, raydium) {
+				replaced = append(replaced, ctx)
+			}
+		}
+	}
+
+	analyzer = solanaswapgo.NewCoverageAnalyzer()
+	analyzer.MaxExamples = 1
+	analyzeCorpus(t, analyzer, replaced)
+	report = analyzer.Report()
+	if report.Transactions != len(replaced) || report.WithSwaps != 0 || report.Uncovered != len(replaced) {
+		t.Fatalf("替换后的 %d 笔交易应全部未覆盖: %+v", len(replaced), report)
+	}
+
+	counts := make(map[solana.PublicKey]int)
+	for _, ctx := range replaced {
+		if ctx.protocol == "pumpfun" {
+			counts[pumpfun]++
+		} else {
+			counts[raydium]++
+		}
+	}
+	if len(report.Programs) < 2 {
+		t.Fatalf("应统计到替换的两个程序: %+v", report.Programs)
+	}
+	for i, program := range report.Programs {
+		if i > 0 && program.Transactions > report.Programs[i-1].Transactions {
+			t.Errorf("程序应按交易数从多到少排列: %+v", report.Programs)
+		}
+		want, ok := counts[program.Program]
+		if !ok {
+			continue
+		}
+		if program.Transactions != want || program.TopLevel != want {
+			t.Errorf("%s 应在 %d 笔交易的外层指令中出现: %+v", program.Program, want, program)
+		}
+		if program.SwapLike != want {
+			t.Errorf("%s 的交易应被识别为类似交换: %+v", program.Program, program)
+		}
+		if len(program.Mints) == 0 || len(program.Examples) != 1 {
+			t.Errorf("%s 应包含代币和 1 个示例签名: %+v", program.Program, program)
+		}
+		delete(counts, program.Program)
+	}
+	if len(counts) > 0 {
+		t.Errorf("报告中缺少程序: %v", counts)
+	}
+
+	var text bytes.Buffer
+	if err := report.WriteText(&text, 1); err != nil {
+		t.Fatalf("error writing report: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(text.String()), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[3], report.Programs[0].Program.String()) {
+		t.Errorf("文本报告应只包含排名第一的程序:\n%s", text.String())
+	}
+}
+
+func TestCoverageCLI(t *testing.T) {
+	binary := buildCommand(t, "dexparse")
+
+	stdout, exitCode := runCLI(t, binary, "", "coverage", "--format", "json", filepath.Join(syntheticDir, "pumpfun"))
+	if exitCode != 0 {
+		t.Fatalf("退出码应为 0，实际 %d", exitCode)
+	}
+	var report solanaswapgo.CoverageReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("输出不是 JSON: %s\n%s", err, stdout)
+	}
+	want := 0
+	for _, ctx := range loadCorpus(t) {
+		if ctx.protocol == "pumpfun" {
+			want++
+		}
+	}
+	if report.Transactions != want || report.WithSwaps != want {
+		t.Errorf("应分析目录中的 %d 笔交易并跳过 golden 文件: %+v", want, report)
+	}
+
+	if _, exitCode := runCLI(t, binary, "", "coverage", "--format", "xml"); exitCode != 2 {
+		t.Errorf("无效的格式退出码应为 2，实际 %d", exitCode)
+	}
+}
@@ -0,0 +1,217 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/mr-tron/base58"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+func TestParserFromEncodedTransactions(t *testing.T) {
+	for _, ctx := range loadCorpus(t) {
+		t.Run(ctx.protocol+"/"+ctx.signature, func(t *testing.T) {
+			expected, err := parseCorpusTx(ctx)
+			if err != nil {
+				t.Fatalf("error parsing transaction: %s", err)
+			}
+
+			wire, err := ctx.tx.MarshalBinary()
+			if err != nil {
+				t.Fatalf("error encoding transaction: %s", err)
+			}
+
+			parsers := map[string]func() (*solanaswapgo.Parser, error){
+				"wire": func() (*solanaswapgo.Parser, error) {
+					return solanaswapgo.NewTransactionParserFromWire(wire, ctx.result.Meta)
+				},
+				"base58": func() (*solanaswapgo.Parser, error) {
+					return solanaswapgo.NewTransactionParserFromEncoded(base58.Encode(wire), solana.EncodingBase58, ctx.result.Meta)
+				},
+				"base64": func() (*solanaswapgo.Parser, error) {
+					return solanaswapgo.NewTransactionParserFromEncoded(base64.StdEncoding.EncodeToString(wire), solana.EncodingBase64, ctx.result.Meta)
+				},
+				"base64 json": func() (*solanaswapgo.Parser, error) {
+					txJSON, _ := json.Marshal([]string{base64.StdEncoding.EncodeToString(wire), "base64"})
+					metaJSON, _ := json.Marshal(ctx.result.Meta)
+					return solanaswapgo.NewTransactionParserFromJSON(txJSON, metaJSON)
+				},
+				"result json": func() (*solanaswapgo.Parser, error) {
+					return solanaswapgo.NewTransactionParserFromResultJSON(ctx.raw)
+				},
+				"jsonParsed": func() (*solanaswapgo.Parser, error) {
+					txJSON, metaJSON := toJSONParsed(t, ctx)
+					return solanaswapgo.NewTransactionParserFromJSON(txJSON, metaJSON)
+				},
+			}
+
+			for name, newParser := range parsers {
+				parser, err := newParser()
+				if err != nil {
+					t.Fatalf("%s: error creating parser: %s", name, err)
+				}
+				_, swapInfo := parseSwaps(t, parser)
+				// 没有区块时间时 ProcessSwapData 使用当前时间，只比较交换内容
+				got, want := *swapInfo, *expected
+				got.Timestamp, want.Timestamp = time.Time{}, time.Time{}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: 解析结果与 getTransaction 输入不一致\n got: %+v\nwant: %+v", name, swapInfo, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestParserFromEncodedRejectsUnknownEncoding(t *testing.T) {
+	if _, err := solanaswapgo.NewTransactionParserFromEncoded("abc", solana.EncodingJSONParsed, &rpc.TransactionMeta{}); err == nil {
+		t.Error("不支持的编码应该返回错误")
+	}
+}
+
+// toJSONParsed 将语料交易转换为 RPC jsonParsed 编码：账户以对象列出（含查找表地址），
+// SPL Token transfer / transferChecked 以 parsed 形式给出，其余指令按公钥引用账户
+func toJSONParsed(t *testing.T, ctx corpusTx) ([]byte, []byte) {
+	t.Helper()
+
+	tx := ctx.tx
+	meta := ctx.result.Meta
+	header := tx.Message.Header
+
+	allKeys := append(solana.PublicKeySlice{}, tx.Message.AccountKeys...)
+	allKeys = append(allKeys, meta.LoadedAddresses.Writable...)
+	allKeys = append(allKeys, meta.LoadedAddresses.ReadOnly...)
+
+	staticCount := len(tx.Message.AccountKeys)
+	accountKeys := make([]map[string]interface{}, 0, len(allKeys))
+	for i, key := range allKeys {
+		signer := i < int(header.NumRequiredSignatures)
+		var writable bool
+		source := "transaction"
+		switch {
+		case i >= staticCount:
+			source = "lookupTable"
+			writable = i < staticCount+len(meta.LoadedAddresses.Writable)
+		case signer:
+			writable = i < int(header.NumRequiredSignatures-header.NumReadonlySignedAccounts)
+		default:
+			writable = i < staticCount-int(header.NumReadonlyUnsignedAccounts)
+		}
+		accountKeys = append(accountKeys, map[string]interface{}{
+			"pubkey":   key.String(),
+			"signer":   signer,
+			"writable": writable,
+			"source":   source,
+		})
+	}
+
+	decimals := make(map[solana.PublicKey]uint8)
+	for _, balance := range append(meta.PreTokenBalances, meta.PostTokenBalances...) {
+		decimals[balance.Mint] = balance.UiTokenAmount.Decimals
+	}
+
+	convert := func(instruction solana.CompiledInstruction) map[string]interface{} {
+		programID := allKeys[instruction.ProgramIDIndex]
+		data := []byte(instruction.Data)
+		accounts := make([]string, 0, len(instruction.Accounts))
+		for _, index := range instruction.Accounts {
+			accounts = append(accounts, allKeys[index].String())
+		}
+
+		if programID.Equals(solana.TokenProgramID) && len(data) >= 9 {
+			amount := strconv.FormatUint(binary.LittleEndian.Uint64(data[1:9]), 10)
+			switch {
+			case data[0] == 3 && len(accounts) >= 3:
+				return map[string]interface{}{
+					"program":   "spl-token",
+					"programId": programID.String(),
+					"parsed": map[string]interface{}{
+						"type": "transfer",
+						"info": map[string]interface{}{
+							"source":      accounts[0],
+							"destination": accounts[1],
+							"authority":   accounts[2],
+							"amount":      amount,
+						},
+					},
+				}
+			case data[0] == 12 && len(accounts) >= 4:
+				return map[string]interface{}{
+					"program":   "spl-token",
+					"programId": programID.String(),
+					"parsed": map[string]interface{}{
+						"type": "transferChecked",
+						"info": map[string]interface{}{
+							"source":      accounts[0],
+							"mint":        accounts[1],
+							"destination": accounts[2],
+							"authority":   accounts[3],
+							"tokenAmount": map[string]interface{}{
+								"amount":   amount,
+								"decimals": decimals[allKeys[instruction.Accounts[1]]],
+							},
+						},
+					},
+				}
+			}
+		}
+
+		return map[string]interface{}{
+			"programId": programID.String(),
+			"accounts":  accounts,
+			"data":      base58.Encode(data),
+		}
+	}
+
+	instructions := make([]map[string]interface{}, 0, len(tx.Message.Instructions))
+	for _, instruction := range tx.Message.Instructions {
+		instructions = append(instructions, convert(instruction))
+	}
+
+	txJSON, err := json.Marshal(map[string]interface{}{
+		"signatures": tx.Signatures,
+		"message": map[string]interface{}{
+			"accountKeys":     accountKeys,
+			"recentBlockhash": tx.Message.RecentBlockhash,
+			"instructions":    instructions,
+		},
+	})
+	if err != nil {
+		t.Fatalf("error encoding jsonParsed transaction: %s", err)
+	}
+
+	var metaMap map[string]interface{}
+	rawMeta, err := json.Marshal(meta)
+	if err != nil {
+		t.Fatalf("error encoding meta: %s", err)
+	}
+	if err := json.Unmarshal(rawMeta, &metaMap); err != nil {
+		t.Fatalf("error decoding meta: %s", err)
+	}
+
+	innerSets := make([]map[string]interface{}, 0, len(meta.InnerInstructions))
+	for _, set := range meta.InnerInstructions {
+		inner := make([]map[string]interface{}, 0, len(set.Instructions))
+		for _, instruction := range set.Instructions {
+			inner = append(inner, convert(instruction))
+		}
+		innerSets = append(innerSets, map[string]interface{}{
+			"index":        set.Index,
+			"instructions": inner,
+		})
+	}
+	metaMap["innerInstructions"] = innerSets
+
+	metaJSON, err := json.Marshal(metaMap)
+	if err != nil {
+		t.Fatalf("error encoding jsonParsed meta: %s", err)
+	}
+
+	return txJSON, metaJSON
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/zzispp/solana-dex-parse/fixture"
+	"github.com/zzispp/solana-dex-parse/rpctest"
+)
+
+// update 重新生成 golden 文件：go test ./tests -run TestGolden -update
+var update = flag.Bool("update", false, "rewrite testdata golden files with the current parse output")
+
+func TestGolden(t *testing.T) {
+	for _, ctx := range loadCorpus(t) {
+		t.Run(ctx.protocol+"/"+ctx.signature, func(t *testing.T) {
+			checkGolden(t, syntheticDir, ctx)
+		})
+	}
+}
+
+// checkGolden 比较交易的解析结果与 dir 下的 golden 文件，-update 时重写 golden 文件
+func checkGolden(t *testing.T, dir string, ctx corpusTx) {
+	t.Helper()
+
+	got, err := fixture.RenderGolden(ctx.raw)
+	if err != nil {
+		t.Fatalf("error rendering golden: %s", err)
+	}
+
+	path := fixture.GoldenPath(dir, ctx.protocol, ctx.signature)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("error writing %s: %s", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("缺少 golden 文件 %s，使用 -update 生成: %s", path, err)
+	}
+	if diff := firstDiff(string(want), string(got)); diff != "" {
+		t.Errorf("解析结果与 %s 不一致（确认变更后使用 -update 更新）:\n%s", path, diff)
+	}
+}
+
+// firstDiff 返回第一处不同的行，相同时返回空字符串
+func firstDiff(want string, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var wantLine, gotLine string
+		if i < len(wantLines) {
+			wantLine = wantLines[i]
+		}
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if wantLine != gotLine {
+			return fmt.Sprintf("line %d:\n- %s\n+ %s", i+1, wantLine, gotLine)
+		}
+	}
+	return ""
+}
+
+func TestFixtureRecord(t *testing.T) {
+	ctx := loadCorpus(t)[0]
+
+	server := rpctest.NewServer()
+	defer server.Close()
+	if err := server.AddTransaction(ctx.raw); err != nil {
+		t.Fatalf("error adding transaction: %s", err)
+	}
+
+	dir := t.TempDir()
+	client := server.Client()
+	path, err := fixture.Record(context.Background(), client, dir, ctx.protocol, ctx.tx.Signatures[0], rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("error recording: %s", err)
+	}
+	if path != filepath.Join(dir, ctx.protocol, ctx.signature+".json") {
+		t.Errorf("语料路径不正确: %s", path)
+	}
+
+	recorded, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading recorded transaction: %s", err)
+	}
+	var gotTx, wantTx interface{}
+	json.Unmarshal(recorded, &gotTx)
+	json.Unmarshal(ctx.raw, &wantTx)
+	if !jsonEqual(gotTx, wantTx) {
+		t.Error("录制的交易应与 RPC 返回的原始结果一致")
+	}
+
+	golden, err := os.ReadFile(fixture.GoldenPath(dir, ctx.protocol, ctx.signature))
+	if err != nil {
+		t.Fatalf("error reading recorded golden: %s", err)
+	}
+	want, err := os.ReadFile(fixture.GoldenPath(syntheticDir, ctx.protocol, ctx.signature))
+	if err != nil {
+		t.Fatalf("error reading golden: %s", err)
+	}
+	if diff := firstDiff(string(want), string(golden)); diff != "" {
+		t.Errorf("录制生成的 golden 与仓库中的不一致:\n%s", diff)
+	}
+
+	if _, err := fixture.Record(context.Background(), client, dir, ctx.protocol, solana.Signature{1}, rpc.CommitmentConfirmed); err == nil {
+		t.Error("交易不存在时应返回错误")
+	}
+}
+
+func jsonEqual(a interface{}, b interface{}) bool {
+	left, _ := json.Marshal(a)
+	right, _ := json.Marshal(b)
+	return string(left) == string(right)
+}
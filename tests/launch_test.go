@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+const pumpfunCreateSignature = "36YM4zwNdsmD9RRuU6c7ArT6kueq9nh4ihKvxoadDHwk2fVwNUhfzb3fn7uAfcEX4BTNBXW2fdEZijpnKLTMpDo7"
+
+func TestPumpfunTokenLaunch(t *testing.T) {
+	var create *corpusTx
+	corpus := loadCorpus(t)
+	for i, ctx := range corpus {
+		if ctx.signature == pumpfunCreateSignature {
+			create = &corpus[i]
+			continue
+		}
+		if ctx.signature == launchLabCreateSignature {
+			continue
+		}
+		if launches := newCorpusParser(t, ctx).ParseTokenLaunches(); len(launches) != 0 {
+			t.Errorf("%s 不应包含代币创建: %+v", ctx.signature, launches)
+		}
+	}
+	if create == nil {
+		t.Fatal("语料中应有 Pump.fun 创建代币的交易")
+	}
+
+	launches := newCorpusParser(t, *create).ParseTokenLaunches()
+	if len(launches) != 1 {
+		t.Fatalf("应解析出 1 个代币创建，实际 %d", len(launches))
+	}
+	launch := launches[0]
+	if launch.Type != solanaswapgo.PUMP_FUN || launch.Signature != create.tx.Signatures[0] {
+		t.Errorf("类型或签名错误: %+v", launch)
+	}
+	if launch.Name != "Fixture Cat" || launch.Symbol != "FCAT" || launch.URI != "https://ipfs.io/ipfs/fixture-cat" {
+		t.Errorf("代币元数据错误: %+v", launch)
+	}
+	signer := create.tx.Message.AccountKeys[0]
+	if launch.User != signer || launch.Creator != signer || launch.Decimals != 6 || launch.BondingCurve.IsZero() {
+		t.Errorf("账户或精度错误: %+v", launch)
+	}
+	if !launch.Timestamp.Equal(time.Unix(int64(*create.result.BlockTime), 0)) {
+		t.Errorf("时间戳应取自事件: %s", launch.Timestamp)
+	}
+
+	buy := launch.InitialBuy
+	if buy == nil {
+		t.Fatal("应包含创建者的首次买入")
+	}
+	if !buy.IsBuy || buy.Mint != launch.Mint || buy.User != signer || buy.SolAmount != 1_000_000_000 || buy.TokenAmount == 0 {
+		t.Errorf("首次买入错误: %+v", buy)
+	}
+}
+
+func TestPumpfunLegacyCreateEvent(t *testing.T) {
+	var create corpusTx
+	for _, ctx := range loadCorpus(t) {
+		if ctx.signature == pumpfunCreateSignature {
+			create = ctx
+		}
+	}
+	if create.tx == nil {
+		t.Fatal("语料中应有 Pump.fun 创建代币的交易")
+	}
+
+	// 去掉 Creator 之后追加的字段，并删除买入，模拟旧版只创建不买入的交易
+	for i := range create.result.Meta.InnerInstructions {
+		instructions := create.result.Meta.InnerInstructions[i].Instructions
+		kept := instructions[:0]
+		for _, instruction := range instructions {
+			switch {
+			case bytes.HasPrefix(instruction.Data, solanaswapgo.PumpfunCreateEventDiscriminator[:]):
+				instruction.Data = instruction.Data[:len(instruction.Data)-72]
+			case bytes.HasPrefix(instruction.Data, solanaswapgo.PumpfunTradeEventDiscriminator[:]):
+				continue
+			}
+			kept = append(kept, instruction)
+		}
+		create.result.Meta.InnerInstructions[i].Instructions = kept
+	}
+
+	launches := newCorpusParser(t, create).ParseTokenLaunches()
+	if len(launches) != 1 {
+		t.Fatalf("应解析出 1 个代币创建，实际 %d", len(launches))
+	}
+	launch := launches[0]
+	if launch.Creator != launch.User || launch.Symbol != "FCAT" || launch.InitialBuy != nil {
+		t.Errorf("旧版事件的创建者应为 User 且没有首次买入: %+v", launch)
+	}
+	if launch.Timestamp.IsZero() || launch.Mint == (solana.PublicKey{}) {
+		t.Errorf("时间戳应取自区块时间: %+v", launch)
+	}
+}
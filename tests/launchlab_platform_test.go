@@ -0,0 +1,193 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/sink"
+	"github.com/zzispp/solana-dex-parse/swappb"
+	"google.golang.org/protobuf/proto"
+)
+
+const launchLabCreateSignature = "2VSvcw4X5N2WU7Tg5vSRgiYBKkoqZV3GcpWQ14j66onBqqKfweikfZDeoXxDgkMRTYNXGtELNPUmFc2XKYhGRtR"
+
+var bonkPlatformConfig = solana.MustPublicKeyFromBase58("FfYek5vEz23cMkWsdJwG2oa6EphsvXSHrGpdALN4g6W1")
+
+func TestLaunchLabPlatformFees(t *testing.T) {
+	ctx := loadCorpusTx(t, "raydium_launchlab", launchLabBuySignature)
+	event, _, swapInfo := launchLabTradeEvent(t, ctx)
+	if event.Platform != "bonk" || event.PlatformConfig != bonkPlatformConfig {
+		t.Errorf("应按内置列表识别为 bonk: %+v", event)
+	}
+
+	fees := swapInfo.Fees
+	if fees == nil || fees.Mint != event.QuoteMint || fees.Decimals != 9 {
+		t.Fatalf("费用应以 quote 代币计: %+v", fees)
+	}
+	if fees.ProtocolFee != event.ProtocolFee || fees.PlatformFee != 10_000_000 || fees.CreatorFee != event.CreatorFee || fees.LPFee != 0 {
+		t.Errorf("费用与 TradeEvent 不一致: %+v", fees)
+	}
+
+	// protobuf 输出包含平台费用，并且可以往返编码
+	result := swappb.FromSwapInfo(swapInfo)
+	raw, err := proto.Marshal(result)
+	if err != nil {
+		t.Fatalf("error marshaling swap info: %s", err)
+	}
+	var decoded swappb.SwapInfo
+	if err := proto.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("error unmarshaling swap info: %s", err)
+	}
+	if decoded.GetFees().GetPlatformFee().GetAmount() != 10_000_000 || decoded.GetFees().GetPlatformFee().GetMint() != event.QuoteMint.String() {
+		t.Errorf("protobuf 中的平台费用不正确: %+v", decoded.GetFees())
+	}
+
+	// 没有 TradeEvent 时没有费用，但仍然标记平台
+	editInnerInstructions(ctx, solanaswapgo.RaydiumLaunchLabTradeEventDiscriminator[:], func([]byte) ([]byte, bool) { return nil, false })
+	event, _, swapInfo = launchLabTradeEvent(t, ctx)
+	if event.Platform != "bonk" || swapInfo.Fees != nil {
+		t.Errorf("没有 TradeEvent 时不应有费用: %+v %+v", event, swapInfo.Fees)
+	}
+}
+
+func TestLaunchLabPlatformRegistry(t *testing.T) {
+	defer solanaswapgo.SetLaunchLabPlatforms(nil)
+	ctx := loadCorpusTx(t, "raydium_launchlab", launchLabSellSignature)
+	trade := &ctx.tx.Message.Instructions[2]
+	user := ctx.tx.Message.AccountKeys[trade.Accounts[0]]
+
+	// 交易者等普通账户不是平台费用接收者
+	solanaswapgo.SetLaunchLabPlatforms([]solanaswapgo.LaunchLabPlatform{{Name: "fixture", FeeRecipient: user}})
+	if event, _, _ := launchLabTradeEvent(t, ctx); event.Platform != "" {
+		t.Errorf("不应按交易者账户识别平台: %q", event.Platform)
+	}
+
+	// 新版指令在 program 之后追加 system_program、platform_fee_vault 和 creator_fee_vault，只按 platform_fee_vault 匹配
+	globalConfigIndex := trade.Accounts[2]
+	feeVault := ctx.tx.Message.AccountKeys[globalConfigIndex]
+	trade.Accounts = append(trade.Accounts, trade.Accounts[11], globalConfigIndex, trade.Accounts[0])
+	solanaswapgo.SetLaunchLabPlatforms([]solanaswapgo.LaunchLabPlatform{{Name: "fixture", FeeRecipient: feeVault}})
+	event, swaps, swapInfo := launchLabTradeEvent(t, ctx)
+	if event.Platform != "fixture" || swaps[0].Platform != "fixture" || swapInfo.Platform != "fixture" {
+		t.Errorf("应按平台费用接收者识别: %q %q %q", event.Platform, swaps[0].Platform, swapInfo.Platform)
+	}
+	solanaswapgo.SetLaunchLabPlatforms([]solanaswapgo.LaunchLabPlatform{{Name: "fixture", FeeRecipient: user}})
+	if event, _, _ := launchLabTradeEvent(t, ctx); event.Platform != "" {
+		t.Errorf("creator_fee_vault 位置的账户不是平台费用接收者: %q", event.Platform)
+	}
+
+	// platform_config 优先于平台费用接收者
+	solanaswapgo.SetLaunchLabPlatforms([]solanaswapgo.LaunchLabPlatform{
+		{Name: "fixture", FeeRecipient: feeVault},
+		{Name: "custom", PlatformConfig: bonkPlatformConfig},
+	})
+	if event, _, _ := launchLabTradeEvent(t, ctx); event.Platform != "custom" {
+		t.Errorf("应按 platform_config 识别: %q", event.Platform)
+	}
+
+	// 没有匹配的平台时为空，传入 nil 恢复内置列表
+	solanaswapgo.SetLaunchLabPlatforms([]solanaswapgo.LaunchLabPlatform{})
+	if event, _, _ := launchLabTradeEvent(t, ctx); event.Platform != "" {
+		t.Errorf("没有匹配的平台时应为空: %q", event.Platform)
+	}
+	solanaswapgo.SetLaunchLabPlatforms(nil)
+	if event, _, _ := launchLabTradeEvent(t, ctx); event.Platform != "bonk" {
+		t.Errorf("应恢复内置列表: %q", event.Platform)
+	}
+}
+
+func TestLaunchLabPlatformConfigFile(t *testing.T) {
+	platforms, err := solanaswapgo.ReadLaunchLabPlatforms(strings.NewReader(
+		`[{"name": "bonk", "platformConfig": "FfYek5vEz23cMkWsdJwG2oa6EphsvXSHrGpdALN4g6W1"},
+		  {"name": "fixture", "feeRecipient": "So11111111111111111111111111111111111111112"}]`))
+	if err != nil {
+		t.Fatalf("error reading platforms: %s", err)
+	}
+	if len(platforms) != 2 || platforms[0].PlatformConfig != bonkPlatformConfig || !platforms[0].FeeRecipient.IsZero() ||
+		platforms[1].FeeRecipient != solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID {
+		t.Errorf("平台列表不正确: %+v", platforms)
+	}
+
+	for _, invalid := range []string{
+		`[{"platformConfig": "FfYek5vEz23cMkWsdJwG2oa6EphsvXSHrGpdALN4g6W1"}]`,
+		`[{"name": "empty"}]`,
+		`[{"name": "bad", "platformConfig": "not-a-key"}]`,
+	} {
+		if _, err := solanaswapgo.ReadLaunchLabPlatforms(strings.NewReader(invalid)); err == nil {
+			t.Errorf("应拒绝无效的平台列表: %s", invalid)
+		}
+	}
+}
+
+func TestLaunchLabPlatformLaunch(t *testing.T) {
+	ctx := loadCorpusTx(t, "raydium_launchlab", launchLabCreateSignature)
+	launches := newCorpusParser(t, ctx).ParseTokenLaunches()
+	if len(launches) != 1 {
+		t.Fatalf("应解析出 1 个代币创建，实际 %d", len(launches))
+	}
+	launch := launches[0]
+	initialize := ctx.tx.Message.Instructions[2]
+	keys := ctx.tx.Message.AccountKeys
+	if launch.Type != solanaswapgo.RAYDIUM_LAUNCHLAB || launch.Platform != "bonk" || launch.Signature != ctx.tx.Signatures[0] {
+		t.Errorf("类型、平台或签名错误: %+v", launch)
+	}
+	if launch.Name != "Fixture Bonk" || launch.Symbol != "FBONK" || launch.URI != "https://ipfs.io/ipfs/fixture-bonk" || launch.Decimals != 6 {
+		t.Errorf("代币元数据错误: %+v", launch)
+	}
+	if launch.Mint != keys[initialize.Accounts[6]] || launch.BondingCurve != keys[initialize.Accounts[5]] ||
+		launch.User != keys[0] || launch.Creator != keys[0] || launch.InitialBuy != nil {
+		t.Errorf("账户错误: %+v", launch)
+	}
+
+	// 同一笔交易中的首次买入包含创作者费用
+	event, _, swapInfo := launchLabTradeEvent(t, ctx)
+	if event.BaseMint != launch.Mint || event.PoolState != launch.BondingCurve || event.Platform != launch.Platform {
+		t.Errorf("首次买入应属于新建的池子: %+v", event)
+	}
+	if event.RealQuoteBefore != 0 || swapInfo.Fees == nil || swapInfo.Fees.CreatorFee == 0 || swapInfo.Fees.PlatformFee == 0 {
+		t.Errorf("首次买入的费用不正确: %+v", swapInfo.Fees)
+	}
+}
+
+func TestLaunchLabPlatformOutputs(t *testing.T) {
+	ctx := loadCorpusTx(t, "raydium_launchlab", launchLabBuySignature)
+
+	// 指令参数无法解码时按转账解析，仍然标记平台
+	trade := &ctx.tx.Message.Instructions[2]
+	trade.Data = trade.Data[:8]
+	swaps, swapInfo := parseSwapInfo(t, ctx)
+	if len(swaps) < 2 {
+		t.Fatalf("应退回到按转账解析: %+v", swaps)
+	}
+	for _, swap := range swaps {
+		if _, ok := swap.Data.(*solanaswapgo.TransferCheck); !ok || swap.Platform != "bonk" {
+			t.Errorf("转账应标记平台: %T %q", swap.Data, swap.Platform)
+		}
+	}
+	if swapInfo.Platform != "bonk" {
+		t.Errorf("SwapInfo 应标记平台: %q", swapInfo.Platform)
+	}
+
+	// 交换腿、sink 的行和 protobuf 都带有平台
+	swap := solanaswapgo.BlockSwap{Signature: ctx.tx.Signatures[0], Swaps: swaps, SwapInfo: swapInfo}
+	for _, leg := range solanaswapgo.NewLegs(swap) {
+		if leg.Platform != "bonk" {
+			t.Errorf("交换腿应标记平台: %+v", leg)
+		}
+	}
+	if row := sink.NewRow(swap); row.Platform != "bonk" {
+		t.Errorf("sink 的行应标记平台: %+v", row)
+	}
+	result := swappb.FromBlockSwap(swap)
+	if result.GetSwapInfo().GetPlatform() != "bonk" || result.GetLegs()[0].GetPlatform() != "bonk" {
+		t.Errorf("protobuf 应标记平台: %v", result)
+	}
+
+	// 其他协议的交换没有平台
+	_, swapInfo = parseSwapInfo(t, loadCorpusTx(t, "raydium", raydiumCPMMSignature))
+	if swapInfo.Platform != "" {
+		t.Errorf("非 LaunchLab 交换不应有平台: %q", swapInfo.Platform)
+	}
+}
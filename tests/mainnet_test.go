@@ -0,0 +1,300 @@
+package tests
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/fixture"
+	"github.com/zzispp/solana-dex-parse/rpctest"
+)
+
+// mainnetDir 存放录制的主网交易，布局与合成语料相同：<protocol>/<signature>.json
+var mainnetDir = filepath.Join("testdata", "mainnet")
+
+// mainnetRPCEnv 指定访问主网的 RPC 地址，使用 -record 时从该节点录制缺少的主网交易
+const mainnetRPCEnv = "SOLANA_RPC_URL"
+
+var record = flag.Bool("record", false, "record mainnet transactions missing from testdata/mainnet from SOLANA_RPC_URL")
+
+// getMainnetTransaction 返回一笔主网交易。录制到 testdata/mainnet 的交易经 rpctest 走完整的 getTransaction 流程；
+// 没有录制时从 SOLANA_RPC_URL 获取，使用 -record 时同时录制。未录制且未设置 SOLANA_RPC_URL 或使用 -short 时跳过
+func getMainnetTransaction(t *testing.T, protocol string, signature string) *rpc.GetTransactionResult {
+	t.Helper()
+
+	txSig := solana.MustSignatureFromBase58(signature)
+	path := fixture.Path(mainnetDir, protocol, signature)
+	var client *rpc.Client
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		endpoint := os.Getenv(mainnetRPCEnv)
+		if endpoint == "" || testing.Short() {
+			t.Skipf("%s 未录制到 %s，设置 %s 以访问主网，见 testdata/README.md", signature, mainnetDir, mainnetRPCEnv)
+		}
+		client = rpc.New(endpoint)
+		if *record {
+			if _, err := fixture.Record(context.TODO(), client, mainnetDir, protocol, txSig, rpc.CommitmentConfirmed); err != nil {
+				t.Fatalf("error recording %s: %s", signature, err)
+			}
+			client = nil
+		}
+	}
+	if client == nil {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("error reading %s: %s", path, err)
+		}
+		server := rpctest.NewServer()
+		t.Cleanup(server.Close)
+		if err := server.AddTransaction(raw); err != nil {
+			t.Fatalf("error loading %s: %s", signature, err)
+		}
+		client = server.Client()
+	}
+
+	var maxTxVersion uint64 = 0
+	tx, err := client.GetTransaction(
+		context.TODO(),
+		txSig,
+		&rpc.GetTransactionOpts{
+			Commitment:                     rpc.CommitmentConfirmed,
+			MaxSupportedTransactionVersion: &maxTxVersion,
+		},
+	)
+	if err != nil {
+		t.Fatalf("error getting tx: %s", err)
+	}
+	return tx
+}
+
+// mainnetLayouts 返回 testdata/mainnet/<protocol> 中录制的交易覆盖的解码器布局，没有录制时跳过测试
+func mainnetLayouts(t *testing.T, protocol string) map[string]bool {
+	t.Helper()
+
+	covered := make(map[string]bool)
+	for _, ctx := range loadMainnetCorpus(t) {
+		if ctx.protocol != protocol {
+			continue
+		}
+		swaps, _ := parseSwapInfo(t, ctx)
+		for _, swap := range swaps {
+			for _, layout := range mainnetCoverage(swap) {
+				covered[layout] = true
+			}
+		}
+	}
+	return covered
+}
+
+// skipMissingMainnetLayouts 在 layouts 中有布局没有录制的主网交易时跳过测试并列出这些布局。
+// 较早的布局在主网上可能已经不再出现，只录制实际存在的交易
+func skipMissingMainnetLayouts(t *testing.T, protocol string, layouts ...string) {
+	t.Helper()
+
+	covered := mainnetLayouts(t, protocol)
+	var missing []string
+	for _, layout := range layouts {
+		if !covered[layout] {
+			missing = append(missing, layout)
+		}
+	}
+	if len(missing) > 0 {
+		t.Skipf("%s: 没有 %s 的主网交易，录制方法见 testdata/README.md", protocol, strings.Join(missing, "、"))
+	}
+}
+
+// mainnetCoverage 返回交换腿覆盖的解码器布局，如 "PumpfunTradeEvent v3"，每个协议要求的布局由各自的 MainnetLayouts 测试检查
+func mainnetCoverage(swap solanaswapgo.SwapData) []string {
+	direction := func(buy bool) string {
+		if buy {
+			return "buy"
+		}
+		return "sell"
+	}
+	switch event := swap.Data.(type) {
+	case *solanaswapgo.PumpfunTradeEvent:
+		return []string{fmt.Sprintf("PumpfunTradeEvent v%d", event.Version), "PumpfunTradeEvent " + direction(event.IsBuy)}
+	case *solanaswapgo.PumpfunAMMBuyEvent:
+		return []string{fmt.Sprintf("PumpfunAMMBuyEvent v%d", event.Version)}
+	case *solanaswapgo.PumpfunAMMSellEvent:
+		return []string{fmt.Sprintf("PumpfunAMMSellEvent v%d", event.Version)}
+	case *solanaswapgo.RaydiumV4SwapEvent:
+		if event.BaseIn {
+			return []string{"RaydiumV4SwapEvent base_in"}
+		}
+		return []string{"RaydiumV4SwapEvent base_out"}
+	case *solanaswapgo.RaydiumCLMMSwapEvent:
+		return []string{"RaydiumCLMMSwapEvent"}
+	case *solanaswapgo.RaydiumCPMMSwapEvent:
+		return []string{fmt.Sprintf("RaydiumCPMMSwapEvent v%d", event.Version)}
+	case *solanaswapgo.RaydiumLaunchLabTradeEvent:
+		exact := "exact_out"
+		if event.ExactIn {
+			exact = "exact_in"
+		}
+		return []string{fmt.Sprintf("RaydiumLaunchLabTradeEvent v%d", event.Version), "RaydiumLaunchLabTradeEvent " + direction(event.IsBuy()) + " " + exact}
+	}
+	return nil
+}
+
+// mainnetCorpus 读取 testdata/mainnet/<protocol>/<signature>.json 下录制的主网交易
+func mainnetCorpus(tb testing.TB) []corpusTx {
+	tb.Helper()
+
+	paths, err := filepath.Glob(filepath.Join(mainnetDir, "*", "*.json"))
+	if err != nil {
+		tb.Fatalf("error listing mainnet corpus: %s", err)
+	}
+	sort.Strings(paths)
+
+	var corpus []corpusTx
+	for _, path := range paths {
+		if !strings.HasSuffix(path, fixture.GoldenSuffix) {
+			corpus = append(corpus, loadCorpusFile(tb, path))
+		}
+	}
+	return corpus
+}
+
+// loadMainnetCorpus 与 mainnetCorpus 相同，没有录制时跳过测试
+func loadMainnetCorpus(tb testing.TB) []corpusTx {
+	tb.Helper()
+
+	corpus := mainnetCorpus(tb)
+	if len(corpus) == 0 {
+		tb.Skipf("%s 中没有录制的主网交易，录制方法见 testdata/README.md", mainnetDir)
+	}
+	return corpus
+}
+
+func TestMainnetGolden(t *testing.T) {
+	for _, ctx := range loadMainnetCorpus(t) {
+		t.Run(ctx.protocol+"/"+ctx.signature, func(t *testing.T) {
+			checkGolden(t, mainnetDir, ctx)
+		})
+	}
+}
+
+// TestMainnetDecoders 用链上的代币余额变化和曲线公式检查录制的主网交易，
+// 而不是与生成语料的代码比较
+func TestMainnetDecoders(t *testing.T) {
+	for _, ctx := range loadMainnetCorpus(t) {
+		swaps, _ := parseSwapInfo(t, ctx)
+
+		var events []solanaswapgo.Leg
+		for i, leg := range solanaswapgo.NewLegs(solanaswapgo.BlockSwap{Swaps: swaps}) {
+			if trade, ok := swaps[i].Data.(*solanaswapgo.PumpfunTradeEvent); ok {
+				checkPumpCurveTrade(t, ctx.signature, trade)
+			}
+			if leg.Trader != "" {
+				events = append(events, leg)
+			}
+		}
+
+		// 只有一个事件时，交易者的代币余额变化应等于事件中的输入输出（SOL 包含手续费和租金，不比较）
+		if len(events) != 1 {
+			continue
+		}
+		leg := events[0]
+		trader := solana.MustPublicKeyFromBase58(leg.Trader)
+		for _, side := range []struct {
+			mint   string
+			change int64
+		}{
+			{leg.InputMint, -int64(leg.InputAmount)},
+			{leg.OutputMint, int64(leg.OutputAmount)},
+		} {
+			if side.mint == "" || side.mint == solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID.String() {
+				continue
+			}
+			if got := tokenBalanceChange(t, ctx.result.Meta, trader, solana.MustPublicKeyFromBase58(side.mint)); got != side.change {
+				t.Errorf("%s: %s 的 %s 余额变化应为 %d，实际 %d", ctx.signature, leg.DataType, side.mint, side.change, got)
+			}
+		}
+	}
+}
+
+// parseMainnetSwap 解析一笔主网交易并检查 SwapInfo 的基本字段：amm 应出现在 AMMs 中（为空时不检查），
+// 输入输出代币不同且数量非零，时间戳取自区块时间，费用以输入或输出代币计
+func parseMainnetSwap(t *testing.T, tx *rpc.GetTransactionResult, amm solanaswapgo.SwapType) ([]solanaswapgo.SwapData, *solanaswapgo.SwapInfo) {
+	t.Helper()
+
+	parser, err := solanaswapgo.NewTransactionParser(tx)
+	if err != nil {
+		t.Fatalf("error creating parser: %s", err)
+	}
+	swaps, swapInfo := parseSwaps(t, parser)
+
+	if amm != "" {
+		found := false
+		for _, name := range swapInfo.AMMs {
+			found = found || name == string(amm)
+		}
+		if !found {
+			t.Errorf("AMMs 应包含 %s，实际 %v", amm, swapInfo.AMMs)
+		}
+	}
+	if swapInfo.TokenInMint.IsZero() || swapInfo.TokenOutMint.IsZero() || swapInfo.TokenInMint == swapInfo.TokenOutMint {
+		t.Errorf("输入输出代币不正确: %s -> %s", swapInfo.TokenInMint, swapInfo.TokenOutMint)
+	}
+	if swapInfo.TokenInAmount == 0 || swapInfo.TokenOutAmount == 0 {
+		t.Errorf("输入输出数量不应为 0: %d -> %d", swapInfo.TokenInAmount, swapInfo.TokenOutAmount)
+	}
+	if tx.BlockTime != nil && !swapInfo.Timestamp.Equal(tx.BlockTime.Time()) {
+		t.Errorf("时间戳应为区块时间 %s，实际 %s", tx.BlockTime.Time(), swapInfo.Timestamp)
+	}
+	if fees := swapInfo.Fees; fees != nil && fees.Mint != swapInfo.TokenInMint && fees.Mint != swapInfo.TokenOutMint {
+		t.Errorf("费用代币 %s 应为输入或输出代币", fees.Mint)
+	}
+	return swaps, swapInfo
+}
+
+// checkMainnetSwap 在 parseMainnetSwap 的基础上，检查非 SOL 的输入输出数量等于第一个签名者的代币余额变化
+// （SOL 余额包含交易费和租金，不比较）
+func checkMainnetSwap(t *testing.T, tx *rpc.GetTransactionResult, amm solanaswapgo.SwapType) ([]solanaswapgo.SwapData, *solanaswapgo.SwapInfo) {
+	t.Helper()
+
+	swaps, swapInfo := parseMainnetSwap(t, tx, amm)
+	if len(swapInfo.Signers) == 0 {
+		t.Fatal("应解析出签名者")
+	}
+	trader := swapInfo.Signers[0]
+	for _, side := range []struct {
+		mint   solana.PublicKey
+		change int64
+	}{
+		{swapInfo.TokenInMint, -int64(swapInfo.TokenInAmount)},
+		{swapInfo.TokenOutMint, int64(swapInfo.TokenOutAmount)},
+	} {
+		if side.mint == solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID {
+			continue
+		}
+		if got := tokenBalanceChange(t, tx.Meta, trader, side.mint); got != side.change {
+			t.Errorf("%s 的 %s 余额变化应为 %d，实际 %d", trader, side.mint, side.change, got)
+		}
+	}
+	return swaps, swapInfo
+}
+
+// checkMainnetEvent 检查交易只有一个交换腿，由 dataType 类型的事件解析，且输入输出与 SwapInfo 一致
+func checkMainnetEvent(t *testing.T, swaps []solanaswapgo.SwapData, swapInfo *solanaswapgo.SwapInfo, dataType string) {
+	t.Helper()
+
+	legs := solanaswapgo.NewLegs(solanaswapgo.BlockSwap{Swaps: swaps, SwapInfo: swapInfo})
+	if len(legs) != 1 || legs[0].DataType != dataType {
+		t.Fatalf("应只有一个 %s 交换腿: %+v", dataType, legs)
+	}
+	leg := legs[0]
+	if leg.InputMint != swapInfo.TokenInMint.String() || leg.InputAmount != swapInfo.TokenInAmount ||
+		leg.OutputMint != swapInfo.TokenOutMint.String() || leg.OutputAmount != swapInfo.TokenOutAmount {
+		t.Errorf("SwapInfo 应与事件一致:\n event: %s %d -> %s %d\n  info: %s %d -> %s %d", leg.InputMint, leg.InputAmount, leg.OutputMint, leg.OutputAmount,
+			swapInfo.TokenInMint, swapInfo.TokenInAmount, swapInfo.TokenOutMint, swapInfo.TokenOutAmount)
+	}
+}
@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/metrics"
+)
+
+// metricValue 返回计数器指标在给定标签下的值，不存在时为 0
+func metricValue(t *testing.T, registry *prometheus.Registry, name string, labels map[string]string) float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("error gathering metrics: %s", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	next:
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if labels[label.GetName()] != label.GetValue() {
+					continue next
+				}
+			}
+			return metric.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestParserMetrics(t *testing.T) {
+	collector := metrics.NewCollector()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	solanaswapgo.SetObserver(collector)
+	defer solanaswapgo.SetObserver(nil)
+
+	corpus := loadCorpus(t)
+	want := make(map[[2]string]int)
+	for _, ctx := range corpus {
+		parser := newCorpusParser(t, ctx)
+		swaps, err := parser.ParseTransaction()
+		if err != nil {
+			t.Fatalf("%s: error parsing transaction: %s", ctx.signature, err)
+		}
+		for _, swap := range swaps {
+			want[[2]string{string(swap.Type), string(swap.Source())}]++
+		}
+	}
+
+	if got := metricValue(t, registry, "dexparse_parser_transactions_total", nil); got != float64(len(corpus)) {
+		t.Errorf("应统计 %d 笔交易，实际 %v", len(corpus), got)
+	}
+	for labels, count := range want {
+		if got := metricValue(t, registry, "dexparse_parser_swaps_total", map[string]string{"protocol": labels[0], "source": labels[1]}); got != float64(count) {
+			t.Errorf("%s/%s 的交换数应为 %d，实际 %v", labels[0], labels[1], count, got)
+		}
+	}
+	if want[[2]string{string(solanaswapgo.PUMP_FUN), string(solanaswapgo.SwapSourceEvent)}] == 0 || want[[2]string{string(solanaswapgo.ORCA), string(solanaswapgo.SwapSourceTransfer)}] == 0 {
+		t.Errorf("语料应覆盖事件和转账两种解析路径: %v", want)
+	}
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(""), "dexparse_parser_decode_errors_total"); err != nil {
+		t.Errorf("语料不应产生解码错误: %s", err)
+	}
+	if count, err := testutil.GatherAndCount(registry, "dexparse_parser_parse_duration_seconds"); err != nil || count != 1 {
+		t.Errorf("应有解析耗时直方图: %d %v", count, err)
+	}
+
+	// 截断 Pump.fun 的 TradeEvent，应记为 Pump.fun 程序的解码错误
+	var pumpfun corpusTx
+	for _, ctx := range corpus {
+		if ctx.protocol == "pumpfun" {
+			pumpfun = ctx
+			break
+		}
+	}
+	truncated := false
+	for i := range pumpfun.result.Meta.InnerInstructions {
+		instructions := pumpfun.result.Meta.InnerInstructions[i].Instructions
+		for j := range instructions {
+			if bytes.HasPrefix(instructions[j].Data, solanaswapgo.PumpfunTradeEventDiscriminator[:]) {
+				instructions[j].Data = instructions[j].Data[:24]
+				truncated = true
+			}
+		}
+	}
+	if !truncated {
+		t.Fatal("Pump.fun 语料中应包含 TradeEvent")
+	}
+	parser, err := solanaswapgo.NewTransactionParserFromTransactionResult(pumpfun.result, pumpfun.tx, pumpfun.result.Meta)
+	if err != nil {
+		t.Fatalf("error creating parser: %s", err)
+	}
+	parser.Log = solanaswapgo.DiscardLogger
+	if _, err := parser.ParseTransaction(); err != nil {
+		t.Fatalf("error parsing transaction: %s", err)
+	}
+	if got := metricValue(t, registry, "dexparse_parser_decode_errors_total", map[string]string{"program": solanaswapgo.PUMP_FUN_PROGRAM_ID.String()}); got != 1 {
+		t.Errorf("应统计 1 次 Pump.fun 解码错误，实际 %v", got)
+	}
+
+	// 将 ComputeBudget 替换为未知程序
+	unknown := solana.MustPublicKeyFromBase58("Dex1111111111111111111111111111111111111111")
+	var target *corpusTx
+	for i := range corpus {
+		for j, key := range corpus[i].tx.Message.AccountKeys {
+			if key.Equals(solana.ComputeBudget) {
+				corpus[i].tx.Message.AccountKeys[j] = unknown
+				target = &corpus[i]
+			}
+		}
+		if target != nil {
+			break
+		}
+	}
+	if target == nil {
+		t.Fatal("语料中应有调用 ComputeBudget 的交易")
+	}
+	parser, err = solanaswapgo.NewTransactionParserFromTransactionResult(target.result, target.tx, target.result.Meta)
+	if err != nil {
+		t.Fatalf("error creating parser: %s", err)
+	}
+	if _, err := parser.ParseTransaction(); err != nil {
+		t.Fatalf("error parsing transaction: %s", err)
+	}
+	if got := metricValue(t, registry, "dexparse_parser_unknown_programs_total", map[string]string{"program": unknown.String()}); got != 1 {
+		t.Errorf("应统计 1 次未知程序 %s，实际 %v", unknown, got)
+	}
+}
+
+func TestParserMetricsInstructionDecodeErrors(t *testing.T) {
+	collector := metrics.NewCollector()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	solanaswapgo.SetObserver(collector)
+	defer solanaswapgo.SetObserver(nil)
+
+	// 将 Meteora 语料的程序替换为按指令参数解析的程序，swap 判别器后的参数被截断时应记为该程序的解码错误
+	for _, c := range []struct {
+		program       solana.PublicKey
+		discriminator [8]byte
+	}{
+		{solanaswapgo.METEORA_DAMM_V2_PROGRAM_ID, solanaswapgo.MeteoraDAMMv2SwapDiscriminator},
+		{solanaswapgo.METEORA_DBC_PROGRAM_ID, solanaswapgo.MeteoraDBCSwapDiscriminator},
+		{solanaswapgo.BOOPFUN_PROGRAM_ID, solanaswapgo.BoopFunBuyTokenDiscriminator},
+	} {
+		var meteora *corpusTx
+		corpus := loadCorpus(t)
+		for i := range corpus {
+			if corpus[i].protocol == "meteora" {
+				meteora = &corpus[i]
+				break
+			}
+		}
+		if meteora == nil {
+			t.Fatal("语料中应有 Meteora 交易")
+		}
+
+		replaced := false
+		for j, key := range meteora.tx.Message.AccountKeys {
+			if !key.Equals(solanaswapgo.METEORA_PROGRAM_ID) {
+				continue
+			}
+			meteora.tx.Message.AccountKeys[j] = c.program
+			for k := range meteora.tx.Message.Instructions {
+				if int(meteora.tx.Message.Instructions[k].ProgramIDIndex) == j {
+					meteora.tx.Message.Instructions[k].Data = append(c.discriminator[:], 1, 2, 3, 4)
+					replaced = true
+				}
+			}
+		}
+		if !replaced {
+			t.Fatal("Meteora 语料的外层指令应调用 Meteora 程序")
+		}
+
+		parser, err := solanaswapgo.NewTransactionParserFromTransactionResult(meteora.result, meteora.tx, meteora.result.Meta)
+		if err != nil {
+			t.Fatalf("error creating parser: %s", err)
+		}
+		parser.Log = solanaswapgo.DiscardLogger
+		if _, err := parser.ParseTransaction(); err != nil {
+			t.Fatalf("error parsing transaction: %s", err)
+		}
+		if got := metricValue(t, registry, "dexparse_parser_decode_errors_total", map[string]string{"program": c.program.String()}); got != 1 {
+			t.Errorf("应统计 1 次 %s 解码错误，实际 %v", c.program, got)
+		}
+	}
+
+}
+
+func TestParserMetricsCardinality(t *testing.T) {
+	collector := metrics.NewCollector()
+	collector.MaxUnknownPrograms = 2
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	for i := 0; i < 4; i++ {
+		collector.ObserveParse(solanaswapgo.ParseStats{UnknownPrograms: []solana.PublicKey{solana.NewWallet().PublicKey()}})
+	}
+	if got := metricValue(t, registry, "dexparse_parser_unknown_programs_total", map[string]string{"program": metrics.OtherProgram}); got != 2 {
+		t.Errorf("超出上限的未知程序应归入 %s，实际 %v", metrics.OtherProgram, got)
+	}
+	if count := testutil.CollectAndCount(collector, "dexparse_parser_unknown_programs_total"); count != 3 {
+		t.Errorf("未知程序标签应有 3 个取值，实际 %d", count)
+	}
+}
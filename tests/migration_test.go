@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+const (
+	pumpfunCompleteSignature = "icJVFxehuopRVdpbSfXAoiuLQocr14RmRw3rZtHakV8RH2VdAbD91R1XcLMVy6P1g7EaJEBY4aRgn6Jo4Kd8zHN"
+	pumpfunMigrateSignature  = "4bnWRCqfEj61vtSSucwjrXxP8rQwrthEpRWTfMC94uo6kEHt3kUmrPBNSPZbYGva3xDpdAS81qHv4DS3y8N7SpfX"
+)
+
+func TestPumpfunMigration(t *testing.T) {
+	for _, ctx := range loadCorpus(t) {
+		if ctx.signature == pumpfunCompleteSignature {
+			continue
+		}
+		if migrations := newCorpusParser(t, ctx).ParseMigrations(); len(migrations) != 0 {
+			t.Errorf("%s 不应包含毕业或迁移: %+v", ctx.signature, migrations)
+		}
+	}
+
+	// 买完联合曲线的交易只有 CompleteEvent
+	complete := newCorpusParser(t, loadCorpusTx(t, "pumpfun", pumpfunCompleteSignature)).ParseMigrations()
+	if len(complete) != 1 {
+		t.Fatalf("应解析出 1 条毕业记录，实际 %d", len(complete))
+	}
+	if !complete[0].Completed || complete[0].Migrated || !complete[0].Pool.IsZero() || complete[0].Timestamp.IsZero() {
+		t.Errorf("毕业记录不正确: %+v", complete[0])
+	}
+
+	// 之后的迁移交易把曲线上的资产转入 PumpSwap 池
+	migrate := loadCorpusTx(t, filepath.Join("events", "pumpfun"), pumpfunMigrateSignature)
+	migrations := newCorpusParser(t, migrate).ParseMigrations()
+	if len(migrations) != 1 {
+		t.Fatalf("应解析出 1 条迁移记录，实际 %d", len(migrations))
+	}
+	migration := migrations[0]
+	if migration.Completed || !migration.Migrated || migration.Signature != migrate.tx.Signatures[0] {
+		t.Errorf("迁移记录的状态不正确: %+v", migration)
+	}
+	if migration.Mint != complete[0].Mint || migration.BondingCurve != complete[0].BondingCurve {
+		t.Errorf("迁移记录应与毕业记录的代币和联合曲线一致: %+v %+v", migration, complete[0])
+	}
+	if migration.Pool.IsZero() || migration.LpMint.IsZero() || migration.Pool != migrate.tx.Message.AccountKeys[migrate.tx.Message.Instructions[1].Accounts[9]] {
+		t.Errorf("池子地址不正确: %+v", migration)
+	}
+	if migration.TokenAmount != 206_900_000_000_000 || migration.SolAmount != 84_990_359_057 || migration.MigrationFee != 15_000_000 {
+		t.Errorf("迁移数量不正确: %+v", migration)
+	}
+
+	// 没有 CompletePumpAmmMigrationEvent 的旧版迁移，池子和数量取自 PumpSwap 的 CreatePoolEvent
+	for i := range migrate.result.Meta.InnerInstructions {
+		instructions := migrate.result.Meta.InnerInstructions[i].Instructions
+		kept := instructions[:0]
+		for _, instruction := range instructions {
+			if !bytes.HasPrefix(instruction.Data, solanaswapgo.PumpfunCompleteMigrationEventDiscriminator[:]) {
+				kept = append(kept, instruction)
+			}
+		}
+		migrate.result.Meta.InnerInstructions[i].Instructions = kept
+	}
+	legacy := newCorpusParser(t, migrate).ParseMigrations()
+	if len(legacy) != 1 {
+		t.Fatalf("应解析出 1 条迁移记录，实际 %d", len(legacy))
+	}
+	if legacy[0].Pool != migration.Pool || legacy[0].TokenAmount != migration.TokenAmount || legacy[0].SolAmount != migration.SolAmount || legacy[0].MigrationFee != 0 {
+		t.Errorf("旧版迁移应从 CreatePoolEvent 补全: %+v", legacy[0])
+	}
+}
+
+func TestMigrationTracker(t *testing.T) {
+	complete := newCorpusParser(t, loadCorpusTx(t, "pumpfun", pumpfunCompleteSignature)).ParseMigrations()
+	migrate := newCorpusParser(t, loadCorpusTx(t, filepath.Join("events", "pumpfun"), pumpfunMigrateSignature)).ParseMigrations()
+	if len(complete) != 1 || len(migrate) != 1 {
+		t.Fatalf("应各解析出 1 条记录，实际 %d %d", len(complete), len(migrate))
+	}
+
+	// 毕业和迁移在两笔交易中，先后加入时配对
+	tracker := solanaswapgo.NewMigrationTracker()
+	if _, ok := tracker.Add(complete[0]); ok {
+		t.Fatal("只有毕业记录时不应配对")
+	}
+	if tracker.Pending() != 1 {
+		t.Errorf("应有 1 条等待配对的记录，实际 %d", tracker.Pending())
+	}
+	graduation, ok := tracker.Add(migrate[0])
+	if !ok {
+		t.Fatal("迁移记录应与之前的毕业记录配对")
+	}
+	if graduation.Mint != complete[0].Mint || graduation.Complete.Signature != complete[0].Signature || graduation.Migrate.Signature != migrate[0].Signature {
+		t.Errorf("配对结果不正确: %+v", graduation)
+	}
+	if graduation.Migrate.Pool.IsZero() || tracker.Pending() != 0 {
+		t.Errorf("配对后应带有池子地址且不再保留记录: %+v pending=%d", graduation, tracker.Pending())
+	}
+
+	// 倒序加入（如从新到旧回填）也能配对
+	tracker = solanaswapgo.NewMigrationTracker()
+	if _, ok := tracker.Add(migrate[0]); ok {
+		t.Fatal("只有迁移记录时不应配对")
+	}
+	graduation, ok = tracker.Add(complete[0])
+	if !ok || graduation.Complete.Signature != complete[0].Signature || graduation.Migrate.Signature != migrate[0].Signature {
+		t.Errorf("倒序加入时配对结果不正确: %+v %v", graduation, ok)
+	}
+
+	// 同一笔交易中同时毕业和迁移时直接返回
+	both := complete[0]
+	both.Migrated = true
+	graduation, ok = solanaswapgo.NewMigrationTracker().Add(both)
+	if !ok || graduation.Complete.Signature != graduation.Migrate.Signature {
+		t.Errorf("同一笔交易中的毕业和迁移应直接配对: %+v %v", graduation, ok)
+	}
+}
+
+func TestMigrationTrackerEviction(t *testing.T) {
+	complete := newCorpusParser(t, loadCorpusTx(t, "pumpfun", pumpfunCompleteSignature)).ParseMigrations()[0]
+	migrate := newCorpusParser(t, loadCorpusTx(t, filepath.Join("events", "pumpfun"), pumpfunMigrateSignature)).ParseMigrations()[0]
+	// pending 返回第 i 个一直没有迁移的代币的毕业记录
+	pending := func(i int) solanaswapgo.Migration {
+		m := complete
+		m.Mint = solana.PublicKeyFromBytes(append(make([]byte, 31), byte(i+1)))
+		m.Timestamp = complete.Timestamp.Add(time.Duration(i) * time.Hour)
+		return m
+	}
+
+	// 超出 MaxPending 时淘汰最早加入的记录
+	tracker := solanaswapgo.NewMigrationTracker()
+	if tracker.MaxPending != solanaswapgo.DefaultMigrationTrackerMaxPending {
+		t.Errorf("默认 MaxPending 不正确: %d", tracker.MaxPending)
+	}
+	tracker.MaxPending = 2
+	tracker.Add(complete)
+	tracker.Add(pending(1))
+	tracker.Add(pending(2))
+	if tracker.Pending() != 2 {
+		t.Errorf("应只保留 2 条记录，实际 %d", tracker.Pending())
+	}
+	if _, ok := tracker.Add(migrate); ok {
+		t.Error("最早的毕业记录已被淘汰，不应配对")
+	}
+
+	// Evict 淘汰时间戳早于给定时间的记录，其余记录仍可配对
+	tracker = solanaswapgo.NewMigrationTracker()
+	for i := 1; i <= 3; i++ {
+		tracker.Add(pending(i))
+	}
+	recent := migrate
+	recent.Timestamp = pending(4).Timestamp
+	tracker.Add(recent)
+	if evicted := tracker.Evict(pending(3).Timestamp); evicted != 2 || tracker.Pending() != 2 {
+		t.Errorf("应淘汰 2 条记录，实际淘汰 %d，剩余 %d", evicted, tracker.Pending())
+	}
+	if _, ok := tracker.Add(complete); !ok {
+		t.Error("未被淘汰的迁移记录应仍可配对")
+	}
+	if evicted := tracker.Evict(time.Time{}); evicted != 0 || tracker.Pending() != 1 {
+		t.Errorf("零值时间不应淘汰记录: %d %d", evicted, tracker.Pending())
+	}
+}
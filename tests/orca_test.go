@@ -1,55 +1,12 @@
 package tests
 
 import (
-	"context"
-	"encoding/json"
-	"fmt"
-	"log"
 	"testing"
 
-	"github.com/gagliardetto/solana-go"
-	"github.com/gagliardetto/solana-go/rpc"
 	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
 )
 
 func TestOrcaTransaction(t *testing.T) {
-	rpcClient := rpc.New("https://mainnet.helius-rpc.com/?api-key=b69296cd-290c-43f7-bf49-9e9ded12b5e0")
-	txSig := solana.MustSignatureFromBase58("2kAW5GAhPZjM3NoSrhJVHdEpwjmq9neWtckWnjopCfsmCGB27e3v2ZyMM79FdsL4VWGEtYSFi1sF1Zhs7bqdoaVT")
-
-	var maxTxVersion uint64 = 0
-	tx, err := rpcClient.GetTransaction(
-		context.TODO(),
-		txSig,
-		&rpc.GetTransactionOpts{
-			Commitment:                     rpc.CommitmentConfirmed,
-			MaxSupportedTransactionVersion: &maxTxVersion,
-		},
-	)
-	if err != nil {
-		log.Fatalf("error getting tx: %s", err)
-	}
-
-	parser, err := solanaswapgo.NewTransactionParser(tx)
-	if err != nil {
-		log.Fatalf("error creating parser: %s", err)
-	}
-
-	transactionData, err := parser.ParseTransaction()
-	if err != nil {
-		log.Fatalf("error parsing transaction: %s", err)
-	}
-
-	swapInfo, err := parser.ProcessSwapData(transactionData)
-	if err != nil {
-		log.Fatalf("error processing swap data: %s", err)
-	}
-
-	fmt.Printf("=== Orca 交易解析结果 ===\n")
-	marshalledSwapData, _ := json.MarshalIndent(swapInfo, "", "  ")
-	fmt.Println(string(marshalledSwapData))
-
-	// 验证解析结果
-	if len(swapInfo.AMMs) == 0 {
-		t.Error("应该解析出 AMM 信息")
-	}
+	tx := getMainnetTransaction(t, "orca", "2kAW5GAhPZjM3NoSrhJVHdEpwjmq9neWtckWnjopCfsmCGB27e3v2ZyMM79FdsL4VWGEtYSFi1sF1Zhs7bqdoaVT")
+	checkMainnetSwap(t, tx, solanaswapgo.ORCA)
 }
@@ -1,139 +1,23 @@
 package tests
 
 import (
-	"context"
-	"encoding/json"
-	"fmt"
-	"log"
 	"testing"
 
-	"github.com/gagliardetto/solana-go"
-	"github.com/gagliardetto/solana-go/rpc"
 	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
 )
 
 func TestBananaGunTransaction(t *testing.T) {
-	rpcClient := rpc.New("https://mainnet.helius-rpc.com/?api-key=b69296cd-290c-43f7-bf49-9e9ded12b5e0")
-	txSig := solana.MustSignatureFromBase58("oXUd22GQ1d45a6XNzfdpHAX6NfFEfFa9o2Awn2oimY89Rms3PmXL1uBJx3CnTYjULJw6uim174b3PLBFkaAxKzK")
-
-	var maxTxVersion uint64 = 0
-	tx, err := rpcClient.GetTransaction(
-		context.TODO(),
-		txSig,
-		&rpc.GetTransactionOpts{
-			Commitment:                     rpc.CommitmentConfirmed,
-			MaxSupportedTransactionVersion: &maxTxVersion,
-		},
-	)
-	if err != nil {
-		log.Fatalf("error getting tx: %s", err)
-	}
-
-	parser, err := solanaswapgo.NewTransactionParser(tx)
-	if err != nil {
-		log.Fatalf("error creating parser: %s", err)
-	}
-
-	transactionData, err := parser.ParseTransaction()
-	if err != nil {
-		log.Fatalf("error parsing transaction: %s", err)
-	}
-
-	swapInfo, err := parser.ProcessSwapData(transactionData)
-	if err != nil {
-		log.Fatalf("error processing swap data: %s", err)
-	}
-
-	fmt.Printf("=== Banana Gun 交易解析结果 ===\n")
-	marshalledSwapData, _ := json.MarshalIndent(swapInfo, "", "  ")
-	fmt.Println(string(marshalledSwapData))
-
-	// 验证解析结果
-	if len(swapInfo.AMMs) == 0 {
-		t.Error("应该解析出 AMM 信息")
-	}
+	tx := getMainnetTransaction(t, "pumpfun", "oXUd22GQ1d45a6XNzfdpHAX6NfFEfFa9o2Awn2oimY89Rms3PmXL1uBJx3CnTYjULJw6uim174b3PLBFkaAxKzK")
+	checkMainnetSwap(t, tx, solanaswapgo.PUMP_FUN)
 }
 
 func TestMaestroTransaction(t *testing.T) {
-	rpcClient := rpc.New("https://mainnet.helius-rpc.com/?api-key=b69296cd-290c-43f7-bf49-9e9ded12b5e0")
-	txSig := solana.MustSignatureFromBase58("mWaH4FELcPj4zeY4Cgk5gxUirQDM7yE54VgMEVaqiUDQjStyzwNrxLx4FMEaKEHQoYsgCRhc1YdmBvhGDRVgRrq")
-
-	var maxTxVersion uint64 = 0
-	tx, err := rpcClient.GetTransaction(
-		context.TODO(),
-		txSig,
-		&rpc.GetTransactionOpts{
-			Commitment:                     rpc.CommitmentConfirmed,
-			MaxSupportedTransactionVersion: &maxTxVersion,
-		},
-	)
-	if err != nil {
-		log.Fatalf("error getting tx: %s", err)
-	}
-
-	parser, err := solanaswapgo.NewTransactionParser(tx)
-	if err != nil {
-		log.Fatalf("error creating parser: %s", err)
-	}
-
-	transactionData, err := parser.ParseTransaction()
-	if err != nil {
-		log.Fatalf("error parsing transaction: %s", err)
-	}
-
-	swapInfo, err := parser.ProcessSwapData(transactionData)
-	if err != nil {
-		log.Fatalf("error processing swap data: %s", err)
-	}
-
-	fmt.Printf("=== Maestro 交易解析结果 ===\n")
-	marshalledSwapData, _ := json.MarshalIndent(swapInfo, "", "  ")
-	fmt.Println(string(marshalledSwapData))
-
-	// 验证解析结果
-	if len(swapInfo.AMMs) == 0 {
-		t.Error("应该解析出 AMM 信息")
-	}
+	tx := getMainnetTransaction(t, "pumpfun", "mWaH4FELcPj4zeY4Cgk5gxUirQDM7yE54VgMEVaqiUDQjStyzwNrxLx4FMEaKEHQoYsgCRhc1YdmBvhGDRVgRrq")
+	checkMainnetSwap(t, tx, solanaswapgo.PUMP_FUN)
 }
 
 func TestOKXTransaction(t *testing.T) {
-	rpcClient := rpc.New("https://mainnet.helius-rpc.com/?api-key=b69296cd-290c-43f7-bf49-9e9ded12b5e0")
-	txSig := solana.MustSignatureFromBase58("5xaT2SXQUyvyLGsnyyoKMwsDoHrx1enCKofkdRMdNaL5MW26gjQBM3AWebwjTJ49uqEqnFu5d9nXJek6gUSGCqbL")
-
-	var maxTxVersion uint64 = 0
-	tx, err := rpcClient.GetTransaction(
-		context.TODO(),
-		txSig,
-		&rpc.GetTransactionOpts{
-			Commitment:                     rpc.CommitmentConfirmed,
-			MaxSupportedTransactionVersion: &maxTxVersion,
-		},
-	)
-	if err != nil {
-		log.Fatalf("error getting tx: %s", err)
-	}
-
-	parser, err := solanaswapgo.NewTransactionParser(tx)
-	if err != nil {
-		log.Fatalf("error creating parser: %s", err)
-	}
-
-	transactionData, err := parser.ParseTransaction()
-	if err != nil {
-		log.Fatalf("error parsing transaction: %s", err)
-	}
-
-	swapInfo, err := parser.ProcessSwapData(transactionData)
-	if err != nil {
-		log.Fatalf("error processing swap data: %s", err)
-	}
-
-	fmt.Printf("=== OKX 交易解析结果 ===\n")
-	marshalledSwapData, _ := json.MarshalIndent(swapInfo, "", "  ")
-	fmt.Println(string(marshalledSwapData))
-
-	// 验证解析结果
-	if len(swapInfo.AMMs) == 0 {
-		t.Error("应该解析出 AMM 信息")
-	}
+	tx := getMainnetTransaction(t, "okx", "5xaT2SXQUyvyLGsnyyoKMwsDoHrx1enCKofkdRMdNaL5MW26gjQBM3AWebwjTJ49uqEqnFu5d9nXJek6gUSGCqbL")
+	// OKX 路由经过的 AMM 不固定，不检查 AMMs
+	checkMainnetSwap(t, tx, "")
 }
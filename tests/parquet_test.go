@@ -0,0 +1,241 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/parquet-go/parquet-go"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/sink"
+)
+
+func TestParquetSink(t *testing.T) {
+	swaps := corpusSwaps(t, loadCorpus(t))
+	sort.Slice(swaps, func(i, j int) bool { return swaps[i].Slot < swaps[j].Slot })
+	dir := t.TempDir()
+
+	s, err := sink.NewParquet(filepath.Join(dir, "out", "swaps.parquet"), sink.ParquetOptions{SlotsPerFile: 100_000_000, RowGroupSize: 2})
+	if err != nil {
+		t.Fatalf("error creating parquet sink: %s", err)
+	}
+	for _, swap := range swaps {
+		if err := s.Write(swap); err != nil {
+			t.Fatalf("error writing swap: %s", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("error closing sink: %s", err)
+	}
+
+	// 语料的 slot 分布在 2xx 和 3xx 百万两个范围内
+	prefix := filepath.Join(dir, "out", "swaps")
+	wantFiles := []string{
+		prefix + "-transactions-000200000000-000299999999.parquet",
+		prefix + "-legs-000200000000-000299999999.parquet",
+		prefix + "-transactions-000300000000-000399999999.parquet",
+		prefix + "-legs-000300000000-000399999999.parquet",
+	}
+	files := s.Files()
+	if len(files) != len(wantFiles) {
+		t.Fatalf("应写出 %d 个文件，实际 %v", len(wantFiles), files)
+	}
+	for i := range wantFiles {
+		if files[i] != wantFiles[i] {
+			t.Errorf("第 %d 个文件应为 %s，实际 %s", i, wantFiles[i], files[i])
+		}
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "out", "*.tmp")); len(tmp) > 0 {
+		t.Errorf("不应残留临时文件: %v", tmp)
+	}
+
+	var rows []sink.Row
+	var legs []sink.LegRow
+	for _, file := range files {
+		if strings.Contains(file, "-transactions-") {
+			part, err := parquet.ReadFile[sink.Row](file)
+			if err != nil {
+				t.Fatalf("error reading %s: %s", file, err)
+			}
+			rows = append(rows, part...)
+		} else {
+			part, err := parquet.ReadFile[sink.LegRow](file)
+			if err != nil {
+				t.Fatalf("error reading %s: %s", file, err)
+			}
+			legs = append(legs, part...)
+		}
+	}
+
+	var wantLegs []sink.LegRow
+	for _, swap := range swaps {
+		wantLegs = append(wantLegs, sink.NewLegRows(swap)...)
+	}
+	if len(rows) != len(swaps) || len(legs) != len(wantLegs) {
+		t.Fatalf("应读回 %d 行交易和 %d 行交换腿，实际 %d 和 %d", len(swaps), len(wantLegs), len(rows), len(legs))
+	}
+	for i, swap := range swaps {
+		if rows[i] != sink.NewRow(swap) {
+			t.Errorf("第 %d 行交易不一致:\n got: %+v\nwant: %+v", i, rows[i], sink.NewRow(swap))
+		}
+	}
+	for i := range wantLegs {
+		if legs[i] != wantLegs[i] {
+			t.Errorf("第 %d 行交换腿不一致:\n got: %+v\nwant: %+v", i, legs[i], wantLegs[i])
+		}
+	}
+
+	// RowGroupSize 为 2 时，每个文件的 row group 数为 ceil(行数 / 2)
+	f, err := os.Open(wantFiles[2])
+	if err != nil {
+		t.Fatalf("error opening parquet: %s", err)
+	}
+	defer f.Close()
+	stat, _ := f.Stat()
+	file, err := parquet.OpenFile(f, stat.Size())
+	if err != nil {
+		t.Fatalf("error opening parquet: %s", err)
+	}
+	if groups, want := len(file.RowGroups()), int((file.NumRows()+1)/2); groups != want || groups < 2 {
+		t.Errorf("应有 %d 个 row group，实际 %d", want, groups)
+	}
+
+	// 再次写入同一范围不会覆盖已有文件，而是新建 part
+	s, err = sink.NewParquet(filepath.Join(dir, "out", "swaps.parquet"), sink.ParquetOptions{SlotsPerFile: 100_000_000})
+	if err != nil {
+		t.Fatalf("error creating parquet sink: %s", err)
+	}
+	last := swaps[len(swaps)-1]
+	if err := s.Write(last); err != nil {
+		t.Fatalf("error writing swap: %s", err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatalf("error flushing sink: %s", err)
+	}
+	if err := s.Write(last); err != nil {
+		t.Fatalf("error writing swap: %s", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("error closing sink: %s", err)
+	}
+	if files := s.Files(); len(files) != 4 || files[0] != prefix+"-transactions-000300000000-000399999999-1.parquet" || files[2] != prefix+"-transactions-000300000000-000399999999-2.parquet" {
+		t.Errorf("追加写入应新建 part 文件，实际 %v", files)
+	}
+}
+
+func TestParquetLegs(t *testing.T) {
+	for _, swap := range corpusSwaps(t, loadCorpus(t)) {
+		legs := solanaswapgo.NewLegs(swap)
+		if len(legs) != len(swap.Swaps) {
+			t.Fatalf("%s: 应有 %d 个交换腿，实际 %d", swap.Signature, len(swap.Swaps), len(legs))
+		}
+		// LegRow 与 Leg 的 JSON 字段相同，序列化结果一致说明没有遗漏字段
+		if rows := sink.NewLegRows(swap); !jsonEqual(rows, legs) {
+			t.Errorf("%s: LegRow 与 Leg 不一致:\n got: %+v\nwant: %+v", swap.Signature, rows, legs)
+		}
+		for i, leg := range legs {
+			if leg.Signature != swap.Signature.String() || leg.Slot != swap.Slot || leg.LegIndex != i || leg.Protocol != string(swap.Swaps[i].Type) {
+				t.Errorf("%s: 第 %d 个交换腿的元数据不正确: %+v", swap.Signature, i, leg)
+			}
+			if leg.DataType == "" || leg.InputMint == "" {
+				t.Errorf("%s: 第 %d 个交换腿缺少类型或输入代币: %+v", swap.Signature, i, leg)
+			}
+			if leg.InputUIAmount != solanaswapgo.FormatAmount(leg.InputAmount, uint8(leg.InputDecimals)) {
+				t.Errorf("%s: 第 %d 个交换腿的 UI 数量不正确: %s", swap.Signature, i, leg.InputUIAmount)
+			}
+		}
+
+		// 单腿的事件型交换，输入输出应与 SwapInfo 一致
+		if len(legs) == 1 && legs[0].OutputMint != "" && swap.SwapInfo != nil {
+			leg, info := legs[0], swap.SwapInfo
+			if leg.InputMint != info.TokenInMint.String() || leg.OutputMint != info.TokenOutMint.String() || leg.OutputDecimals != int(info.TokenOutDecimals) {
+				t.Errorf("%s: 交换腿与 SwapInfo 不一致: %+v", swap.Signature, leg)
+			}
+		}
+	}
+}
+
+// flushCounter 统计 Flush 的调用次数
+type flushCounter struct {
+	*sink.Parquet
+	flushes int
+}
+
+func (s *flushCounter) Flush() error {
+	s.flushes++
+	return s.Parquet.Flush()
+}
+
+func TestParquetBackfill(t *testing.T) {
+	server := newRPCTestServer(t)
+	address := solana.TokenProgramID
+	history := addressHistory(t, loadCorpus(t), address)
+	dir := t.TempDir()
+
+	parquetSink, err := sink.NewParquet(filepath.Join(dir, "swaps.parquet"), sink.ParquetOptions{SlotsPerFile: 100_000_000, RowGroupSize: 4})
+	if err != nil {
+		t.Fatalf("error creating parquet sink: %s", err)
+	}
+	counter := &flushCounter{Parquet: parquetSink}
+	runner := newTestRunner(server, address, counter, filepath.Join(dir, "checkpoint.json"))
+	runner.FlushSlots = 100_000_000
+	checkpoint, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("error running backfill: %s", err)
+	}
+	if err := parquetSink.Close(); err != nil {
+		t.Fatalf("error closing sink: %s", err)
+	}
+	if !checkpoint.Done || checkpoint.Swaps != uint64(len(history)) {
+		t.Errorf("检查点统计不正确: %+v", checkpoint)
+	}
+
+	// 每页 2 个签名，但每个 slot 范围只 Flush 一次，从新到旧各写出一对文件
+	counts := map[uint64]int64{}
+	for _, ctx := range history {
+		counts[ctx.result.Slot/100_000_000]++
+	}
+	if len(counts) != 2 || counter.flushes != 2 {
+		t.Fatalf("语料应跨 2 个 slot 范围并 Flush 2 次，实际 %d 个范围 %d 次", len(counts), counter.flushes)
+	}
+	prefix := filepath.Join(dir, "swaps")
+	wantFiles := []string{
+		prefix + "-transactions-000300000000-000399999999.parquet",
+		prefix + "-legs-000300000000-000399999999.parquet",
+		prefix + "-transactions-000200000000-000299999999.parquet",
+		prefix + "-legs-000200000000-000299999999.parquet",
+	}
+	files := parquetSink.Files()
+	if len(files) != len(wantFiles) {
+		t.Fatalf("应写出 %d 个文件，实际 %v", len(wantFiles), files)
+	}
+	for i := range wantFiles {
+		if files[i] != wantFiles[i] {
+			t.Errorf("第 %d 个文件应为 %s，实际 %s", i, wantFiles[i], files[i])
+		}
+	}
+
+	// RowGroupSize 为 4 时，交易文件的 row group 数为 ceil(行数 / 4)，与分页无关
+	for i, bucket := range []uint64{3, 2} {
+		f, err := os.Open(wantFiles[2*i])
+		if err != nil {
+			t.Fatalf("error opening parquet: %s", err)
+		}
+		defer f.Close()
+		stat, _ := f.Stat()
+		file, err := parquet.OpenFile(f, stat.Size())
+		if err != nil {
+			t.Fatalf("error opening parquet: %s", err)
+		}
+		if file.NumRows() != counts[bucket] {
+			t.Errorf("%s: 应有 %d 行，实际 %d", wantFiles[2*i], counts[bucket], file.NumRows())
+		}
+		if groups, want := len(file.RowGroups()), int((counts[bucket]+3)/4); groups != want {
+			t.Errorf("%s: 应有 %d 个 row group，实际 %d", wantFiles[2*i], want, groups)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/pumpcurve"
+)
+
+// preTradeCurve 由事件中交易后的储备还原交易前的曲线。交易后的储备小于成交数量时（如真实储备由虚拟储备推算而来）返回 false
+func preTradeCurve(trade *solanaswapgo.PumpfunTradeEvent) (pumpcurve.Curve, bool) {
+	curve := pumpcurve.FromVirtualReserves(trade.VirtualSolReserves, trade.VirtualTokenReserves)
+	if trade.Version >= solanaswapgo.PumpfunTradeEventV2 {
+		curve.RealSolReserves, curve.RealTokenReserves = trade.RealSolReserves, trade.RealTokenReserves
+	}
+	if trade.IsBuy {
+		if curve.VirtualSolReserves < trade.SolAmount || curve.RealSolReserves < trade.SolAmount {
+			return curve, false
+		}
+		curve.VirtualSolReserves -= trade.SolAmount
+		curve.RealSolReserves -= trade.SolAmount
+		curve.VirtualTokenReserves += trade.TokenAmount
+		curve.RealTokenReserves += trade.TokenAmount
+	} else {
+		if curve.VirtualTokenReserves < trade.TokenAmount || curve.RealTokenReserves < trade.TokenAmount {
+			return curve, false
+		}
+		curve.VirtualSolReserves += trade.SolAmount
+		curve.RealSolReserves += trade.SolAmount
+		curve.VirtualTokenReserves -= trade.TokenAmount
+		curve.RealTokenReserves -= trade.TokenAmount
+	}
+	return curve, true
+}
+
+// checkPumpCurveTrade 检查由交易前曲线计算的报价与事件中的成交数量一致，且曲线状态与交易后的储备一致
+func checkPumpCurveTrade(t *testing.T, signature string, trade *solanaswapgo.PumpfunTradeEvent) {
+	t.Helper()
+
+	curve, ok := preTradeCurve(trade)
+	if !ok {
+		t.Errorf("%s: 交易后的储备小于成交数量: %+v", signature, trade)
+		return
+	}
+	if trade.IsBuy {
+		if got := curve.BuyQuote(trade.SolAmount); got != trade.TokenAmount {
+			t.Errorf("%s: 买入报价应为 %d，实际 %d", signature, trade.TokenAmount, got)
+		}
+	} else if got := curve.SellQuote(trade.TokenAmount); got != trade.SolAmount {
+		t.Errorf("%s: 卖出报价应为 %d，实际 %d", signature, trade.SolAmount, got)
+	}
+
+	post := pumpcurve.FromVirtualReserves(trade.VirtualSolReserves, trade.VirtualTokenReserves)
+	if trade.SpotPrice != post.SpotPrice() || trade.MarketCap != post.MarketCap() || trade.CurveProgress <= 0 || trade.CurveProgress > 100 {
+		t.Errorf("%s: 曲线状态不正确: %+v", signature, trade)
+	}
+}
+
+// TestPumpCurveQuotesMatchCorpus 在生成的语料上检查报价，生成语料与 pumpcurve 使用同一套公式，只能说明两者自洽；
+// 与链上成交的比较见 TestMainnetDecoders
+func TestPumpCurveQuotesMatchCorpus(t *testing.T) {
+	trades := 0
+	for _, ctx := range loadCorpus(t) {
+		parser := newCorpusParser(t, ctx)
+		swaps, err := parser.ParseTransaction()
+		if err != nil {
+			t.Fatalf("%s: error parsing transaction: %s", ctx.signature, err)
+		}
+		for _, swap := range swaps {
+			trade, ok := swap.Data.(*solanaswapgo.PumpfunTradeEvent)
+			if !ok {
+				continue
+			}
+			trades++
+			checkPumpCurveTrade(t, ctx.signature, trade)
+		}
+	}
+	if trades < 3 {
+		t.Fatalf("语料中应至少有 3 笔 Pump.fun 交易，实际 %d", trades)
+	}
+}
+
+func TestPumpCurve(t *testing.T) {
+	curve := pumpcurve.New()
+	if curve.Progress() != 0 || curve.Complete() {
+		t.Errorf("新曲线的进度应为 0: %+v", curve)
+	}
+	// 初始价格约为 28 lamports / 代币，市值约 28 SOL
+	if price := curve.SpotPrice(); math.Abs(price-2.796e-8) > 1e-11 {
+		t.Errorf("初始价格不正确: %v", price)
+	}
+	if marketCap := curve.MarketCap(); math.Abs(marketCap-27.96) > 0.01 {
+		t.Errorf("初始市值不正确: %v", marketCap)
+	}
+
+	bought, tokens, spent := curve.Buy(1_000_000_000)
+	if tokens != curve.BuyQuote(1_000_000_000) || spent != 1_000_000_000 || bought.SpotPrice() <= curve.SpotPrice() || bought.Progress() <= 0 {
+		t.Errorf("买入后价格和进度应上升: %+v", bought)
+	}
+	sold, sol := bought.Sell(tokens)
+	if sol > 1_000_000_000 || 1_000_000_000-sol > 1 || sold.VirtualTokenReserves != curve.VirtualTokenReserves {
+		t.Errorf("卖回买到的代币应得到投入的 SOL（误差不超过 1 lamport）: %d", sol)
+	}
+
+	// 买入量不超过剩余的真实代币储备，只花费买完剩余代币需要的 SOL，买完后曲线完成
+	complete, tokens, spent := curve.Buy(1_000_000_000_000)
+	if tokens != pumpcurve.InitialRealTokenReserves || !complete.Complete() || complete.Progress() != 100 {
+		t.Errorf("买完曲线后进度应为 100: %d %+v", tokens, complete)
+	}
+	if spent != curve.BuyCost(tokens) || spent >= 1_000_000_000_000 || complete.RealSolReserves != spent ||
+		complete.VirtualSolReserves != curve.VirtualSolReserves+spent {
+		t.Errorf("买完曲线时应只花费剩余代币的成本: %d %+v", spent, complete)
+	}
+	if quoted := curve.BuyQuote(spent - 1); quoted >= tokens {
+		t.Errorf("少 1 lamport 时不应买完剩余代币: %d", quoted)
+	}
+	if curve.BuyQuote(0) != 0 || curve.SellQuote(0) != 0 {
+		t.Error("数量为 0 时报价应为 0")
+	}
+}
+
+// TestPumpfunMainnetLayouts 列出还没有录制主网交易的 Pump.fun 买入、卖出和 TradeEvent 版本，
+// TestMainnetDecoders 用 pumpcurve 逐笔核对已录制交易的报价
+func TestPumpfunMainnetLayouts(t *testing.T) {
+	skipMissingMainnetLayouts(t, "pumpfun",
+		"PumpfunTradeEvent v1", "PumpfunTradeEvent v2", "PumpfunTradeEvent v3", "PumpfunTradeEvent v4", "PumpfunTradeEvent v5",
+		"PumpfunTradeEvent buy", "PumpfunTradeEvent sell",
+	)
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+func TestPumpfunTradeEventVersions(t *testing.T) {
+	const signature = "5HKP3z5w1uZc2N3krM9fErvBX7egTm6Nc8pL2hDLxrCtgmnVNQEyj9fkkx3JeoRuhdh5yMPRbg2JUuZdCi8xP4uP"
+
+	// 各版本事件数据（不含 16 字节判别器）的长度
+	layouts := []struct {
+		version int
+		size    int
+	}{
+		{solanaswapgo.PumpfunTradeEventV1, 105},
+		{solanaswapgo.PumpfunTradeEventV2, 121},
+		{solanaswapgo.PumpfunTradeEventV3, 217},
+		{solanaswapgo.PumpfunTradeEventV4, 250},
+		{solanaswapgo.PumpfunTradeEventV5, -1},
+	}
+	for _, layout := range layouts {
+		buy := loadCorpusTx(t, "pumpfun", signature)
+		for i := range buy.result.Meta.InnerInstructions {
+			for j, instruction := range buy.result.Meta.InnerInstructions[i].Instructions {
+				if bytes.HasPrefix(instruction.Data, solanaswapgo.PumpfunTradeEventDiscriminator[:]) && layout.size > 0 {
+					buy.result.Meta.InnerInstructions[i].Instructions[j].Data = instruction.Data[:16+layout.size]
+				}
+			}
+		}
+
+		parser, err := solanaswapgo.NewTransactionParserFromTransactionResult(buy.result, buy.tx, buy.result.Meta)
+		if err != nil {
+			t.Fatalf("error creating parser: %s", err)
+		}
+		parser.Log = solanaswapgo.DiscardLogger
+		swaps, err := parser.ParseTransaction()
+		if err != nil || len(swaps) != 1 {
+			t.Fatalf("v%d: 应解析出 1 个交换: %v %s", layout.version, swaps, err)
+		}
+		trade, ok := swaps[0].Data.(*solanaswapgo.PumpfunTradeEvent)
+		if !ok || trade.Version != layout.version || trade.SolAmount != 500_000_000 || trade.VirtualSolReserves == 0 {
+			t.Fatalf("v%d: 事件解析错误: %+v", layout.version, swaps[0].Data)
+		}
+		if (trade.RealSolReserves != 0) != (layout.version >= solanaswapgo.PumpfunTradeEventV2) ||
+			(trade.LastUpdateTimestamp != 0) != (layout.version >= solanaswapgo.PumpfunTradeEventV4) ||
+			(trade.IxName == "buy") != (layout.version >= solanaswapgo.PumpfunTradeEventV5) {
+			t.Errorf("v%d: 追加字段应只在对应版本中存在: %+v", layout.version, trade)
+		}
+
+		swapInfo, err := parser.ProcessSwapData(swaps)
+		if err != nil {
+			t.Fatalf("error processing swap data: %s", err)
+		}
+		if layout.version < solanaswapgo.PumpfunTradeEventV3 {
+			if swapInfo.Fees != nil {
+				t.Errorf("v%d: 不包含费用的事件不应设置 Fees: %+v", layout.version, swapInfo.Fees)
+			}
+			continue
+		}
+		// 手续费 95 bps，创作者费用 5 bps
+		fees := swapInfo.Fees
+		if fees == nil || fees.Mint != solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID || fees.ProtocolFee != 4_750_000 || fees.CreatorFee != 250_000 {
+			t.Errorf("v%d: 费用错误: %+v", layout.version, fees)
+		}
+		if trade.FeeBasisPoints != 95 || trade.CreatorFeeBasisPoints != 5 || trade.Creator.IsZero() || trade.FeeRecipient.IsZero() {
+			t.Errorf("v%d: 费用字段错误: %+v", layout.version, trade)
+		}
+	}
+
+	// 长度落在两个版本之间说明数据被截断，应记为解码错误
+	ctx := loadCorpusTx(t, "pumpfun", signature)
+	for i := range ctx.result.Meta.InnerInstructions {
+		for j, instruction := range ctx.result.Meta.InnerInstructions[i].Instructions {
+			if bytes.HasPrefix(instruction.Data, solanaswapgo.PumpfunTradeEventDiscriminator[:]) {
+				ctx.result.Meta.InnerInstructions[i].Instructions[j].Data = instruction.Data[:16+150]
+			}
+		}
+	}
+	parser := newCorpusParser(t, ctx)
+	if swaps, _ := parser.ParseTransaction(); len(swaps) != 0 {
+		t.Errorf("截断的事件不应解析出交换: %+v", swaps)
+	}
+}
@@ -1,97 +1,47 @@
 package tests
 
 import (
-	"context"
-	"encoding/json"
-	"fmt"
-	"log"
 	"testing"
 
-	"github.com/gagliardetto/solana-go"
-	"github.com/gagliardetto/solana-go/rpc"
 	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
 )
 
 func TestPumpfunTransaction(t *testing.T) {
-	rpcClient := rpc.New("https://mainnet.helius-rpc.com/?api-key=b69296cd-290c-43f7-bf49-9e9ded12b5e0")
-	txSig := solana.MustSignatureFromBase58("4Cod1cNGv6RboJ7rSB79yeVCR4Lfd25rFgLY3eiPJfTJjTGyYP1r2i1upAYZHQsWDqUbGd1bhTRm1bpSQcpWMnEz")
+	tx := getMainnetTransaction(t, "pumpfun", "4Cod1cNGv6RboJ7rSB79yeVCR4Lfd25rFgLY3eiPJfTJjTGyYP1r2i1upAYZHQsWDqUbGd1bhTRm1bpSQcpWMnEz")
+	swaps, swapInfo := checkMainnetSwap(t, tx, solanaswapgo.PUMP_FUN)
 
-	var maxTxVersion uint64 = 0
-	tx, err := rpcClient.GetTransaction(
-		context.TODO(),
-		txSig,
-		&rpc.GetTransactionOpts{
-			Commitment:                     rpc.CommitmentConfirmed,
-			MaxSupportedTransactionVersion: &maxTxVersion,
-		},
-	)
-	if err != nil {
-		log.Fatalf("error getting tx: %s", err)
+	event, ok := swaps[0].Data.(*solanaswapgo.PumpfunTradeEvent)
+	if !ok {
+		t.Fatalf("应由 TradeEvent 解析出 PumpfunTradeEvent: %T", swaps[0].Data)
 	}
-
-	parser, err := solanaswapgo.NewTransactionParser(tx)
-	if err != nil {
-		log.Fatalf("error creating parser: %s", err)
+	// 曲线交易的一侧是 SOL，另一侧是事件中的代币
+	sol := solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID
+	if event.IsBuy && (swapInfo.TokenInMint != sol || swapInfo.TokenOutMint != event.Mint || swapInfo.TokenInAmount != event.SolAmount || swapInfo.TokenOutAmount != event.TokenAmount) ||
+		!event.IsBuy && (swapInfo.TokenInMint != event.Mint || swapInfo.TokenOutMint != sol || swapInfo.TokenInAmount != event.TokenAmount || swapInfo.TokenOutAmount != event.SolAmount) {
+		t.Errorf("SwapInfo 应与 TradeEvent 一致: %+v %+v", swapInfo, event)
 	}
-
-	transactionData, err := parser.ParseTransaction()
-	if err != nil {
-		log.Fatalf("error parsing transaction: %s", err)
-	}
-
-	swapInfo, err := parser.ProcessSwapData(transactionData)
-	if err != nil {
-		log.Fatalf("error processing swap data: %s", err)
-	}
-
-	fmt.Printf("=== PumpFun 交易解析结果 ===\n")
-	marshalledSwapData, _ := json.MarshalIndent(swapInfo, "", "  ")
-	fmt.Println(string(marshalledSwapData))
-
-	// 验证解析结果
-	if len(swapInfo.AMMs) == 0 {
-		t.Error("应该解析出 AMM 信息")
+	if swapInfo.Fees != nil && swapInfo.Fees.ProtocolFee != event.Fee {
+		t.Errorf("协议费应为 %d，实际 %d", event.Fee, swapInfo.Fees.ProtocolFee)
 	}
 }
 
 func TestPumpfunAMMTransaction(t *testing.T) {
-	rpcClient := rpc.New("https://mainnet.helius-rpc.com/?api-key=b69296cd-290c-43f7-bf49-9e9ded12b5e0")
-	txSig := solana.MustSignatureFromBase58("23QJ6qbKcwzA76TX2uSaEb3EtBorKYty9phGYUueMyGoazopvyyZfPfGmGgGzmdt5CPW9nEuB72nnBfaGnydUa6D")
-
-	var maxTxVersion uint64 = 0
-	tx, err := rpcClient.GetTransaction(
-		context.TODO(),
-		txSig,
-		&rpc.GetTransactionOpts{
-			Commitment:                     rpc.CommitmentConfirmed,
-			MaxSupportedTransactionVersion: &maxTxVersion,
-		},
-	)
-	if err != nil {
-		log.Fatalf("error getting tx: %s", err)
-	}
-
-	parser, err := solanaswapgo.NewTransactionParser(tx)
-	if err != nil {
-		log.Fatalf("error creating parser: %s", err)
-	}
-
-	transactionData, err := parser.ParseTransaction()
-	if err != nil {
-		log.Fatalf("error parsing transaction: %s", err)
-	}
-
-	swapInfo, err := parser.ProcessSwapData(transactionData)
-	if err != nil {
-		log.Fatalf("error processing swap data: %s", err)
-	}
-
-	fmt.Printf("=== PumpFun AMM (Pumpswap) 交易解析结果 ===\n")
-	marshalledSwapData, _ := json.MarshalIndent(swapInfo, "", "  ")
-	fmt.Println(string(marshalledSwapData))
-
-	// 验证解析结果
-	if len(swapInfo.AMMs) == 0 {
-		t.Error("应该解析出 AMM 信息")
+	tx := getMainnetTransaction(t, "pumpswap", "23QJ6qbKcwzA76TX2uSaEb3EtBorKYty9phGYUueMyGoazopvyyZfPfGmGgGzmdt5CPW9nEuB72nnBfaGnydUa6D")
+	swaps, swapInfo := checkMainnetSwap(t, tx, solanaswapgo.PUMP_SWAP)
+
+	switch event := swaps[0].Data.(type) {
+	case *solanaswapgo.PumpfunAMMBuyEvent:
+		if swapInfo.TokenInMint != event.QuoteMint || swapInfo.TokenOutMint != event.BaseMint {
+			t.Errorf("买入应从 quote 换为 base: %s -> %s", swapInfo.TokenInMint, swapInfo.TokenOutMint)
+		}
+	case *solanaswapgo.PumpfunAMMSellEvent:
+		if swapInfo.TokenInMint != event.BaseMint || swapInfo.TokenOutMint != event.QuoteMint {
+			t.Errorf("卖出应从 base 换为 quote: %s -> %s", swapInfo.TokenInMint, swapInfo.TokenOutMint)
+		}
+	default:
+		t.Fatalf("应由 BuyEvent 或 SellEvent 解析: %T", swaps[0].Data)
+	}
+	if swapInfo.Fees == nil || swapInfo.Fees.Mint != swapInfo.TokenInMint && swapInfo.Fees.Mint != swapInfo.TokenOutMint || swapInfo.Fees.LPFee == 0 {
+		t.Errorf("应解析出以 quote 计的 LP 费用: %+v", swapInfo.Fees)
 	}
 }
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+const (
+	pumpswapBuySignature  = "5ubdHftnUTNk1aEw1KYF1aGGrXAargQPyaog2WiKGpmHQVHvxr4dCUAiE6uhP7sDqNvqzFSze9PbywkSMqv86jNB"
+	pumpswapSellSignature = "3Ukw5c5JCrwEdixbLSj5EkZ1hs9MDSWLB4TJCNXgWCbkLM7L1VqAtLHRozBJMePh8tx1xBwn2xgybWZRkx4hNbKY"
+)
+
+// editInnerInstructions 对交易中数据以 prefix 开头的内部指令调用 edit，edit 返回 false 时删除该指令
+func editInnerInstructions(ctx corpusTx, prefix []byte, edit func(data []byte) ([]byte, bool)) {
+	for i := range ctx.result.Meta.InnerInstructions {
+		instructions := ctx.result.Meta.InnerInstructions[i].Instructions
+		kept := instructions[:0]
+		for _, instruction := range instructions {
+			if bytes.HasPrefix(instruction.Data, prefix) {
+				data, keep := edit(instruction.Data)
+				if !keep {
+					continue
+				}
+				instruction.Data = data
+			}
+			kept = append(kept, instruction)
+		}
+		ctx.result.Meta.InnerInstructions[i].Instructions = kept
+	}
+}
+
+func TestPumpSwapEvents(t *testing.T) {
+	buy := loadCorpusTx(t, "pumpswap", pumpswapBuySignature)
+	swaps, swapInfo := parseSwapInfo(t, buy)
+	if len(swaps) != 1 || swaps[0].Type != solanaswapgo.PUMP_SWAP {
+		t.Fatalf("买入应解析出 1 个 PumpSwap 事件: %+v", swaps)
+	}
+	buyEvent, ok := swaps[0].Data.(*solanaswapgo.PumpfunAMMBuyEvent)
+	if !ok || buyEvent.Version != solanaswapgo.PumpfunAMMEventV3 || buyEvent.BaseMintDecimals != 6 || buyEvent.QuoteMint != solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID {
+		t.Fatalf("BuyEvent 解析错误: %+v", swaps[0].Data)
+	}
+	if swapInfo.TokenInMint != buyEvent.QuoteMint || swapInfo.TokenInAmount != buyEvent.UserQuoteAmountIn ||
+		swapInfo.TokenOutMint != buyEvent.BaseMint || swapInfo.TokenOutAmount != 1_000_000_000_000 || swapInfo.AMMs[0] != string(solanaswapgo.PUMP_SWAP) {
+		t.Errorf("买入的 SwapInfo 不正确: %+v", swapInfo)
+	}
+	// 用户支付的 quote 包含 LP、协议和创作者费用
+	fees := swapInfo.Fees
+	if fees == nil || fees.LPFee == 0 || fees.ProtocolFee == 0 || fees.CreatorFee == 0 ||
+		buyEvent.QuoteAmountIn+fees.LPFee+fees.ProtocolFee+fees.CreatorFee != buyEvent.UserQuoteAmountIn {
+		t.Errorf("买入的费用不正确: %+v %+v", fees, buyEvent)
+	}
+
+	sell := loadCorpusTx(t, "pumpswap", pumpswapSellSignature)
+	swaps, swapInfo = parseSwapInfo(t, sell)
+	sellEvent, ok := swaps[0].Data.(*solanaswapgo.PumpfunAMMSellEvent)
+	if len(swaps) != 1 || !ok || sellEvent.Version != solanaswapgo.PumpfunAMMEventV2 {
+		t.Fatalf("卖出应解析出 1 个 SellEvent: %+v", swaps)
+	}
+	if swapInfo.TokenInMint != sellEvent.BaseMint || swapInfo.TokenInAmount != sellEvent.BaseAmountIn ||
+		swapInfo.TokenOutMint != solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID || swapInfo.TokenOutAmount != sellEvent.UserQuoteAmountOut {
+		t.Errorf("卖出的 SwapInfo 不正确: %+v", swapInfo)
+	}
+	if fees := swapInfo.Fees; fees == nil || sellEvent.QuoteAmountOut-fees.LPFee-fees.ProtocolFee-fees.CreatorFee != sellEvent.UserQuoteAmountOut {
+		t.Errorf("卖出的费用不正确: %+v %+v", fees, sellEvent)
+	}
+}
+
+func TestPumpSwapEventFallbacks(t *testing.T) {
+	// 没有创作者费用的旧版 BuyEvent
+	buy := loadCorpusTx(t, "pumpswap", pumpswapBuySignature)
+	editInnerInstructions(buy, solanaswapgo.PumpfunAMMBuyEventDiscriminator[:], func(data []byte) ([]byte, bool) {
+		return data[:16+14*8+6*32], true
+	})
+	swaps, swapInfo := parseSwapInfo(t, buy)
+	event, ok := swaps[0].Data.(*solanaswapgo.PumpfunAMMBuyEvent)
+	if !ok || event.Version != solanaswapgo.PumpfunAMMEventV1 || !event.CoinCreator.IsZero() || swapInfo.Fees.CreatorFee != 0 || swapInfo.Fees.ProtocolFee == 0 {
+		t.Errorf("旧版 BuyEvent 解析错误: %+v %+v", swaps[0].Data, swapInfo.Fees)
+	}
+
+	// 没有事件时按转账解析，类型仍为 PumpSwap
+	sell := loadCorpusTx(t, "pumpswap", pumpswapSellSignature)
+	editInnerInstructions(sell, solanaswapgo.PumpfunAMMSellEventDiscriminator[:], func(data []byte) ([]byte, bool) {
+		return nil, false
+	})
+	swaps, swapInfo = parseSwapInfo(t, sell)
+	if len(swaps) == 0 || swapInfo.Fees != nil || swapInfo.AMMs[0] != string(solanaswapgo.PUMP_SWAP) {
+		t.Fatalf("没有事件时应按转账解析: %+v %+v", swaps, swapInfo)
+	}
+	for _, swap := range swaps {
+		if _, ok := swap.Data.(*solanaswapgo.TransferCheck); !ok || swap.Type != solanaswapgo.PUMP_SWAP {
+			t.Errorf("转账解析的交换腿类型错误: %+v", swap)
+		}
+	}
+}
+
+// TestPumpSwapMainnetLayouts 列出还没有录制主网交易的 PumpSwap BuyEvent 和 SellEvent 版本，
+// 上面的测试只用按同一布局生成的语料，已录制的交易由 TestMainnetDecoders 对照链上余额检查
+func TestPumpSwapMainnetLayouts(t *testing.T) {
+	skipMissingMainnetLayouts(t, "pumpswap",
+		"PumpfunAMMBuyEvent v1", "PumpfunAMMBuyEvent v2", "PumpfunAMMBuyEvent v3", "PumpfunAMMBuyEvent v4",
+		"PumpfunAMMSellEvent v1", "PumpfunAMMSellEvent v2",
+	)
+}
@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+const raydiumCLMMSignature = "LZtaeciXMskraALBmpejJzGoorCzsZvrixJ21Eyjs1BeZ6EGoywqo5j82BLdzRq8wdkJVp6CFkxX5488JNKTjYj"
+
+// SwapEvent 中 transfer_fee_0 和 transfer_fee_1 的偏移，包括 8 字节判别器和 4 个地址
+const (
+	clmmTransferFee0Offset = 8 + 4*32 + 8
+	clmmTransferFee1Offset = clmmTransferFee0Offset + 16
+)
+
+// editProgramData 修改日志中 Program data 的事件数据（包括判别器），edit 返回 nil 时删除该行
+func editProgramData(ctx corpusTx, edit func(data []byte) []byte) {
+	editLogs(ctx, "Program data: ", func(line string) (string, bool) {
+		data, err := base64.StdEncoding.DecodeString(line[len("Program data: "):])
+		if err != nil {
+			return line, true
+		}
+		data = edit(data)
+		return "Program data: " + base64.StdEncoding.EncodeToString(data), data != nil
+	})
+}
+
+func TestCLMMSwapEvent(t *testing.T) {
+	ctx := loadCorpusTx(t, "raydium", raydiumCLMMSignature)
+	swaps, swapInfo := parseSwapInfo(t, ctx)
+	if len(swaps) != 1 || swaps[0].Type != solanaswapgo.RAYDIUM {
+		t.Fatalf("应解析出 1 个 Raydium 交换: %+v", swaps)
+	}
+	event, ok := swaps[0].Data.(*solanaswapgo.RaydiumCLMMSwapEvent)
+	if !ok {
+		t.Fatalf("应由 SwapEvent 解析出 RaydiumCLMMSwapEvent: %T", swaps[0].Data)
+	}
+
+	swap := ctx.tx.Message.Instructions[2]
+	keys := ctx.tx.Message.AccountKeys
+	if event.PoolState != keys[swap.Accounts[2]] || event.Sender != keys[swap.Accounts[0]] {
+		t.Errorf("池子或用户不正确: %+v", event)
+	}
+	if event.ZeroForOne || !event.IsBaseInput || event.Amount != 150_000_000 || event.OtherAmountThreshold != binary.LittleEndian.Uint64(swap.Data[16:24]) {
+		t.Errorf("方向或指令参数不正确: %+v", event)
+	}
+	if event.Mint0 != solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID || event.Decimals0 != 9 || event.Decimals1 != 6 || event.Liquidity.BigInt().Uint64() != 55_000_000_000_000 {
+		t.Errorf("代币或流动性不正确: %+v", event)
+	}
+
+	// 交换后的价格约为 150.2 USDC/SOL，Tick 满足 1.0001^tick <= 原始价格 < 1.0001^(tick+1)
+	if math.Abs(event.Price-150.2) > 1e-6 {
+		t.Errorf("价格应约为 150.2，实际 %v", event.Price)
+	}
+	raw := event.Price / 1e3
+	if lower := math.Pow(1.0001, float64(event.Tick)); raw < lower || raw >= lower*1.0001 {
+		t.Errorf("Tick %d 与价格 %v 不一致", event.Tick, event.Price)
+	}
+
+	if swapInfo.TokenInMint != event.Mint1 || swapInfo.TokenInAmount != 150_000_000 || swapInfo.TokenInDecimals != 6 ||
+		swapInfo.TokenOutMint != event.Mint0 || swapInfo.TokenOutAmount != 998_700_000 || swapInfo.TokenOutDecimals != 9 {
+		t.Errorf("SwapInfo 应使用 SwapEvent 中的数量: %+v", swapInfo)
+	}
+
+	leg := solanaswapgo.NewLegs(solanaswapgo.BlockSwap{Swaps: swaps, SwapInfo: swapInfo})[0]
+	if leg.Pool != event.PoolState.String() || leg.Trader != event.Sender.String() || leg.InputAmount != 150_000_000 || leg.OutputAmount != 998_700_000 {
+		t.Errorf("交换腿应包含池子和实际数量: %+v", leg)
+	}
+}
+
+func TestCLMMSwapEventTransferFees(t *testing.T) {
+	ctx := loadCorpusTx(t, "raydium", raydiumCLMMSignature)
+	editProgramData(ctx, func(data []byte) []byte {
+		binary.LittleEndian.PutUint64(data[clmmTransferFee0Offset:], 700_000)
+		binary.LittleEndian.PutUint64(data[clmmTransferFee1Offset:], 15_000)
+		return data
+	})
+	// 改为 swap 指令，代币取自池子代币账户的余额记录
+	copy(ctx.tx.Message.Instructions[2].Data, solanaswapgo.RaydiumCLMMSwapDiscriminator[:])
+
+	_, swapInfo := parseSwapInfo(t, ctx)
+	if swapInfo.TokenInMint.String() != "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v" || swapInfo.TokenOutMint != solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID {
+		t.Errorf("swap 指令的代币不正确: %+v", swapInfo)
+	}
+	// 用户支付的数量包含输入转账费用，收到的数量扣除输出转账费用
+	if swapInfo.TokenInAmount != 150_015_000 || swapInfo.TokenOutAmount != 998_000_000 {
+		t.Errorf("数量应计入转账费用: %+v", swapInfo)
+	}
+}
+
+func TestCLMMSwapEventFallback(t *testing.T) {
+	// SwapEvent 的池子与指令不一致时退回到按转账解析
+	ctx := loadCorpusTx(t, "raydium", raydiumCLMMSignature)
+	editProgramData(ctx, func(data []byte) []byte {
+		data[8] ^= 0xff
+		return data
+	})
+	if swaps, _ := parseSwapInfo(t, ctx); len(swaps) != 2 {
+		t.Errorf("池子不一致时应退回到 2 个转账: %+v", swaps)
+	}
+
+	// 转账费用超过数量时不应回绕，退回到按转账解析
+	for _, offset := range []int{clmmTransferFee0Offset, clmmTransferFee1Offset} {
+		ctx = loadCorpusTx(t, "raydium", raydiumCLMMSignature)
+		editProgramData(ctx, func(data []byte) []byte {
+			binary.LittleEndian.PutUint64(data[offset:], 1<<63)
+			return data
+		})
+		if swaps, _ := parseSwapInfo(t, ctx); len(swaps) != 2 {
+			t.Errorf("转账费用超过数量时应退回到 2 个转账: %+v", swaps)
+		}
+	}
+
+	// 没有日志时按转账解析，结果不变
+	ctx = loadCorpusTx(t, "raydium", raydiumCLMMSignature)
+	_, want := parseSwapInfo(t, ctx)
+	editProgramData(ctx, func([]byte) []byte { return nil })
+	swaps, swapInfo := parseSwapInfo(t, ctx)
+	if len(swaps) != 2 {
+		t.Fatalf("没有 SwapEvent 时应解析出 2 个转账: %+v", swaps)
+	}
+	if swapInfo.TokenInMint != want.TokenInMint || swapInfo.TokenInAmount != want.TokenInAmount ||
+		swapInfo.TokenOutMint != want.TokenOutMint || swapInfo.TokenOutAmount != want.TokenOutAmount {
+		t.Errorf("按转账解析的结果应与 SwapEvent 一致: %+v %+v", swapInfo, want)
+	}
+}
+
+// TestRaydiumCLMMMainnetLayouts 在没有录制 CLMM SwapEvent 的主网交易时跳过，已录制的交易由 TestMainnetDecoders 对照链上余额检查
+func TestRaydiumCLMMMainnetLayouts(t *testing.T) {
+	skipMissingMainnetLayouts(t, "raydium", "RaydiumCLMMSwapEvent")
+}
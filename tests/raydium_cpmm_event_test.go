@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"encoding/binary"
+	"strconv"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+const raydiumCPMMSignature = "4kAmCP2mXKpttB4jKGJm82JaYp54SPjtDqBSr3a1TM35e1LVFytj29CSac3A8Gcf3ajKNBjbnN2NowgM4NmPNhzB"
+
+// SwapEvent V1 的长度和 base_input 的偏移，包括 8 字节判别器
+const (
+	cpmmSwapEventV1Size         = 8 + 32 + 6*8 + 1
+	cpmmBaseInputOffset         = cpmmSwapEventV1Size - 1
+	cpmmOutputFeeOffset         = cpmmBaseInputOffset - 8
+	cpmmCreatorFeeOnInputOffset = cpmmSwapEventV1Size + 2*32 + 2*8
+)
+
+// tokenBalanceChange 返回 owner 持有的 mint 在交易中的余额变化
+func tokenBalanceChange(t *testing.T, meta *rpc.TransactionMeta, owner, mint solana.PublicKey) int64 {
+	t.Helper()
+	amount := func(balances []rpc.TokenBalance) int64 {
+		for _, balance := range balances {
+			if balance.Owner != nil && *balance.Owner == owner && balance.Mint == mint {
+				value, err := strconv.ParseInt(balance.UiTokenAmount.Amount, 10, 64)
+				if err != nil {
+					t.Fatalf("error parsing token balance: %s", err)
+				}
+				return value
+			}
+		}
+		return 0
+	}
+	return amount(meta.PostTokenBalances) - amount(meta.PreTokenBalances)
+}
+
+func TestCPMMSwapEvent(t *testing.T) {
+	ctx := loadCorpusTx(t, "raydium", raydiumCPMMSignature)
+	swaps, swapInfo := parseSwapInfo(t, ctx)
+	if len(swaps) != 1 || swaps[0].Type != solanaswapgo.RAYDIUM {
+		t.Fatalf("应解析出 1 个 Raydium 交换: %+v", swaps)
+	}
+	event, ok := swaps[0].Data.(*solanaswapgo.RaydiumCPMMSwapEvent)
+	if !ok {
+		t.Fatalf("应由 SwapEvent 解析出 RaydiumCPMMSwapEvent: %T", swaps[0].Data)
+	}
+
+	swap := ctx.tx.Message.Instructions[1]
+	keys := ctx.tx.Message.AccountKeys
+	if event.Version != solanaswapgo.RaydiumCPMMSwapEventV2 || event.PoolId != keys[swap.Accounts[3]] || event.User != keys[swap.Accounts[0]] {
+		t.Errorf("版本、池子或用户不正确: %+v", event)
+	}
+	if !event.BaseInput || event.InputAmount != 2_000_000_000 || event.MinimumAmountOut != binary.LittleEndian.Uint64(swap.Data[16:24]) || event.MaxAmountIn != 0 {
+		t.Errorf("指令参数不正确: %+v", event)
+	}
+	if event.InputVaultBefore != 800_000_000_000 || event.OutputVaultBefore != 40_000_000_000_000_000 || event.TradeFee != 5_000_000 {
+		t.Errorf("金库余额或交易费用不正确: %+v", event)
+	}
+	if event.InputMint != solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID || event.OutputTokenProgram != solana.Token2022ProgramID || event.OutputDecimals != 9 {
+		t.Errorf("代币不正确: %+v", event)
+	}
+
+	// Token-2022 的输出扣除 1% 转账费用，SwapInfo 的输出数量为用户实际收到的数量
+	received := tokenBalanceChange(t, ctx.result.Meta, event.User, event.OutputMint)
+	if event.OutputTransferFee == 0 || received != int64(event.OutputAmount-event.OutputTransferFee) {
+		t.Errorf("用户收到 %d，与事件不一致: %+v", received, event)
+	}
+	if swapInfo.TokenInMint != event.InputMint || swapInfo.TokenInAmount != event.InputAmount ||
+		swapInfo.TokenOutMint != event.OutputMint || int64(swapInfo.TokenOutAmount) != received || swapInfo.TokenOutDecimals != 9 {
+		t.Errorf("SwapInfo 应使用扣除转账费用后的数量: %+v", swapInfo)
+	}
+
+	// V2 事件的交易费用以输入代币计，没有拆分给 LP 和协议，计入 TradeFee
+	fees := swapInfo.Fees
+	if fees == nil || fees.Mint != event.InputMint || fees.Decimals != event.InputDecimals || fees.TradeFee != event.TradeFee {
+		t.Fatalf("费用应取自 SwapEvent 的交易费用: %+v", fees)
+	}
+	if fees.CreatorFee != event.InputCreatorFee() || fees.LPFee != 0 || fees.ProtocolFee != 0 || fees.PlatformFee != 0 {
+		t.Errorf("创作者费用只在以输入代币计时填写: %+v", fees)
+	}
+
+	leg := solanaswapgo.NewLegs(solanaswapgo.BlockSwap{Swaps: swaps, SwapInfo: swapInfo})[0]
+	if leg.Pool != event.PoolId.String() || leg.Trader != event.User.String() || int64(leg.OutputAmount) != received {
+		t.Errorf("交换腿应包含池子和实际数量: %+v", leg)
+	}
+}
+
+func TestCPMMSwapEventLayouts(t *testing.T) {
+	// 旧版事件没有 mint 和费用，代币取自指令账户
+	ctx := loadCorpusTx(t, "raydium", raydiumCPMMSignature)
+	editProgramData(ctx, func(data []byte) []byte { return data[:cpmmSwapEventV1Size] })
+	swaps, swapInfo := parseSwapInfo(t, ctx)
+	event, ok := swaps[0].Data.(*solanaswapgo.RaydiumCPMMSwapEvent)
+	if !ok || event.Version != solanaswapgo.RaydiumCPMMSwapEventV1 || event.TradeFee != 0 || event.OutputMint.IsZero() {
+		t.Fatalf("应解析出 V1 事件: %+v", swaps[0].Data)
+	}
+	if swapInfo.TokenOutMint != event.OutputMint || swapInfo.TokenOutAmount != event.OutputAmount-event.OutputTransferFee {
+		t.Errorf("V1 事件的 SwapInfo 不正确: %+v", swapInfo)
+	}
+	if swapInfo.Fees != nil {
+		t.Errorf("V1 事件没有费用: %+v", swapInfo.Fees)
+	}
+
+	// swap_base_output 的滑点限制为最多输入
+	ctx = loadCorpusTx(t, "raydium", raydiumCPMMSignature)
+	copy(ctx.tx.Message.Instructions[1].Data, solanaswapgo.RaydiumCPMMSwapBaseOutputDiscriminator[:])
+	editProgramData(ctx, func(data []byte) []byte {
+		data[cpmmBaseInputOffset] = 0
+		return data
+	})
+	swaps, _ = parseSwapInfo(t, ctx)
+	event, ok = swaps[0].Data.(*solanaswapgo.RaydiumCPMMSwapEvent)
+	if !ok || event.BaseInput || event.MaxAmountIn != 2_000_000_000 || event.MinimumAmountOut != 0 {
+		t.Errorf("swap_base_output 解析错误: %+v", swaps[0].Data)
+	}
+}
+
+func TestCPMMSwapEventFallback(t *testing.T) {
+	// 事件的 base_input 与指令不一致时退回到按转账解析
+	ctx := loadCorpusTx(t, "raydium", raydiumCPMMSignature)
+	copy(ctx.tx.Message.Instructions[1].Data, solanaswapgo.RaydiumCPMMSwapBaseOutputDiscriminator[:])
+	if swaps, _ := parseSwapInfo(t, ctx); len(swaps) != 2 {
+		t.Errorf("base_input 不一致时应退回到 2 个转账: %+v", swaps)
+	}
+
+	// 输出转账费用超过输出数量时不应回绕，退回到按转账解析
+	ctx = loadCorpusTx(t, "raydium", raydiumCPMMSignature)
+	editProgramData(ctx, func(data []byte) []byte {
+		binary.LittleEndian.PutUint64(data[cpmmOutputFeeOffset:], 1<<63)
+		return data
+	})
+	if swaps, _ := parseSwapInfo(t, ctx); len(swaps) != 2 {
+		t.Errorf("转账费用超过数量时应退回到 2 个转账: %+v", swaps)
+	}
+
+	// 没有日志时按转账解析，输出为池子转出的数量
+	ctx = loadCorpusTx(t, "raydium", raydiumCPMMSignature)
+	editProgramData(ctx, func([]byte) []byte { return nil })
+	swaps, swapInfo := parseSwapInfo(t, ctx)
+	if len(swaps) != 2 {
+		t.Fatalf("没有 SwapEvent 时应解析出 2 个转账: %+v", swaps)
+	}
+	if swapInfo.TokenInAmount != 2_000_000_000 || swapInfo.TokenOutAmount != 99_501_867_218_623 {
+		t.Errorf("按转账解析的结果不正确: %+v", swapInfo)
+	}
+}
+
+func TestCPMMSwapEventCreatorFee(t *testing.T) {
+	for _, onInput := range []bool{true, false} {
+		ctx := loadCorpusTx(t, "raydium", raydiumCPMMSignature)
+		editProgramData(ctx, func(data []byte) []byte {
+			binary.LittleEndian.PutUint64(data[cpmmCreatorFeeOnInputOffset-8:], 300_000)
+			data[cpmmCreatorFeeOnInputOffset] = 0
+			if onInput {
+				data[cpmmCreatorFeeOnInputOffset] = 1
+			}
+			return data
+		})
+		swaps, swapInfo := parseSwapInfo(t, ctx)
+		event := swaps[0].Data.(*solanaswapgo.RaydiumCPMMSwapEvent)
+		if event.CreatorFee != 300_000 || event.CreatorFeeOnInput != onInput {
+			t.Fatalf("创作者费用解析错误: %+v", event)
+		}
+
+		// 以输出代币计的创作者费用只在事件上，不计入以输入代币计的 SwapInfo.Fees
+		if onInput {
+			if event.InputCreatorFee() != 300_000 || event.OutputCreatorFee() != 0 || swapInfo.Fees.CreatorFee != 300_000 {
+				t.Errorf("以输入代币计的创作者费用不正确: %+v %+v", event, swapInfo.Fees)
+			}
+		} else if event.InputCreatorFee() != 0 || event.OutputCreatorFee() != 300_000 || swapInfo.Fees.CreatorFee != 0 {
+			t.Errorf("以输出代币计的创作者费用不正确: %+v %+v", event, swapInfo.Fees)
+		}
+	}
+}
+
+// TestRaydiumCPMMMainnetLayouts 列出还没有录制主网交易的 CPMM SwapEvent 版本，已录制的交易由 TestMainnetDecoders 对照链上余额检查
+func TestRaydiumCPMMMainnetLayouts(t *testing.T) {
+	skipMissingMainnetLayouts(t, "raydium", "RaydiumCPMMSwapEvent v1", "RaydiumCPMMSwapEvent v2")
+}
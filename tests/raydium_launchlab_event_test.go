@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+const (
+	launchLabBuySignature  = "iUSCsftgaqEYTVpDe4m25AQhqz9XonmDzNo3FUrKg37GanmgG2hWCqz3jEat4wkfJ4trGAfiycjxoZX7r5MvqAp"
+	launchLabSellSignature = "4d2NUMhHUMjkRvEaznWD6NVWvXUYPvWcCVnsF8FNMfqzg3ZRfKJNqXjHAwE8Yz5HLTTNf7sELji2KBFn8xDXQz7K"
+)
+
+// TradeEvent 中 creator_fee 的偏移和 V2 事件的长度，包括 16 字节判别器
+const (
+	launchLabCreatorFeeOffset = 16 + 32 + 11*8
+	launchLabTradeEventV2Size = launchLabCreatorFeeOffset + 2*8 + 3
+)
+
+// launchLabTradeEvent 解析交易并返回其中唯一的 LaunchLab TradeEvent
+func launchLabTradeEvent(t *testing.T, ctx corpusTx) (*solanaswapgo.RaydiumLaunchLabTradeEvent, []solanaswapgo.SwapData, *solanaswapgo.SwapInfo) {
+	t.Helper()
+	swaps, swapInfo := parseSwapInfo(t, ctx)
+	if len(swaps) != 1 || swaps[0].Type != solanaswapgo.RAYDIUM_LAUNCHLAB {
+		t.Fatalf("应解析出 1 个 LaunchLab 交易: %+v", swaps)
+	}
+	event, ok := swaps[0].Data.(*solanaswapgo.RaydiumLaunchLabTradeEvent)
+	if !ok {
+		t.Fatalf("应解析出 RaydiumLaunchLabTradeEvent: %T", swaps[0].Data)
+	}
+	return event, swaps, swapInfo
+}
+
+func TestLaunchLabTradeEventBuy(t *testing.T) {
+	ctx := loadCorpusTx(t, "raydium_launchlab", launchLabBuySignature)
+	event, swaps, swapInfo := launchLabTradeEvent(t, ctx)
+
+	trade := ctx.tx.Message.Instructions[2]
+	keys := ctx.tx.Message.AccountKeys
+	if event.Version != solanaswapgo.RaydiumLaunchLabTradeEventV2 || event.PoolState != keys[trade.Accounts[4]] || event.User != keys[trade.Accounts[0]] {
+		t.Errorf("版本、池子或用户不正确: %+v", event)
+	}
+	if !event.IsBuy() || !event.ExactIn || event.PoolStatus != solanaswapgo.RaydiumLaunchLabPoolFund ||
+		event.MinimumAmountOut != binary.LittleEndian.Uint64(trade.Data[16:24]) || event.MaximumAmountIn != 0 {
+		t.Errorf("方向、池子状态或指令参数不正确: %+v", event)
+	}
+	if event.BaseDecimals != 6 || event.QuoteMint != solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID || event.QuoteDecimals != 9 {
+		t.Errorf("代币不正确: %+v", event)
+	}
+
+	// 买入的费用从输入中扣除，其余进入池子
+	fees := event.ProtocolFee + event.PlatformFee + event.CreatorFee + event.ShareFee
+	if event.ProtocolFee == 0 || event.PlatformFee == 0 || event.RealQuoteAfter-event.RealQuoteBefore != event.AmountIn-fees ||
+		event.RealBaseAfter-event.RealBaseBefore != event.AmountOut {
+		t.Errorf("储备变化与数量和费用不一致: %+v", event)
+	}
+
+	if swapInfo.TokenInMint != event.QuoteMint || swapInfo.TokenInAmount != 1_000_000_000 ||
+		swapInfo.TokenOutMint != event.BaseMint || swapInfo.TokenOutAmount != event.AmountOut || swapInfo.TokenOutDecimals != 6 {
+		t.Errorf("SwapInfo 应使用 TradeEvent 中的数量: %+v", swapInfo)
+	}
+	if swaps[0].Source() != solanaswapgo.SwapSourceEvent {
+		t.Errorf("解析路径应为 event: %s", swaps[0].Source())
+	}
+
+	leg := solanaswapgo.NewLegs(solanaswapgo.BlockSwap{Swaps: swaps, SwapInfo: swapInfo})[0]
+	if leg.Pool != event.PoolState.String() || leg.Trader != event.User.String() || leg.OutputAmount != event.AmountOut {
+		t.Errorf("交换腿应包含池子和实际数量: %+v", leg)
+	}
+}
+
+func TestLaunchLabTradeEventSell(t *testing.T) {
+	ctx := loadCorpusTx(t, "raydium_launchlab", launchLabSellSignature)
+	event, _, swapInfo := launchLabTradeEvent(t, ctx)
+	if event.IsBuy() || event.AmountIn != 3_000_000_000_000 || event.RealBaseBefore-event.RealBaseAfter != event.AmountIn {
+		t.Errorf("卖出的方向或数量不正确: %+v", event)
+	}
+	// 卖出的费用已从输出中扣除
+	if gross := event.RealQuoteBefore - event.RealQuoteAfter; gross != event.AmountOut+event.ProtocolFee+event.PlatformFee+event.CreatorFee+event.ShareFee {
+		t.Errorf("输出与费用之和应等于池子付出的 %d: %+v", gross, event)
+	}
+	if swapInfo.TokenInMint != event.BaseMint || swapInfo.TokenInDecimals != 6 ||
+		swapInfo.TokenOutMint != event.QuoteMint || swapInfo.TokenOutAmount != event.AmountOut || swapInfo.TokenOutDecimals != 9 {
+		t.Errorf("SwapInfo 不正确: %+v", swapInfo)
+	}
+}
+
+func TestLaunchLabTradeEventLayouts(t *testing.T) {
+	// V1 事件没有 creator_fee 和 exact_in
+	ctx := loadCorpusTx(t, "raydium_launchlab", launchLabSellSignature)
+	want, _, _ := launchLabTradeEvent(t, ctx)
+	editInnerInstructions(ctx, solanaswapgo.RaydiumLaunchLabTradeEventDiscriminator[:], func(data []byte) ([]byte, bool) {
+		v1 := append([]byte{}, data[:launchLabCreatorFeeOffset]...)
+		return append(v1, data[launchLabCreatorFeeOffset+8:launchLabTradeEventV2Size-1]...), true
+	})
+	event, _, _ := launchLabTradeEvent(t, ctx)
+	if event.Version != solanaswapgo.RaydiumLaunchLabTradeEventV1 || event.CreatorFee != 0 || !event.ExactIn ||
+		event.ShareFee != want.ShareFee || event.AmountOut != want.AmountOut || event.PoolStatus != want.PoolStatus {
+		t.Errorf("应解析出 V1 事件: %+v", event)
+	}
+
+	// buy_exact_out 的参数为输出数量和最多输入
+	ctx = loadCorpusTx(t, "raydium_launchlab", launchLabBuySignature)
+	trade := ctx.tx.Message.Instructions[2]
+	copy(trade.Data, solanaswapgo.RaydiumLaunchLabBuyExactOutDiscriminator[:])
+	binary.LittleEndian.PutUint64(trade.Data[8:], 26_078_019_875_394)
+	binary.LittleEndian.PutUint64(trade.Data[16:], 1_010_000_000)
+	editInnerInstructions(ctx, solanaswapgo.RaydiumLaunchLabTradeEventDiscriminator[:], func(data []byte) ([]byte, bool) {
+		data[launchLabTradeEventV2Size-1] = 0
+		return data, true
+	})
+	event, _, swapInfo := launchLabTradeEvent(t, ctx)
+	if event.ExactIn || event.MaximumAmountIn != 1_010_000_000 || event.MinimumAmountOut != 0 || swapInfo.TokenInAmount != 1_000_000_000 {
+		t.Errorf("buy_exact_out 解析错误: %+v", event)
+	}
+}
+
+func TestLaunchLabTradeEventFallback(t *testing.T) {
+	// TradeEvent 与指令方向不一致时，由指令参数和转账补全
+	ctx := loadCorpusTx(t, "raydium_launchlab", launchLabBuySignature)
+	copy(ctx.tx.Message.Instructions[2].Data, solanaswapgo.RaydiumLaunchLabBuyExactOutDiscriminator[:])
+	event, swaps, _ := launchLabTradeEvent(t, ctx)
+	if event.Version != 0 || swaps[0].Source() != solanaswapgo.SwapSourceInstruction {
+		t.Errorf("exact_in 不一致时不应使用 TradeEvent: %+v", event)
+	}
+
+	// 没有 TradeEvent 时数量和精度取自转账
+	ctx = loadCorpusTx(t, "raydium_launchlab", launchLabSellSignature)
+	_, want := parseSwapInfo(t, ctx)
+	editInnerInstructions(ctx, solanaswapgo.RaydiumLaunchLabTradeEventDiscriminator[:], func([]byte) ([]byte, bool) { return nil, false })
+	event, _, swapInfo := launchLabTradeEvent(t, ctx)
+	if event.Version != 0 || event.PoolState.IsZero() || event.BaseDecimals != 6 {
+		t.Errorf("没有 TradeEvent 时应由指令解析: %+v", event)
+	}
+	if swapInfo.TokenInMint != want.TokenInMint || swapInfo.TokenInAmount != want.TokenInAmount ||
+		swapInfo.TokenOutMint != want.TokenOutMint || swapInfo.TokenOutAmount != want.TokenOutAmount {
+		t.Errorf("按转账补全的结果应与 TradeEvent 一致: %+v %+v", swapInfo, want)
+	}
+}
+
+func TestLaunchLabTradeThroughRouter(t *testing.T) {
+	want, _, _ := launchLabTradeEvent(t, loadCorpusTx(t, "raydium_launchlab", launchLabBuySignature))
+
+	// 交易机器人的路由指令通过 CPI 连续调用两次 LaunchLab，每次交易之后是它自己的 TradeEvent 和转账
+	ctx := loadCorpusTx(t, "raydium_launchlab", launchLabBuySignature)
+	message := &ctx.tx.Message
+	message.AccountKeys = append(message.AccountKeys, solanaswapgo.BANANA_GUN_PROGRAM_ID)
+	trade := message.Instructions[2]
+	message.Instructions[2] = solana.CompiledInstruction{ProgramIDIndex: uint16(len(message.AccountKeys) - 1), Accounts: trade.Accounts}
+	for i, set := range ctx.result.Meta.InnerInstructions {
+		if set.Index != 2 {
+			continue
+		}
+		calls := append([]solana.CompiledInstruction{trade}, set.Instructions...)
+		ctx.result.Meta.InnerInstructions[i].Instructions = append(calls, calls...)
+	}
+
+	swaps, swapInfo := parseSwapInfo(t, ctx)
+	if len(swaps) != 2 {
+		t.Fatalf("应解析出路由中的 2 个 LaunchLab 交易: %+v", swaps)
+	}
+	for _, swap := range swaps {
+		event, ok := swap.Data.(*solanaswapgo.RaydiumLaunchLabTradeEvent)
+		if !ok || swap.Type != solanaswapgo.RAYDIUM_LAUNCHLAB || event.Version != want.Version || event.PoolState != want.PoolState ||
+			event.AmountIn != want.AmountIn || event.AmountOut != want.AmountOut || event.PlatformFee != want.PlatformFee || event.Platform != want.Platform {
+			t.Errorf("路由中的交易应与直接调用一致: %+v", swap.Data)
+		}
+	}
+	if swapInfo.AMMs[0] != string(solanaswapgo.RAYDIUM_LAUNCHLAB) || swapInfo.TokenInMint != want.QuoteMint || swapInfo.TokenOutMint != want.BaseMint {
+		t.Errorf("SwapInfo 应来自 LaunchLab 交易: %+v", swapInfo)
+	}
+}
+
+// TestRaydiumLaunchLabMainnetLayouts 列出还没有录制主网交易的 LaunchLab TradeEvent 版本和交易指令，已录制的交易由 TestMainnetDecoders 对照链上余额检查
+func TestRaydiumLaunchLabMainnetLayouts(t *testing.T) {
+	skipMissingMainnetLayouts(t, "raydium_launchlab",
+		"RaydiumLaunchLabTradeEvent v1", "RaydiumLaunchLabTradeEvent v2",
+		"RaydiumLaunchLabTradeEvent buy exact_in", "RaydiumLaunchLabTradeEvent buy exact_out",
+		"RaydiumLaunchLabTradeEvent sell exact_in", "RaydiumLaunchLabTradeEvent sell exact_out",
+	)
+}
@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+)
+
+const raydiumV4Signature = "3pQQv4nW3HqY5PBRnNhpTcTsqQzfUYKmuga7nq7pHTWJjsuPpRRcWdnRbjAt4qhVQ22dSqqx3VeoQUBggSBduega"
+
+// rayLogLine 按 ray_log 的格式编码日志类型和之后的 u64 字段
+func rayLogLine(logType uint8, fields ...uint64) string {
+	data := []byte{logType}
+	for _, field := range fields {
+		data = binary.LittleEndian.AppendUint64(data, field)
+	}
+	return "Program log: ray_log: " + base64.StdEncoding.EncodeToString(data)
+}
+
+// editLogs 对交易中以 prefix 开头的日志行调用 edit，edit 返回 false 时删除该行
+func editLogs(ctx corpusTx, prefix string, edit func(line string) (string, bool)) {
+	logs := ctx.result.Meta.LogMessages[:0]
+	for _, line := range ctx.result.Meta.LogMessages {
+		if strings.HasPrefix(line, prefix) {
+			edited, keep := edit(line)
+			if !keep {
+				continue
+			}
+			line = edited
+		}
+		logs = append(logs, line)
+	}
+	ctx.result.Meta.LogMessages = logs
+}
+
+// setRayLog 替换交易中的 ray_log 日志行，line 为空时删除
+func setRayLog(ctx corpusTx, line string) {
+	editLogs(ctx, "Program log: ray_log: ", func(string) (string, bool) {
+		return line, line != ""
+	})
+}
+
+func TestRayLogSwapBaseIn(t *testing.T) {
+	ctx := loadCorpusTx(t, "raydium", raydiumV4Signature)
+	swaps, swapInfo := parseSwapInfo(t, ctx)
+	if len(swaps) != 1 || swaps[0].Type != solanaswapgo.RAYDIUM {
+		t.Fatalf("应解析出 1 个 Raydium 交换: %+v", swaps)
+	}
+	event, ok := swaps[0].Data.(*solanaswapgo.RaydiumV4SwapEvent)
+	if !ok {
+		t.Fatalf("应由 ray_log 解析出 RaydiumV4SwapEvent: %T", swaps[0].Data)
+	}
+
+	swap := ctx.tx.Message.Instructions[2]
+	if event.Amm != ctx.tx.Message.AccountKeys[swap.Accounts[1]] || event.User != ctx.tx.Message.AccountKeys[swap.Accounts[17]] {
+		t.Errorf("AMM 或用户不正确: %+v", event)
+	}
+	if !event.BaseIn || event.Direction != solanaswapgo.RaydiumV4PcToCoin || event.AmountIn != 1_000_000_000 ||
+		event.MinimumAmountOut != binary.LittleEndian.Uint64(swap.Data[9:17]) || event.MaxAmountIn != 0 {
+		t.Errorf("指令参数或方向不正确: %+v", event)
+	}
+	if event.TokenInMint != solanaswapgo.NATIVE_SOL_MINT_PROGRAM_ID || event.TokenInDecimals != 9 || event.TokenOutDecimals != 6 {
+		t.Errorf("代币不正确: %+v", event)
+	}
+
+	// 输出数量满足扣除 0.25% 手续费后的恒定乘积公式
+	inAfterFee := event.AmountIn * 9975 / 10000
+	want := uint64(float64(event.PoolCoinAmount) * float64(inAfterFee) / float64(event.PoolPcAmount+inAfterFee))
+	if event.PoolCoinAmount != 500_000_000_000_000 || event.PoolPcAmount != 2_000_000_000_000 || event.AmountOut > want || want-event.AmountOut > 1 {
+		t.Errorf("池子储备或输出数量不正确，期望输出约 %d: %+v", want, event)
+	}
+
+	if swapInfo.TokenInMint != event.TokenInMint || swapInfo.TokenInAmount != event.AmountIn ||
+		swapInfo.TokenOutMint != event.TokenOutMint || swapInfo.TokenOutAmount != event.AmountOut || swapInfo.TokenOutDecimals != 6 {
+		t.Errorf("SwapInfo 应使用 ray_log 中的数量: %+v", swapInfo)
+	}
+
+	leg := solanaswapgo.NewLegs(solanaswapgo.BlockSwap{Swaps: swaps, SwapInfo: swapInfo})[0]
+	if leg.Pool != event.Amm.String() || leg.Trader != event.User.String() || leg.InputAmount != event.AmountIn || leg.OutputAmount != event.AmountOut {
+		t.Errorf("交换腿应包含 AMM 和实际数量: %+v", leg)
+	}
+}
+
+func TestRayLogSwapBaseOut(t *testing.T) {
+	ctx := loadCorpusTx(t, "raydium", raydiumV4Signature)
+	var (
+		amountOut uint64 = 249_250_686_220
+		maxIn     uint64 = 1_010_000_000
+		deductIn  uint64 = 1_000_000_000
+	)
+	data := binary.LittleEndian.AppendUint64([]byte{11}, maxIn)
+	ctx.tx.Message.Instructions[2].Data = binary.LittleEndian.AppendUint64(data, amountOut)
+	setRayLog(ctx, rayLogLine(4, maxIn, amountOut, 1, 3_000_000_000, 500_000_000_000_000, 2_000_000_000_000, deductIn))
+
+	swaps, swapInfo := parseSwapInfo(t, ctx)
+	event, ok := swaps[0].Data.(*solanaswapgo.RaydiumV4SwapEvent)
+	if len(swaps) != 1 || !ok {
+		t.Fatalf("应解析出 1 个 RaydiumV4SwapEvent: %+v", swaps)
+	}
+	if event.BaseIn || event.MaxAmountIn != maxIn || event.AmountIn != deductIn || event.AmountOut != amountOut || event.MinimumAmountOut != 0 {
+		t.Errorf("swapBaseOut 的数量不正确: %+v", event)
+	}
+	if swapInfo.TokenInAmount != deductIn || swapInfo.TokenOutAmount != amountOut {
+		t.Errorf("SwapInfo 应使用实际扣除的输入数量: %+v", swapInfo)
+	}
+
+	// ray_log 与指令不对应时退回到按转账解析
+	setRayLog(ctx, rayLogLine(3, deductIn, amountOut, 1, 3_000_000_000, 500_000_000_000_000, 2_000_000_000_000, amountOut))
+	swaps, _ = parseSwapInfo(t, ctx)
+	if len(swaps) != 2 {
+		t.Errorf("日志类型不匹配时应退回到 2 个转账: %+v", swaps)
+	}
+}
+
+func TestRayLogFallback(t *testing.T) {
+	ctx := loadCorpusTx(t, "raydium", raydiumV4Signature)
+	_, want := parseSwapInfo(t, ctx)
+
+	// 没有日志时（如日志被截断）按转账解析，结果不变
+	setRayLog(ctx, "")
+	swaps, swapInfo := parseSwapInfo(t, ctx)
+	if len(swaps) != 2 {
+		t.Fatalf("没有 ray_log 时应解析出 2 个转账: %+v", swaps)
+	}
+	for _, swap := range swaps {
+		if _, ok := swap.Data.(*solanaswapgo.TransferData); !ok {
+			t.Errorf("应为转账: %T", swap.Data)
+		}
+	}
+	if swapInfo.TokenInMint != want.TokenInMint || swapInfo.TokenInAmount != want.TokenInAmount ||
+		swapInfo.TokenOutMint != want.TokenOutMint || swapInfo.TokenOutAmount != want.TokenOutAmount {
+		t.Errorf("按转账解析的结果应与 ray_log 一致: %+v %+v", swapInfo, want)
+	}
+}
+
+func TestRaydiumUnknownMintTransfer(t *testing.T) {
+	// 代币未知的转账（Mint 为 "Unknown"）不能作为输入输出，也不应导致 panic
+	ctx := loadCorpusTx(t, "raydium", raydiumV4Signature)
+	setRayLog(ctx, "")
+	parser := newCorpusParser(t, ctx)
+	swaps, want := parseSwaps(t, parser)
+
+	unknown := solanaswapgo.SwapData{
+		Type: solanaswapgo.RAYDIUM,
+		Data: &solanaswapgo.TransferData{Info: solanaswapgo.TransferInfo{Amount: 1}, Type: "transfer", Mint: "Unknown"},
+	}
+	swapInfo, err := parser.ProcessSwapData(append([]solanaswapgo.SwapData{unknown}, swaps...))
+	if err != nil {
+		t.Fatalf("error processing swap data: %s", err)
+	}
+	if swapInfo.TokenInMint != want.TokenInMint || swapInfo.TokenInAmount != want.TokenInAmount ||
+		swapInfo.TokenOutMint != want.TokenOutMint || swapInfo.TokenOutAmount != want.TokenOutAmount {
+		t.Errorf("应忽略代币未知的转账: %+v %+v", swapInfo, want)
+	}
+}
+
+// TestRaydiumV4MainnetLayouts 列出还没有录制主网交易的 AMM v4 swapBaseIn 和 swapBaseOut，已录制的交易由 TestMainnetDecoders 对照链上余额检查
+func TestRaydiumV4MainnetLayouts(t *testing.T) {
+	skipMissingMainnetLayouts(t, "raydium", "RaydiumV4SwapEvent base_in", "RaydiumV4SwapEvent base_out")
+}
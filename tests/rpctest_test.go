@@ -0,0 +1,213 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/rpctest"
+)
+
+func newRPCTestServer(t *testing.T) *rpctest.Server {
+	t.Helper()
+
+	server := rpctest.NewServer()
+	t.Cleanup(server.Close)
+	if err := server.Load(syntheticDir); err != nil {
+		t.Fatalf("error loading fixtures: %s", err)
+	}
+	return server
+}
+
+func TestRPCTestGetTransaction(t *testing.T) {
+	server := newRPCTestServer(t)
+	client := server.Client()
+	maxTxVersion := uint64(0)
+
+	for _, ctx := range loadCorpus(t) {
+		expected, err := parseCorpusTx(ctx)
+		if err != nil {
+			t.Fatalf("error parsing transaction: %s", err)
+		}
+
+		// 编码为空时节点默认返回 json（solana-go 不允许显式指定 json）
+		for _, encoding := range []solana.EncodingType{"", solana.EncodingBase64, solana.EncodingBase58} {
+			tx, err := client.GetTransaction(context.Background(), ctx.tx.Signatures[0], &rpc.GetTransactionOpts{
+				Encoding:                       encoding,
+				MaxSupportedTransactionVersion: &maxTxVersion,
+			})
+			if err != nil {
+				t.Fatalf("%s %s: error getting tx: %s", ctx.signature, encoding, err)
+			}
+			if tx.Slot != ctx.result.Slot {
+				t.Errorf("%s %s: slot 应为 %d，实际 %d", ctx.signature, encoding, ctx.result.Slot, tx.Slot)
+			}
+
+			parser, err := solanaswapgo.NewTransactionParser(tx)
+			if err != nil {
+				t.Fatalf("%s %s: error creating parser: %s", ctx.signature, encoding, err)
+			}
+			_, swapInfo := parseSwaps(t, parser)
+			got, want := *swapInfo, *expected
+			got.Timestamp, want.Timestamp = time.Time{}, time.Time{}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s %s: 经 RPC 拉取的解析结果不一致\n got: %+v\nwant: %+v", ctx.signature, encoding, got, want)
+			}
+		}
+
+		// 与节点一致：版本化交易需要 maxSupportedTransactionVersion
+		_, err = client.GetTransaction(context.Background(), ctx.tx.Signatures[0], nil)
+		if isVersioned := ctx.result.Version != rpc.LegacyTransactionVersion; isVersioned != (err != nil) {
+			t.Errorf("%s: 版本化=%v 时不带 maxSupportedTransactionVersion 的错误为 %v", ctx.signature, isVersioned, err)
+		}
+	}
+
+	if _, err := client.GetTransaction(context.Background(), solana.Signature{1}, nil); err != rpc.ErrNotFound {
+		t.Errorf("不存在的交易应返回 rpc.ErrNotFound，实际 %v", err)
+	}
+	if calls := server.Requests("getTransaction"); calls == 0 {
+		t.Error("应记录请求次数")
+	}
+}
+
+func TestRPCTestGetBlock(t *testing.T) {
+	corpus := loadCorpus(t)
+	server := newRPCTestServer(t)
+	server.AddBlock(280000000, corpusBlockJSON(t, corpus))
+	client := server.Client()
+	maxTxVersion := uint64(0)
+
+	block, err := client.GetBlockWithOpts(context.Background(), 280000000, &rpc.GetBlockOpts{
+		Encoding:                       solana.EncodingBase64,
+		MaxSupportedTransactionVersion: &maxTxVersion,
+	})
+	if err != nil {
+		t.Fatalf("error getting block: %s", err)
+	}
+	swaps, err := solanaswapgo.ParseBlock(280000000, block)
+	if err != nil {
+		t.Fatalf("error parsing block: %s", err)
+	}
+	if len(swaps) != len(corpus) {
+		t.Errorf("应解析出 %d 笔交换，实际 %d", len(corpus), len(swaps))
+	}
+
+	block, err = client.GetBlockWithOpts(context.Background(), 280000000, &rpc.GetBlockOpts{
+		TransactionDetails:             rpc.TransactionDetailsSignatures,
+		MaxSupportedTransactionVersion: &maxTxVersion,
+	})
+	if err != nil {
+		t.Fatalf("error getting block signatures: %s", err)
+	}
+	if len(block.Signatures) != len(corpus) || len(block.Transactions) != 0 {
+		t.Errorf("signatures 模式应只返回 %d 个签名，实际 %d 个签名、%d 笔交易", len(corpus), len(block.Signatures), len(block.Transactions))
+	}
+
+	if _, err := client.GetBlockWithOpts(context.Background(), 1, &rpc.GetBlockOpts{MaxSupportedTransactionVersion: &maxTxVersion}); err == nil {
+		t.Error("不存在的区块应返回错误")
+	}
+}
+
+func TestRPCTestGetSignaturesForAddress(t *testing.T) {
+	corpus := loadCorpus(t)
+	server := newRPCTestServer(t)
+	client := server.Client()
+	address := solana.TokenProgramID
+
+	// 预期顺序：slot 从新到旧
+	var expected []corpusTx
+	for _, ctx := range corpus {
+		keys := append(append(append(solana.PublicKeySlice{}, ctx.tx.Message.AccountKeys...), ctx.result.Meta.LoadedAddresses.Writable...), ctx.result.Meta.LoadedAddresses.ReadOnly...)
+		if keys.Contains(address) {
+			expected = append(expected, ctx)
+		}
+	}
+	sort.SliceStable(expected, func(i, j int) bool { return expected[i].result.Slot > expected[j].result.Slot })
+	if len(expected) < 2 {
+		t.Fatalf("语料中涉及 %s 的交易太少", address)
+	}
+
+	all, err := client.GetSignaturesForAddress(context.Background(), address)
+	if err != nil {
+		t.Fatalf("error getting signatures: %s", err)
+	}
+	if len(all) != len(expected) {
+		t.Fatalf("应返回 %d 个签名，实际 %d", len(expected), len(all))
+	}
+
+	// 每页 1 条，通过 before 翻页
+	var paged []*rpc.TransactionSignature
+	limit := 1
+	opts := &rpc.GetSignaturesForAddressOpts{Limit: &limit}
+	for {
+		page, err := client.GetSignaturesForAddressWithOpts(context.Background(), address, opts)
+		if err != nil {
+			t.Fatalf("error getting signatures page: %s", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		paged = append(paged, page...)
+		opts.Before = page[len(page)-1].Signature
+	}
+
+	for i, ctx := range expected {
+		if all[i].Signature.String() != ctx.signature || paged[i].Signature.String() != ctx.signature {
+			t.Errorf("第 %d 个签名应为 %s，实际 %s / %s", i, ctx.signature, all[i].Signature, paged[i].Signature)
+		}
+		if all[i].Slot != ctx.result.Slot || all[i].BlockTime == nil || all[i].Err != nil {
+			t.Errorf("%s: slot/blockTime/err 不正确: %+v", ctx.signature, all[i])
+		}
+	}
+
+	until := expected[1].tx.Signatures[0]
+	head, err := client.GetSignaturesForAddressWithOpts(context.Background(), address, &rpc.GetSignaturesForAddressOpts{Until: until})
+	if err != nil {
+		t.Fatalf("error getting signatures until: %s", err)
+	}
+	if len(head) != 1 || head[0].Signature != expected[0].tx.Signatures[0] {
+		t.Errorf("until 应在指定签名前停止: %v", head)
+	}
+}
+
+func TestRPCTestAccounts(t *testing.T) {
+	server := newRPCTestServer(t)
+	client := server.Client()
+
+	pubkey := solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
+	account := rpctest.Account{
+		Lamports: 1461600,
+		Owner:    solana.TokenProgramID,
+		Data:     []byte{1, 2, 3, 4, 5},
+	}
+	server.AddAccount(pubkey, account)
+
+	info, err := client.GetAccountInfo(context.Background(), pubkey)
+	if err != nil {
+		t.Fatalf("error getting account: %s", err)
+	}
+	if info.Value.Lamports != account.Lamports || !info.Value.Owner.Equals(account.Owner) || !bytes.Equal(info.Value.Data.GetBinary(), account.Data) {
+		t.Errorf("账户内容不正确: %+v", info.Value)
+	}
+
+	if _, err := client.GetAccountInfo(context.Background(), solana.SystemProgramID); err != rpc.ErrNotFound {
+		t.Errorf("不存在的账户应返回 rpc.ErrNotFound，实际 %v", err)
+	}
+
+	multiple, err := client.GetMultipleAccounts(context.Background(), pubkey, solana.SystemProgramID)
+	if err != nil {
+		t.Fatalf("error getting multiple accounts: %s", err)
+	}
+	if len(multiple.Value) != 2 || multiple.Value[0] == nil || multiple.Value[1] != nil {
+		t.Fatalf("应返回一个账户和一个 null: %+v", multiple.Value)
+	}
+	if !bytes.Equal(multiple.Value[0].Data.GetBinary(), account.Data) {
+		t.Errorf("账户数据不正确: %v", multiple.Value[0].Data.GetBinary())
+	}
+}
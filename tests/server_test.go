@@ -0,0 +1,285 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/metrics"
+	"github.com/zzispp/solana-dex-parse/server"
+)
+
+type serverEnvelope struct {
+	Version string          `json:"version"`
+	Data    json.RawMessage `json:"data"`
+	Error   *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func newParseServer(t *testing.T, config server.Config) *httptest.Server {
+	t.Helper()
+
+	config.Log = logrus.New()
+	config.Log.SetOutput(io.Discard)
+	ts := httptest.NewServer(server.New(config))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func doServerRequest(t *testing.T, method string, url string, body []byte) (int, serverEnvelope) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("error creating request: %s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("error sending request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	var envelope serverEnvelope
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		t.Fatalf("%s %s: 响应应为 JSON: %s", method, url, err)
+	}
+	if envelope.Version != server.APIVersion {
+		t.Errorf("%s %s: 响应版本应为 %s，实际 %q", method, url, server.APIVersion, envelope.Version)
+	}
+	return resp.StatusCode, envelope
+}
+
+func TestParseServer(t *testing.T) {
+	corpus := loadCorpus(t)
+	rpcServer := newRPCTestServer(t)
+	// 与 dexparse-server 相同，解析器的指标注册到服务的 Registry 中
+	collector := metrics.NewCollector()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	solanaswapgo.SetObserver(collector)
+	defer solanaswapgo.SetObserver(nil)
+	ts := newParseServer(t, server.Config{RPC: rpcServer.Client(), MaxBodyBytes: 1 << 20, Registry: registry})
+
+	checkTransaction := func(t *testing.T, ctx corpusTx, envelope serverEnvelope) {
+		t.Helper()
+		var response server.TransactionResponse
+		if err := json.Unmarshal(envelope.Data, &response); err != nil {
+			t.Fatalf("%s: error decoding response: %s", ctx.signature, err)
+		}
+		if response.Signature != ctx.signature || response.Slot != ctx.result.Slot {
+			t.Errorf("%s: 签名或 slot 不正确: %s %d", ctx.signature, response.Signature, response.Slot)
+		}
+		if response.SwapInfo == nil || len(response.Swaps) == 0 {
+			t.Fatalf("%s: 应解析出交换", ctx.signature)
+		}
+
+		expected, err := parseCorpusTx(ctx)
+		if err != nil {
+			t.Fatalf("error parsing transaction: %s", err)
+		}
+		got, want := *response.SwapInfo, *expected
+		got.Timestamp, want.Timestamp = time.Time{}, time.Time{}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: 服务返回的解析结果不一致\n got: %+v\nwant: %+v", ctx.signature, got, want)
+		}
+	}
+
+	t.Run("by signature", func(t *testing.T) {
+		for _, ctx := range corpus {
+			status, envelope := doServerRequest(t, http.MethodGet, ts.URL+"/v1/transactions/"+ctx.signature, nil)
+			if status != http.StatusOK {
+				t.Fatalf("%s: 状态码应为 200，实际 %d: %+v", ctx.signature, status, envelope.Error)
+			}
+			checkTransaction(t, ctx, envelope)
+		}
+
+		status, envelope := doServerRequest(t, http.MethodGet, ts.URL+"/v1/transactions/"+strings.Repeat("1", 64), nil)
+		if status != http.StatusNotFound || envelope.Error == nil || envelope.Error.Code != "not_found" {
+			t.Errorf("不存在的交易应返回 404 not_found，实际 %d %+v", status, envelope.Error)
+		}
+		status, _ = doServerRequest(t, http.MethodGet, ts.URL+"/v1/transactions/invalid", nil)
+		if status != http.StatusBadRequest {
+			t.Errorf("无效签名应返回 400，实际 %d", status)
+		}
+	})
+
+	t.Run("raw transaction", func(t *testing.T) {
+		for _, ctx := range corpus {
+			status, envelope := doServerRequest(t, http.MethodPost, ts.URL+"/v1/transactions", ctx.raw)
+			if status != http.StatusOK {
+				t.Fatalf("%s: 状态码应为 200，实际 %d: %+v", ctx.signature, status, envelope.Error)
+			}
+			checkTransaction(t, ctx, envelope)
+		}
+
+		status, envelope := doServerRequest(t, http.MethodPost, ts.URL+"/v1/transactions", []byte(`{"transaction":{}}`))
+		if status != http.StatusBadRequest || envelope.Error == nil {
+			t.Errorf("缺少 meta 应返回 400，实际 %d", status)
+		}
+
+		// 失败的交易与按签名解析一样被跳过，签名取自交易本身
+		var failed map[string]interface{}
+		if err := json.Unmarshal(corpus[0].raw, &failed); err != nil {
+			t.Fatalf("error decoding transaction: %s", err)
+		}
+		failed["meta"].(map[string]interface{})["err"] = map[string]interface{}{"InstructionError": []interface{}{0, "Custom"}}
+		raw, err := json.Marshal(failed)
+		if err != nil {
+			t.Fatalf("error encoding transaction: %s", err)
+		}
+		migrate := loadCorpusTx(t, filepath.Join("events", "pumpfun"), pumpfunMigrateSignature)
+		for _, tc := range []struct {
+			signature string
+			body      []byte
+		}{
+			{corpus[0].signature, raw},
+			{migrate.signature, migrate.raw},
+		} {
+			status, envelope := doServerRequest(t, http.MethodPost, ts.URL+"/v1/transactions", tc.body)
+			if status != http.StatusOK {
+				t.Fatalf("%s: 状态码应为 200，实际 %d: %+v", tc.signature, status, envelope.Error)
+			}
+			var response server.TransactionResponse
+			if err := json.Unmarshal(envelope.Data, &response); err != nil {
+				t.Fatalf("error decoding response: %s", err)
+			}
+			if response.Signature != tc.signature || response.SwapInfo != nil || len(response.Swaps) != 0 {
+				t.Errorf("%s: 失败或不含交换的交易应只返回签名: %+v", tc.signature, response)
+			}
+		}
+	})
+
+	t.Run("block", func(t *testing.T) {
+		status, envelope := doServerRequest(t, http.MethodPost, ts.URL+"/v1/blocks?slot=280000000", corpusBlockJSON(t, corpus))
+		if status != http.StatusOK {
+			t.Fatalf("状态码应为 200，实际 %d: %+v", status, envelope.Error)
+		}
+		var response server.BlockResponse
+		if err := json.Unmarshal(envelope.Data, &response); err != nil {
+			t.Fatalf("error decoding response: %s", err)
+		}
+		if response.Slot != 280000000 || len(response.Transactions) != len(corpus) || len(response.Errors) != 0 {
+			t.Fatalf("应解析出 %d 笔交易，实际 %d，错误 %v", len(corpus), len(response.Transactions), response.Errors)
+		}
+		for i, tx := range response.Transactions {
+			if tx.TransactionIndex != i || tx.Signature != corpus[i].signature || tx.Slot != 280000000 {
+				t.Errorf("第 %d 笔交易的位置或签名不正确: %d %s", i, tx.TransactionIndex, tx.Signature)
+			}
+		}
+
+		if status, _ := doServerRequest(t, http.MethodPost, ts.URL+"/v1/blocks", corpusBlockJSON(t, corpus)); status != http.StatusBadRequest {
+			t.Errorf("缺少 slot 应返回 400，实际 %d", status)
+		}
+	})
+
+	t.Run("body limit", func(t *testing.T) {
+		body := append([]byte(`{"meta":{},"pad":"`), bytes.Repeat([]byte("a"), 2<<20)...)
+		status, envelope := doServerRequest(t, http.MethodPost, ts.URL+"/v1/transactions", append(body, `"}`...))
+		if status != http.StatusRequestEntityTooLarge || envelope.Error == nil || envelope.Error.Code != "body_too_large" {
+			t.Errorf("超出大小限制应返回 413，实际 %d %+v", status, envelope.Error)
+		}
+	})
+
+	t.Run("health", func(t *testing.T) {
+		for _, path := range []string{"/healthz", "/readyz"} {
+			if status, envelope := doServerRequest(t, http.MethodGet, ts.URL+path, nil); status != http.StatusOK {
+				t.Errorf("%s 应返回 200，实际 %d %+v", path, status, envelope.Error)
+			}
+		}
+		if status, _ := doServerRequest(t, http.MethodGet, ts.URL+"/v2/unknown", nil); status != http.StatusNotFound {
+			t.Errorf("未知端点应返回 404，实际 %d", status)
+		}
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/metrics")
+		if err != nil {
+			t.Fatalf("error getting metrics: %s", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+
+		swaps := make(map[solanaswapgo.SwapType]bool)
+		for _, ctx := range corpus {
+			expected, _ := parseCorpusTx(ctx)
+			for _, amm := range expected.AMMs {
+				swaps[solanaswapgo.SwapType(amm)] = true
+			}
+		}
+		for protocol := range swaps {
+			if !strings.Contains(string(body), `dexparse_swaps_total{protocol="`+string(protocol)+`"}`) {
+				t.Errorf("指标中应包含协议 %s:\n%s", protocol, body)
+			}
+		}
+		for _, want := range []string{
+			`dexparse_http_requests_total{code="200",endpoint="transaction"} ` + strconv.Itoa(len(corpus)),
+			`dexparse_http_requests_total{code="404",endpoint="transaction"} 1`,
+			`dexparse_http_requests_total{code="413",endpoint="transaction_raw"} 1`,
+			`dexparse_http_request_duration_seconds_count{endpoint="block"} `,
+			// 每笔交易按签名、请求体和区块各解析一次，另有两笔不含交换的请求体
+			`dexparse_transactions_parsed_total ` + strconv.Itoa(3*len(corpus)+2),
+			`dexparse_swap_transactions_total ` + strconv.Itoa(3*len(corpus)),
+			// 解析器的指标还包含测试自身计算期望值时的解析，只检查存在
+			`dexparse_parser_transactions_total `,
+			`dexparse_parser_swaps_total{protocol="PumpFun",source="event"} `,
+		} {
+			if !strings.Contains(string(body), want) {
+				t.Errorf("指标中应包含 %q:\n%s", want, body)
+			}
+		}
+	})
+}
+
+func TestParseServerLimits(t *testing.T) {
+	ts := newParseServer(t, server.Config{})
+
+	ctx := loadCorpus(t)[0]
+	status, envelope := doServerRequest(t, http.MethodGet, ts.URL+"/v1/transactions/"+ctx.signature, nil)
+	if status != http.StatusServiceUnavailable || envelope.Error == nil || envelope.Error.Code != "rpc_not_configured" {
+		t.Errorf("未配置 RPC 时应返回 503 rpc_not_configured，实际 %d %+v", status, envelope.Error)
+	}
+
+	// 并发上限为 1 时，处理中的请求会让后续请求立即返回 503
+	blocked := make(chan struct{})
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(blocked)
+		<-release
+	}))
+	defer slow.Close()
+	limited := newParseServer(t, server.Config{RPC: rpc.New(slow.URL), MaxConcurrent: 1, RequestTimeout: 5 * time.Second})
+
+	done := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(limited.URL + "/v1/transactions/" + ctx.signature)
+		if err != nil {
+			done <- 0
+			return
+		}
+		resp.Body.Close()
+		done <- resp.StatusCode
+	}()
+	<-blocked
+	status, envelope = doServerRequest(t, http.MethodGet, limited.URL+"/v1/transactions/"+ctx.signature, nil)
+	if status != http.StatusServiceUnavailable || envelope.Error == nil || envelope.Error.Code != "overloaded" {
+		t.Errorf("超出并发上限应返回 503 overloaded，实际 %d %+v", status, envelope.Error)
+	}
+	close(release)
+	if status := <-done; status != http.StatusBadGateway {
+		t.Errorf("RPC 返回空响应时应为 502，实际 %d", status)
+	}
+}
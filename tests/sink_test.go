@@ -0,0 +1,252 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/sink"
+)
+
+// corpusSwaps 将语料解析为 BlockSwap
+func corpusSwaps(t *testing.T, corpus []corpusTx) []solanaswapgo.BlockSwap {
+	t.Helper()
+
+	swaps := make([]solanaswapgo.BlockSwap, 0, len(corpus))
+	for _, ctx := range corpus {
+		swap, err := solanaswapgo.ParseTransactionResult(ctx.result)
+		if err != nil || swap == nil {
+			t.Fatalf("%s: error parsing transaction: %v", ctx.signature, err)
+		}
+		swaps = append(swaps, *swap)
+	}
+	return swaps
+}
+
+func TestSinkRow(t *testing.T) {
+	corpus := loadCorpus(t)
+	withFees := 0
+	for i, swap := range corpusSwaps(t, corpus) {
+		ctx := corpus[i]
+		row := sink.NewRow(swap)
+		info := swap.SwapInfo
+
+		if row.Signature != ctx.signature || row.Slot != ctx.result.Slot || row.BlockTime != int64(*ctx.result.BlockTime) {
+			t.Errorf("%s: 签名、slot 或时间不正确: %+v", ctx.signature, row)
+		}
+		if row.Fee != ctx.result.Meta.Fee || row.Fee == 0 {
+			t.Errorf("%s: 手续费应为 %d，实际 %d", ctx.signature, ctx.result.Meta.Fee, row.Fee)
+		}
+		if row.Trader != info.Signers[0].String() || row.Protocol != strings.Join(info.AMMs, ",") || row.Legs != len(swap.Swaps) {
+			t.Errorf("%s: trader、protocol 或 legs 不正确: %+v", ctx.signature, row)
+		}
+		if row.TokenInMint != info.TokenInMint.String() || row.TokenInAmount != info.TokenInAmount || row.TokenOutAmount != info.TokenOutAmount {
+			t.Errorf("%s: 数量或代币不正确: %+v", ctx.signature, row)
+		}
+		if row.TokenOutUIAmount != solanaswapgo.FormatAmount(info.TokenOutAmount, info.TokenOutDecimals) {
+			t.Errorf("%s: UI 数量不正确: %s", ctx.signature, row.TokenOutUIAmount)
+		}
+		if len(row.Values()) != len(sink.Columns) {
+			t.Fatalf("Values 应有 %d 列，实际 %d", len(sink.Columns), len(row.Values()))
+		}
+
+		// 交换费用与 SwapInfo.Fees 一致，没有费用时为空
+		if fees := info.Fees; fees != nil {
+			withFees++
+			if row.FeeMint != fees.Mint.String() || row.FeeDecimals != int(fees.Decimals) || row.ProtocolFee != fees.ProtocolFee ||
+				row.CreatorFee != fees.CreatorFee || row.LPFee != fees.LPFee || row.PlatformFee != fees.PlatformFee || row.TradeFee != fees.TradeFee {
+				t.Errorf("%s: 交换费用与 SwapInfo 不一致: %+v %+v", ctx.signature, row, fees)
+			}
+		} else if row.FeeMint != "" || row.ProtocolFee != 0 || row.CreatorFee != 0 || row.LPFee != 0 || row.PlatformFee != 0 || row.TradeFee != 0 {
+			t.Errorf("%s: 没有 SwapInfo.Fees 时交换费用应为空: %+v", ctx.signature, row)
+		}
+	}
+	if withFees == 0 {
+		t.Error("语料中应有带交换费用的交易")
+	}
+
+	for _, c := range []struct {
+		amount   uint64
+		decimals uint8
+		want     string
+	}{
+		{1500000000, 9, "1.5"},
+		{1, 6, "0.000001"},
+		{123000000, 6, "123"},
+		{18446744073709551615, 9, "18446744073.709551615"},
+		{42, 0, "42"},
+	} {
+		if got := solanaswapgo.FormatAmount(c.amount, c.decimals); got != c.want {
+			t.Errorf("FormatAmount(%d, %d) 应为 %s，实际 %s", c.amount, c.decimals, c.want, got)
+		}
+	}
+}
+
+func TestJSONLSink(t *testing.T) {
+	swaps := corpusSwaps(t, loadCorpus(t))
+
+	var buf bytes.Buffer
+	s := sink.NewJSONL(&buf)
+	for _, swap := range swaps {
+		if err := s.Write(swap); err != nil {
+			t.Fatalf("error writing swap: %s", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("error closing sink: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(swaps) {
+		t.Fatalf("应写入 %d 行，实际 %d", len(swaps), len(lines))
+	}
+	for i, line := range lines {
+		var row sink.Row
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			t.Fatalf("第 %d 行不是 JSON: %s", i+1, err)
+		}
+		if row != sink.NewRow(swaps[i]) {
+			t.Errorf("第 %d 行内容不一致:\n got: %+v\nwant: %+v", i+1, row, sink.NewRow(swaps[i]))
+		}
+	}
+}
+
+func TestCSVSink(t *testing.T) {
+	swaps := corpusSwaps(t, loadCorpus(t))
+	path := filepath.Join(t.TempDir(), "swaps.csv")
+
+	// 分两次打开，第二次追加时不应重复写表头
+	for _, part := range [][]solanaswapgo.BlockSwap{swaps[:2], swaps[2:]} {
+		s, err := sink.Open(path)
+		if err != nil {
+			t.Fatalf("error opening sink: %s", err)
+		}
+		for _, swap := range part {
+			if err := s.Write(swap); err != nil {
+				t.Fatalf("error writing swap: %s", err)
+			}
+		}
+		if err := s.Close(); err != nil {
+			t.Fatalf("error closing sink: %s", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading csv: %s", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("error parsing csv: %s", err)
+	}
+	if len(records) != len(swaps)+1 || strings.Join(records[0], ",") != strings.Join(sink.Columns, ",") {
+		t.Fatalf("应包含一行表头和 %d 行数据，实际 %d 行: %v", len(swaps), len(records), records[0])
+	}
+	for i, record := range records[1:] {
+		if strings.Join(record, ",") != strings.Join(sink.NewRow(swaps[i]).Values(), ",") {
+			t.Errorf("第 %d 行内容不一致: %v", i+1, record)
+		}
+	}
+}
+
+func TestSQLiteSink(t *testing.T) {
+	swaps := corpusSwaps(t, loadCorpus(t))
+	path := filepath.Join(t.TempDir(), "swaps.db")
+
+	s, err := sink.OpenSQLite(path)
+	if err != nil {
+		t.Fatalf("error opening sqlite: %s", err)
+	}
+	for _, swap := range swaps {
+		if err := s.Write(swap); err != nil {
+			t.Fatalf("error writing swap: %s", err)
+		}
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatalf("error flushing sqlite: %s", err)
+	}
+	// 重复写入同一笔交易会覆盖而不是新增
+	if err := s.Write(swaps[0]); err != nil {
+		t.Fatalf("error rewriting swap: %s", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("error closing sqlite: %s", err)
+	}
+
+	s, err = sink.OpenSQLite(path)
+	if err != nil {
+		t.Fatalf("error reopening sqlite: %s", err)
+	}
+	defer s.Close()
+
+	var count int
+	if err := s.DB().QueryRow("SELECT COUNT(*) FROM swaps").Scan(&count); err != nil {
+		t.Fatalf("error counting swaps: %s", err)
+	}
+	if count != len(swaps) {
+		t.Errorf("应有 %d 行，实际 %d", len(swaps), count)
+	}
+
+	feeRows := 0
+	for _, swap := range swaps {
+		want := sink.NewRow(swap)
+		var (
+			slot, fee         int64
+			trader, protocol  string
+			inAmount, outMint string
+			outDecimals       int
+			outUIAmount       float64
+			blockTime         int64
+		)
+		err := s.DB().QueryRow(
+			"SELECT slot, block_time, trader, protocol, token_in_amount, token_out_mint, token_out_decimals, token_out_ui_amount, fee FROM swaps WHERE signature = ?",
+			want.Signature,
+		).Scan(&slot, &blockTime, &trader, &protocol, &inAmount, &outMint, &outDecimals, &outUIAmount, &fee)
+		if err != nil {
+			t.Fatalf("%s: error querying swap: %s", want.Signature, err)
+		}
+		if uint64(slot) != want.Slot || blockTime != want.BlockTime || trader != want.Trader || protocol != want.Protocol || uint64(fee) != want.Fee {
+			t.Errorf("%s: 行内容不一致", want.Signature)
+		}
+		if inAmount != solanaswapgo.FormatAmount(want.TokenInAmount, 0) || outMint != want.TokenOutMint || outDecimals != int(want.TokenOutDecimals) {
+			t.Errorf("%s: 数量或代币不一致: %s %s %d", want.Signature, inAmount, outMint, outDecimals)
+		}
+		if expected, _ := strconv.ParseFloat(want.TokenOutUIAmount, 64); outUIAmount != expected {
+			t.Errorf("%s: UI 数量应为 %v，实际 %v", want.Signature, expected, outUIAmount)
+		}
+
+		var (
+			feeMint, protocolFee, creatorFee, lpFee, platformFee, tradeFee string
+			feeDecimals                                                    int
+		)
+		err = s.DB().QueryRow(
+			"SELECT fee_mint, fee_decimals, protocol_fee, creator_fee, lp_fee, platform_fee, trade_fee FROM swaps WHERE signature = ?",
+			want.Signature,
+		).Scan(&feeMint, &feeDecimals, &protocolFee, &creatorFee, &lpFee, &platformFee, &tradeFee)
+		if err != nil {
+			t.Fatalf("%s: error querying fees: %s", want.Signature, err)
+		}
+		if feeMint != want.FeeMint || feeDecimals != want.FeeDecimals || protocolFee != strconv.FormatUint(want.ProtocolFee, 10) ||
+			creatorFee != strconv.FormatUint(want.CreatorFee, 10) || lpFee != strconv.FormatUint(want.LPFee, 10) || platformFee != strconv.FormatUint(want.PlatformFee, 10) ||
+			tradeFee != strconv.FormatUint(want.TradeFee, 10) {
+			t.Errorf("%s: 交换费用不一致: %s %d %s %s %s %s %s", want.Signature, feeMint, feeDecimals, protocolFee, creatorFee, lpFee, platformFee, tradeFee)
+		}
+		if want.FeeMint != "" {
+			feeRows++
+		}
+	}
+
+	if feeRows == 0 {
+		t.Error("语料中应有带交换费用的行")
+	}
+
+	if _, err := sink.Open(filepath.Join(t.TempDir(), "swaps.txt")); err == nil {
+		t.Error("不支持的扩展名应返回错误")
+	}
+}
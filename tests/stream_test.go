@@ -0,0 +1,365 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gorilla/websocket"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/stream"
+)
+
+// websocketStandIn 是本地的 RPC pubsub 替身：确认订阅后按连接序号执行 script
+type websocketStandIn struct {
+	subscriptions int
+
+	mu          sync.Mutex
+	connections int
+	methods     []string
+	params      []json.RawMessage
+}
+
+func startWebsocketStandIn(t *testing.T, standIn *websocketStandIn, script func(conn *websocket.Conn, connection int)) string {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		standIn.mu.Lock()
+		standIn.connections++
+		connection := standIn.connections
+		standIn.mu.Unlock()
+
+		// 逐个确认订阅请求后开始推送
+		for i := 0; i < standIn.subscriptions; i++ {
+			var req struct {
+				ID     int               `json:"id"`
+				Method string            `json:"method"`
+				Params []json.RawMessage `json:"params"`
+			}
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			standIn.mu.Lock()
+			standIn.methods = append(standIn.methods, req.Method)
+			standIn.params = append(standIn.params, req.Params...)
+			standIn.mu.Unlock()
+
+			conn.WriteJSON(map[string]interface{}{"jsonrpc": "2.0", "result": i + 1, "id": req.ID})
+		}
+		// 后台读取以处理 ping 与关闭帧
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		script(conn, connection)
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func writeNotification(conn *websocket.Conn, method string, result interface{}) {
+	conn.WriteJSON(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params": map[string]interface{}{
+			"result":       result,
+			"subscription": 1,
+		},
+	})
+}
+
+func writeLogs(conn *websocket.Conn, signature string, txErr interface{}) {
+	writeNotification(conn, "logsNotification", map[string]interface{}{
+		"context": map[string]interface{}{"slot": 280000000},
+		"value": map[string]interface{}{
+			"signature": signature,
+			"err":       txErr,
+			"logs":      []string{},
+		},
+	})
+}
+
+// corpusFetcher 从语料中返回 getTransaction 结果，并记录每个签名的请求次数
+type corpusFetcher struct {
+	mu      sync.Mutex
+	results map[solana.Signature]*rpc.GetTransactionResult
+	calls   map[solana.Signature]int
+}
+
+func newCorpusFetcher(corpus []corpusTx) *corpusFetcher {
+	fetcher := &corpusFetcher{
+		results: make(map[solana.Signature]*rpc.GetTransactionResult),
+		calls:   make(map[solana.Signature]int),
+	}
+	for _, ctx := range corpus {
+		fetcher.results[ctx.tx.Signatures[0]] = ctx.result
+	}
+	return fetcher
+}
+
+func (f *corpusFetcher) GetTransaction(_ context.Context, signature solana.Signature, _ *rpc.GetTransactionOpts) (*rpc.GetTransactionResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls[signature]++
+	result, ok := f.results[signature]
+	if !ok {
+		return nil, rpc.ErrNotFound
+	}
+	return result, nil
+}
+
+func newTestSubscriber(endpoint string, fetcher stream.TransactionFetcher) *stream.Subscriber {
+	subscriber := stream.NewSubscriber(endpoint, fetcher)
+	subscriber.ProgramIDs = []solana.PublicKey{solanaswapgo.PUMP_FUN_PROGRAM_ID, solanaswapgo.RAYDIUM_V4_PROGRAM_ID}
+	subscriber.ReconnectDelay = 10 * time.Millisecond
+	subscriber.MaxReconnectDelay = 10 * time.Millisecond
+	subscriber.FetchAttempts = 1
+	subscriber.Log.SetOutput(io.Discard)
+	return subscriber
+}
+
+// collectSwaps 读取 count 个交换后取消订阅，并确认 Run 以 ctx 错误返回
+func collectSwaps(t *testing.T, subscriber *stream.Subscriber, count int) []solanaswapgo.BlockSwap {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// 不带缓冲的 channel：订阅器只有在消费端读取后才继续处理
+	out := make(chan solanaswapgo.BlockSwap)
+	done := make(chan error, 1)
+	go func() {
+		done <- subscriber.Run(ctx, out)
+	}()
+
+	swaps := make([]solanaswapgo.BlockSwap, 0, count)
+	for len(swaps) < count {
+		select {
+		case swap := <-out:
+			swaps = append(swaps, swap)
+		case err := <-done:
+			t.Fatalf("订阅提前结束: %v", err)
+		case <-ctx.Done():
+			t.Fatalf("等待第 %d 个交换超时", len(swaps))
+		}
+	}
+
+	// 等待剩余通知处理完，确认没有重复输出
+	select {
+	case swap := <-out:
+		t.Errorf("不应输出重复或多余的交换: %s", swap.Signature)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("取消后应返回 context.Canceled，实际 %v", err)
+	}
+	return swaps
+}
+
+func TestStreamLogsReconnectAndDedupe(t *testing.T) {
+	corpus := loadCorpus(t)
+	fetcher := newCorpusFetcher(corpus)
+	half := len(corpus) / 2
+
+	standIn := &websocketStandIn{subscriptions: 2}
+	endpoint := startWebsocketStandIn(t, standIn, func(conn *websocket.Conn, connection int) {
+		if connection == 1 {
+			// 两个程序订阅命中同一交易时会收到两条通知，失败交易不应被拉取
+			writeLogs(conn, solana.Signature{1}.String(), map[string]interface{}{"InstructionError": []interface{}{0, "Custom"}})
+			for _, ctx := range corpus[:half] {
+				writeLogs(conn, ctx.signature, nil)
+				writeLogs(conn, ctx.signature, nil)
+			}
+			// 第一条连接在推送一半后断开
+			return
+		}
+
+		// 重连后节点重放已推送过的通知
+		for _, ctx := range corpus {
+			writeLogs(conn, ctx.signature, nil)
+		}
+		time.Sleep(time.Second)
+	})
+
+	swaps := collectSwaps(t, newTestSubscriber(endpoint, fetcher), len(corpus))
+	for i, swap := range swaps {
+		if swap.Signature.String() != corpus[i].signature {
+			t.Errorf("第 %d 个交换应为 %s，实际 %s", i, corpus[i].signature, swap.Signature)
+		}
+		if swap.Slot != corpus[i].result.Slot || swap.TransactionIndex != -1 || swap.SwapInfo == nil {
+			t.Errorf("%s: slot/下标/SwapInfo 不正确: %d %d %v", swap.Signature, swap.Slot, swap.TransactionIndex, swap.SwapInfo)
+		}
+	}
+
+	fetcher.mu.Lock()
+	defer fetcher.mu.Unlock()
+	for _, ctx := range corpus {
+		if calls := fetcher.calls[ctx.tx.Signatures[0]]; calls != 1 {
+			t.Errorf("%s: 应只拉取一次，实际 %d 次", ctx.signature, calls)
+		}
+	}
+	if calls := fetcher.calls[solana.Signature{1}]; calls != 0 {
+		t.Errorf("失败交易不应被拉取，实际 %d 次", calls)
+	}
+
+	standIn.mu.Lock()
+	defer standIn.mu.Unlock()
+	if standIn.connections != 2 {
+		t.Errorf("应重连一次，实际连接 %d 次", standIn.connections)
+	}
+	if len(standIn.methods) != 4 || standIn.methods[0] != "logsSubscribe" {
+		t.Errorf("每次连接应为每个程序发送 logsSubscribe: %v", standIn.methods)
+	}
+}
+
+// slowFetcher 按签名设置拉取延迟，并记录同时进行的最大请求数
+type slowFetcher struct {
+	*corpusFetcher
+	delays map[solana.Signature]time.Duration
+
+	active    int
+	maxActive int
+}
+
+func (f *slowFetcher) GetTransaction(ctx context.Context, signature solana.Signature, opts *rpc.GetTransactionOpts) (*rpc.GetTransactionResult, error) {
+	f.mu.Lock()
+	f.active++
+	if f.active > f.maxActive {
+		f.maxActive = f.active
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-time.After(f.delays[signature]):
+	case <-ctx.Done():
+	}
+
+	f.mu.Lock()
+	f.active--
+	f.mu.Unlock()
+	return f.corpusFetcher.GetTransaction(ctx, signature, opts)
+}
+
+func TestStreamLogsSlowFetcher(t *testing.T) {
+	corpus := loadCorpus(t)
+	const delay = 200 * time.Millisecond
+
+	for _, unordered := range []bool{false, true} {
+		// 越靠前的交易拉取越慢
+		fetcher := &slowFetcher{corpusFetcher: newCorpusFetcher(corpus), delays: make(map[solana.Signature]time.Duration)}
+		for i, ctx := range corpus {
+			fetcher.delays[ctx.tx.Signatures[0]] = delay + time.Duration(len(corpus)-i)*10*time.Millisecond
+		}
+
+		standIn := &websocketStandIn{subscriptions: 2}
+		endpoint := startWebsocketStandIn(t, standIn, func(conn *websocket.Conn, _ int) {
+			for _, ctx := range corpus {
+				writeLogs(conn, ctx.signature, nil)
+			}
+			time.Sleep(5 * time.Second)
+		})
+
+		subscriber := newTestSubscriber(endpoint, fetcher)
+		subscriber.FetchWorkers = 4
+		subscriber.Unordered = unordered
+
+		start := time.Now()
+		swaps := collectSwaps(t, subscriber, len(corpus))
+		// 串行拉取至少需要 len(corpus) 倍的延迟
+		if elapsed := time.Since(start); elapsed > time.Duration(len(corpus))*delay/2 {
+			t.Errorf("拉取应并发进行，耗时 %s", elapsed)
+		}
+
+		fetcher.mu.Lock()
+		if fetcher.maxActive < 2 || fetcher.maxActive > subscriber.FetchWorkers {
+			t.Errorf("同时进行的请求数应在 2 和 %d 之间，实际 %d", subscriber.FetchWorkers, fetcher.maxActive)
+		}
+		fetcher.mu.Unlock()
+
+		emitted := make(map[string]bool)
+		for _, swap := range swaps {
+			emitted[swap.Signature.String()] = true
+		}
+		for i, ctx := range corpus {
+			if !emitted[ctx.signature] {
+				t.Errorf("%s 未输出", ctx.signature)
+			}
+			if !unordered && swaps[i].Signature.String() != ctx.signature {
+				t.Errorf("有序输出的第 %d 个交换应为 %s，实际 %s", i, ctx.signature, swaps[i].Signature)
+			}
+		}
+		if unordered && swaps[0].Signature.String() == corpus[0].signature {
+			t.Errorf("无序输出时最慢的交易不应最先输出")
+		}
+	}
+}
+
+func TestStreamBlockMode(t *testing.T) {
+	corpus := loadCorpus(t)
+	block := json.RawMessage(corpusBlockJSON(t, corpus))
+
+	standIn := &websocketStandIn{subscriptions: 2}
+	endpoint := startWebsocketStandIn(t, standIn, func(conn *websocket.Conn, _ int) {
+		// 两个程序订阅都会收到同一区块
+		for i := 0; i < 2; i++ {
+			writeNotification(conn, "blockNotification", map[string]interface{}{
+				"context": map[string]interface{}{"slot": 280000000},
+				"value": map[string]interface{}{
+					"slot":  280000000,
+					"block": block,
+					"err":   nil,
+				},
+			})
+		}
+		time.Sleep(time.Second)
+	})
+
+	subscriber := newTestSubscriber(endpoint, nil)
+	subscriber.Mode = stream.ModeBlock
+
+	swaps := collectSwaps(t, subscriber, len(corpus))
+	for i, swap := range swaps {
+		if swap.Signature.String() != corpus[i].signature || swap.Slot != 280000000 || swap.TransactionIndex != i {
+			t.Errorf("第 %d 个交换不正确: %s slot %d index %d", i, swap.Signature, swap.Slot, swap.TransactionIndex)
+		}
+	}
+
+	standIn.mu.Lock()
+	defer standIn.mu.Unlock()
+	if len(standIn.methods) == 0 || standIn.methods[0] != "blockSubscribe" {
+		t.Fatalf("应发送 blockSubscribe: %v", standIn.methods)
+	}
+	if !strings.Contains(string(standIn.params[1]), `"encoding":"base64"`) || !strings.Contains(string(standIn.params[1]), `"transactionDetails":"full"`) {
+		t.Errorf("blockSubscribe 参数不正确: %s", standIn.params[1])
+	}
+}
+
+func TestStreamLogsModeRequiresFetcher(t *testing.T) {
+	subscriber := stream.NewSubscriber("ws://127.0.0.1:0", nil)
+	if err := subscriber.Run(context.Background(), make(chan solanaswapgo.BlockSwap)); err == nil {
+		t.Error("logs 模式缺少 fetcher 时应返回错误")
+	}
+}
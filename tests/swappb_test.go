@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"testing"
+
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/sink"
+	"github.com/zzispp/solana-dex-parse/swappb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSwapProto(t *testing.T) {
+	corpus := loadCorpus(t)
+	swaps := corpusSwaps(t, corpus)
+
+	for i, swap := range swaps {
+		ctx := corpus[i]
+		result := swappb.FromBlockSwap(swap)
+
+		data, err := proto.Marshal(result)
+		if err != nil {
+			t.Fatalf("%s: error marshaling: %s", ctx.signature, err)
+		}
+		var decoded swappb.ParseResult
+		if err := proto.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("%s: error unmarshaling: %s", ctx.signature, err)
+		}
+		if !proto.Equal(result, &decoded) {
+			t.Fatalf("%s: 序列化往返后内容不一致", ctx.signature)
+		}
+
+		// 与 sink 的扁平结构逐字段对照
+		row := sink.NewRow(swap)
+		tx := decoded.GetTransaction()
+		if tx.GetSignature() != row.Signature || tx.GetSlot() != row.Slot || tx.GetTransactionIndex() != int64(row.TransactionIndex) {
+			t.Errorf("%s: 交易元数据不正确: %v", ctx.signature, tx)
+		}
+		if tx.GetBlockTime().GetSeconds() != row.BlockTime || tx.GetFees().GetNetworkFee() != ctx.result.Meta.Fee {
+			t.Errorf("%s: 时间或手续费不正确: %v", ctx.signature, tx)
+		}
+
+		info := decoded.GetSwapInfo()
+		if info.GetSigners()[0] != row.Trader || len(info.GetSignatures()) != len(swap.SwapInfo.Signatures) || len(info.GetAmms()) != len(swap.SwapInfo.AMMs) {
+			t.Errorf("%s: SwapInfo 不正确: %v", ctx.signature, info)
+		}
+		in, out := info.GetTokenIn(), info.GetTokenOut()
+		if in.GetMint() != row.TokenInMint || in.GetAmount() != row.TokenInAmount || in.GetUiAmount() != row.TokenInUIAmount {
+			t.Errorf("%s: token_in 不正确: %v", ctx.signature, in)
+		}
+		if out.GetMint() != row.TokenOutMint || out.GetDecimals() != uint32(row.TokenOutDecimals) || out.GetUiAmount() != row.TokenOutUIAmount {
+			t.Errorf("%s: token_out 不正确: %v", ctx.signature, out)
+		}
+		if fees := swap.SwapInfo.Fees; (fees == nil) != (info.GetFees() == nil) ||
+			fees != nil && (info.GetFees().GetProtocolFee().GetAmount() != fees.ProtocolFee || info.GetFees().GetCreatorFee().GetAmount() != fees.CreatorFee ||
+				info.GetFees().GetTradeFee().GetAmount() != fees.TradeFee) {
+			t.Errorf("%s: 协议费用不正确: %v", ctx.signature, info.GetFees())
+		}
+
+		legs := solanaswapgo.NewLegs(swap)
+		if len(decoded.GetLegs()) != len(legs) {
+			t.Fatalf("%s: 应有 %d 个交换腿，实际 %d", ctx.signature, len(legs), len(decoded.GetLegs()))
+		}
+		for j, leg := range decoded.GetLegs() {
+			want := legs[j]
+			if leg.GetIndex() != uint32(j) || leg.GetProtocol() != want.Protocol || leg.GetDataType() != want.DataType || leg.GetPool() != want.Pool {
+				t.Errorf("%s: 第 %d 个交换腿不正确: %v", ctx.signature, j, leg)
+			}
+			if leg.GetInput().GetMint() != want.InputMint || leg.GetInput().GetAmount() != want.InputAmount || leg.GetInput().GetUiAmount() != want.InputUIAmount {
+				t.Errorf("%s: 第 %d 个交换腿的输入不正确: %v", ctx.signature, j, leg.GetInput())
+			}
+			if (leg.GetOutput() == nil) != (want.OutputMint == "") || leg.GetOutput().GetAmount() != want.OutputAmount {
+				t.Errorf("%s: 第 %d 个交换腿的输出不正确: %v", ctx.signature, j, leg.GetOutput())
+			}
+			if leg.GetSource() != want.Source || leg.GetDestination() != want.Destination || leg.GetAuthority() != want.Authority {
+				t.Errorf("%s: 第 %d 个交换腿的转账账户不正确: %v", ctx.signature, j, leg)
+			}
+		}
+	}
+
+	block := swappb.FromBlockSwaps(swaps[0].Slot, swaps)
+	if block.GetSlot() != swaps[0].Slot || len(block.GetTransactions()) != len(swaps) {
+		t.Errorf("BlockResult 应包含 %d 笔交易，实际 %d", len(swaps), len(block.GetTransactions()))
+	}
+	if swappb.FromSwapInfo(nil) != nil {
+		t.Error("nil SwapInfo 应转换为 nil")
+	}
+}
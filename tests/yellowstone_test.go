@@ -0,0 +1,290 @@
+package tests
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"github.com/zzispp/solana-dex-parse/yellowstone"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// geyserStandIn 是进程内的 Geyser 服务替身，按顺序推送预设的更新
+type geyserStandIn struct {
+	updates  []*yellowstone.SubscribeUpdate
+	requests chan *yellowstone.SubscribeRequest
+	tokens   chan string
+}
+
+func (g *geyserStandIn) subscribe(_ interface{}, stream grpc.ServerStream) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	g.tokens <- firstOrEmpty(md.Get("x-token"))
+
+	// 后台持续接收请求（订阅请求与 ping 回复）
+	go func() {
+		for {
+			var req yellowstone.SubscribeRequest
+			if err := stream.RecvMsg(&req); err != nil {
+				return
+			}
+			g.requests <- &req
+		}
+	}()
+
+	for _, update := range g.updates {
+		if err := stream.SendMsg(update); err != nil {
+			return err
+		}
+	}
+
+	<-stream.Context().Done()
+	return nil
+}
+
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func startGeyserStandIn(t *testing.T, standIn *geyserStandIn) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "geyser.Geyser",
+		HandlerType: (*interface{})(nil),
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "Subscribe",
+			Handler:       standIn.subscribe,
+			ServerStreams: true,
+			ClientStreams: true,
+		}},
+	}, standIn)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("error dialing stand-in: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// toGeyserUpdate 将语料交易转换为 Geyser 推送格式：账户为原始字节，内部指令带 stack_height
+func toGeyserUpdate(ctx corpusTx, slot uint64, index uint64) *yellowstone.SubscribeUpdate {
+	tx := ctx.tx
+	meta := ctx.result.Meta
+
+	message := &yellowstone.Message{
+		Header: &yellowstone.MessageHeader{
+			NumRequiredSignatures:       uint32(tx.Message.Header.NumRequiredSignatures),
+			NumReadonlySignedAccounts:   uint32(tx.Message.Header.NumReadonlySignedAccounts),
+			NumReadonlyUnsignedAccounts: uint32(tx.Message.Header.NumReadonlyUnsignedAccounts),
+		},
+		RecentBlockhash: tx.Message.RecentBlockhash[:],
+		Versioned:       tx.Message.IsVersioned(),
+	}
+	for _, key := range tx.Message.AccountKeys {
+		message.AccountKeys = append(message.AccountKeys, key.Bytes())
+	}
+	for _, instruction := range tx.Message.Instructions {
+		message.Instructions = append(message.Instructions, &yellowstone.CompiledInstruction{
+			ProgramIdIndex: uint32(instruction.ProgramIDIndex),
+			Accounts:       accountIndexBytes(instruction.Accounts),
+			Data:           instruction.Data,
+		})
+	}
+
+	protoMeta := &yellowstone.TransactionStatusMeta{
+		Fee:          meta.Fee,
+		PreBalances:  meta.PreBalances,
+		PostBalances: meta.PostBalances,
+		LogMessages:  meta.LogMessages,
+	}
+	stackHeight := uint32(2)
+	for _, set := range meta.InnerInstructions {
+		inner := &yellowstone.InnerInstructions{Index: uint32(set.Index)}
+		for _, instruction := range set.Instructions {
+			inner.Instructions = append(inner.Instructions, &yellowstone.InnerInstruction{
+				ProgramIdIndex: uint32(instruction.ProgramIDIndex),
+				Accounts:       accountIndexBytes(instruction.Accounts),
+				Data:           instruction.Data,
+				StackHeight:    &stackHeight,
+			})
+		}
+		protoMeta.InnerInstructions = append(protoMeta.InnerInstructions, inner)
+	}
+	protoMeta.PreTokenBalances = protoTokenBalances(meta.PreTokenBalances)
+	protoMeta.PostTokenBalances = protoTokenBalances(meta.PostTokenBalances)
+	for _, key := range meta.LoadedAddresses.Writable {
+		protoMeta.LoadedWritableAddresses = append(protoMeta.LoadedWritableAddresses, key.Bytes())
+	}
+	for _, key := range meta.LoadedAddresses.ReadOnly {
+		protoMeta.LoadedReadonlyAddresses = append(protoMeta.LoadedReadonlyAddresses, key.Bytes())
+	}
+
+	protoTx := &yellowstone.Transaction{Message: message}
+	for _, signature := range tx.Signatures {
+		protoTx.Signatures = append(protoTx.Signatures, signature[:])
+	}
+
+	return &yellowstone.SubscribeUpdate{
+		Filters: []string{"swaps"},
+		UpdateOneof: &yellowstone.SubscribeUpdate_Transaction{
+			Transaction: &yellowstone.SubscribeUpdateTransaction{
+				Slot: slot,
+				Transaction: &yellowstone.SubscribeUpdateTransactionInfo{
+					Signature:   tx.Signatures[0][:],
+					Transaction: protoTx,
+					Meta:        protoMeta,
+					Index:       index,
+				},
+			},
+		},
+	}
+}
+
+func accountIndexBytes(accounts []uint16) []byte {
+	raw := make([]byte, len(accounts))
+	for i, account := range accounts {
+		raw[i] = byte(account)
+	}
+	return raw
+}
+
+func protoTokenBalances(balances []rpc.TokenBalance) []*yellowstone.TokenBalance {
+	result := make([]*yellowstone.TokenBalance, 0, len(balances))
+	for _, balance := range balances {
+		protoBalance := &yellowstone.TokenBalance{
+			AccountIndex: uint32(balance.AccountIndex),
+			Mint:         balance.Mint.String(),
+			UiTokenAmount: &yellowstone.UiTokenAmount{
+				Amount:         balance.UiTokenAmount.Amount,
+				Decimals:       uint32(balance.UiTokenAmount.Decimals),
+				UiAmountString: balance.UiTokenAmount.UiAmountString,
+			},
+		}
+		if balance.UiTokenAmount.UiAmount != nil {
+			protoBalance.UiTokenAmount.UiAmount = *balance.UiTokenAmount.UiAmount
+		}
+		if balance.Owner != nil {
+			protoBalance.Owner = balance.Owner.String()
+		}
+		result = append(result, protoBalance)
+	}
+	return result
+}
+
+func TestYellowstoneParseUpdate(t *testing.T) {
+	for i, ctx := range loadCorpus(t) {
+		update := toGeyserUpdate(ctx, 280000000, uint64(i))
+
+		swap, err := yellowstone.ParseUpdate(update.GetTransaction())
+		if err != nil {
+			t.Fatalf("%s: error parsing update: %s", ctx.signature, err)
+		}
+		if swap == nil || swap.SwapInfo == nil {
+			t.Fatalf("%s: 应该解析出交换", ctx.signature)
+		}
+		if swap.Slot != 280000000 || swap.TransactionIndex != i || swap.Signature.String() != ctx.signature {
+			t.Errorf("%s: slot/下标/签名不正确: %d %d %s", ctx.signature, swap.Slot, swap.TransactionIndex, swap.Signature)
+		}
+
+		expected, err := parseCorpusTx(ctx)
+		if err != nil {
+			t.Fatalf("error parsing transaction: %s", err)
+		}
+		got, want := *swap.SwapInfo, *expected
+		got.Timestamp, want.Timestamp = time.Time{}, time.Time{}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Geyser 输入的解析结果不一致\n got: %+v\nwant: %+v", ctx.signature, got, want)
+		}
+	}
+}
+
+func TestYellowstoneSubscriber(t *testing.T) {
+	corpus := loadCorpus(t)
+
+	standIn := &geyserStandIn{
+		requests: make(chan *yellowstone.SubscribeRequest, 16),
+		tokens:   make(chan string, 1),
+	}
+	standIn.updates = append(standIn.updates, &yellowstone.SubscribeUpdate{
+		UpdateOneof: &yellowstone.SubscribeUpdate_Ping{Ping: &yellowstone.SubscribeUpdatePing{}},
+	})
+	for i, ctx := range corpus {
+		standIn.updates = append(standIn.updates, toGeyserUpdate(ctx, 280000000+uint64(i), 0))
+	}
+	// 投票交易应被跳过
+	vote := toGeyserUpdate(corpus[0], 1, 0)
+	vote.GetTransaction().GetTransaction().IsVote = true
+	standIn.updates = append(standIn.updates, vote)
+
+	conn := startGeyserStandIn(t, standIn)
+	subscriber := yellowstone.NewSwapSubscriber(conn)
+	subscriber.Token = "secret"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// 不带缓冲的 channel：订阅器只有在消费端读取后才继续接收
+	out := make(chan solanaswapgo.BlockSwap)
+	done := make(chan error, 1)
+	go func() {
+		done <- subscriber.Subscribe(ctx, yellowstone.TransactionRequest(yellowstone.CommitmentLevel_CONFIRMED, solanaswapgo.PUMP_FUN_PROGRAM_ID), out)
+	}()
+
+	for i := range corpus {
+		select {
+		case swap := <-out:
+			if swap.Slot != 280000000+uint64(i) {
+				t.Errorf("第 %d 个交换的 slot 应为 %d，实际 %d", i, 280000000+i, swap.Slot)
+			}
+			if swap.Signature.String() != corpus[i].signature {
+				t.Errorf("第 %d 个交换的签名不正确: %s", i, swap.Signature)
+			}
+		case <-ctx.Done():
+			t.Fatalf("等待第 %d 个交换超时", i)
+		}
+	}
+
+	if token := <-standIn.tokens; token != "secret" {
+		t.Errorf("x-token 应为 secret，实际 %q", token)
+	}
+
+	subscribe := <-standIn.requests
+	filter := subscribe.GetTransactions()["swaps"]
+	if filter == nil || len(filter.GetAccountInclude()) != 1 || filter.GetAccountInclude()[0] != solanaswapgo.PUMP_FUN_PROGRAM_ID.String() || filter.GetVote() {
+		t.Errorf("订阅请求不正确: %v", subscribe)
+	}
+	if ping := <-standIn.requests; ping.GetPing() == nil {
+		t.Errorf("收到 ping 后应回复 ping 请求: %v", ping)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled && err != context.DeadlineExceeded {
+		t.Errorf("取消后应返回 context 错误，实际 %v", err)
+	}
+
+	select {
+	case swap := <-out:
+		t.Errorf("投票交易不应输出: %s", swap.Signature)
+	default:
+	}
+}
@@ -0,0 +1,237 @@
+// Package yellowstone 将 Yellowstone gRPC (Geyser) 的交易推送转换为 dex-parse 的解析器输入。
+package yellowstone
+
+//go:generate protoc --go_out=.. --go_opt=paths=source_relative --proto_path=.. yellowstone/geyser.proto
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"google.golang.org/protobuf/proto"
+)
+
+// FromProto 将任意线路兼容的 SubscribeUpdateTransaction 消息（例如官方 yellowstone-grpc
+// 生成的类型）转换为本包的类型，调用方无需依赖官方生成代码的版本
+func FromProto(msg proto.Message) (*SubscribeUpdateTransaction, error) {
+	if update, ok := msg.(*SubscribeUpdateTransaction); ok {
+		return update, nil
+	}
+
+	raw, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling update: %s", err)
+	}
+
+	var update SubscribeUpdateTransaction
+	if err := proto.Unmarshal(raw, &update); err != nil {
+		return nil, fmt.Errorf("error unmarshaling update: %s", err)
+	}
+	return &update, nil
+}
+
+// NewParser 根据交易推送构建解析器，slot 会保留在解析器的交易结果中
+func NewParser(update *SubscribeUpdateTransaction) (*solanaswapgo.Parser, error) {
+	info := update.GetTransaction()
+	if info == nil {
+		return nil, fmt.Errorf("update has no transaction")
+	}
+
+	tx, meta, err := ConvertTransaction(info)
+	if err != nil {
+		return nil, err
+	}
+
+	txResult := &rpc.GetTransactionResult{
+		Slot: update.GetSlot(),
+		Meta: meta,
+	}
+	if info.GetTransaction().GetMessage().GetVersioned() {
+		txResult.Version = 0
+	} else {
+		txResult.Version = rpc.LegacyTransactionVersion
+	}
+
+	return solanaswapgo.NewTransactionParserFromTransactionResult(txResult, tx, meta)
+}
+
+// ConvertTransaction 将 Geyser 的交易与 meta 转换为 solana-go 的类型。
+// 账户以原始字节给出，内部指令的 stack_height 在 CompiledInstruction 中没有对应字段而被丢弃，
+// loaded_writable/readonly_addresses 转换为 meta.LoadedAddresses。
+func ConvertTransaction(info *SubscribeUpdateTransactionInfo) (*solana.Transaction, *rpc.TransactionMeta, error) {
+	tx, err := convertTransaction(info.GetTransaction())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta, err := convertMeta(info.GetMeta())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tx, meta, nil
+}
+
+func convertTransaction(protoTx *Transaction) (*solana.Transaction, error) {
+	if protoTx == nil || protoTx.GetMessage() == nil {
+		return nil, fmt.Errorf("transaction is missing")
+	}
+
+	tx := &solana.Transaction{
+		Signatures: make([]solana.Signature, 0, len(protoTx.GetSignatures())),
+	}
+	for _, raw := range protoTx.GetSignatures() {
+		if len(raw) != solana.SignatureLength {
+			return nil, fmt.Errorf("invalid signature length: %d", len(raw))
+		}
+		tx.Signatures = append(tx.Signatures, solana.SignatureFromBytes(raw))
+	}
+
+	message := protoTx.GetMessage()
+	header := message.GetHeader()
+	tx.Message.Header = solana.MessageHeader{
+		NumRequiredSignatures:       uint8(header.GetNumRequiredSignatures()),
+		NumReadonlySignedAccounts:   uint8(header.GetNumReadonlySignedAccounts()),
+		NumReadonlyUnsignedAccounts: uint8(header.GetNumReadonlyUnsignedAccounts()),
+	}
+
+	accountKeys, err := publicKeys(message.GetAccountKeys())
+	if err != nil {
+		return nil, fmt.Errorf("error converting account keys: %s", err)
+	}
+	tx.Message.AccountKeys = accountKeys
+
+	if len(message.GetRecentBlockhash()) == 32 {
+		tx.Message.RecentBlockhash = solana.HashFromBytes(message.GetRecentBlockhash())
+	}
+
+	tx.Message.Instructions = make([]solana.CompiledInstruction, 0, len(message.GetInstructions()))
+	for _, instruction := range message.GetInstructions() {
+		tx.Message.Instructions = append(tx.Message.Instructions, compiledInstruction(instruction.GetProgramIdIndex(), instruction.GetAccounts(), instruction.GetData()))
+	}
+
+	if message.GetVersioned() {
+		tx.Message.SetVersion(solana.MessageVersionV0)
+	}
+	for _, lookup := range message.GetAddressTableLookups() {
+		if len(lookup.GetAccountKey()) != solana.PublicKeyLength {
+			return nil, fmt.Errorf("invalid lookup table key length: %d", len(lookup.GetAccountKey()))
+		}
+		tx.Message.AddressTableLookups = append(tx.Message.AddressTableLookups, solana.MessageAddressTableLookup{
+			AccountKey:      solana.PublicKeyFromBytes(lookup.GetAccountKey()),
+			WritableIndexes: solana.Uint8SliceAsNum(lookup.GetWritableIndexes()),
+			ReadonlyIndexes: solana.Uint8SliceAsNum(lookup.GetReadonlyIndexes()),
+		})
+	}
+
+	return tx, nil
+}
+
+func convertMeta(protoMeta *TransactionStatusMeta) (*rpc.TransactionMeta, error) {
+	if protoMeta == nil {
+		return nil, fmt.Errorf("transaction meta is missing")
+	}
+
+	meta := &rpc.TransactionMeta{
+		Fee:          protoMeta.GetFee(),
+		PreBalances:  protoMeta.GetPreBalances(),
+		PostBalances: protoMeta.GetPostBalances(),
+		LogMessages:  protoMeta.GetLogMessages(),
+	}
+	if protoMeta.GetErr() != nil {
+		// Geyser 中的错误是 bincode 编码的 TransactionError，这里只保留原始字节用于判断失败
+		meta.Err = protoMeta.GetErr().GetErr()
+	}
+	if protoMeta.ComputeUnitsConsumed != nil {
+		computeUnits := protoMeta.GetComputeUnitsConsumed()
+		meta.ComputeUnitsConsumed = &computeUnits
+	}
+
+	meta.InnerInstructions = make([]rpc.InnerInstruction, 0, len(protoMeta.GetInnerInstructions()))
+	for _, set := range protoMeta.GetInnerInstructions() {
+		inner := rpc.InnerInstruction{
+			Index:        uint16(set.GetIndex()),
+			Instructions: make([]solana.CompiledInstruction, 0, len(set.GetInstructions())),
+		}
+		for _, instruction := range set.GetInstructions() {
+			inner.Instructions = append(inner.Instructions, compiledInstruction(instruction.GetProgramIdIndex(), instruction.GetAccounts(), instruction.GetData()))
+		}
+		meta.InnerInstructions = append(meta.InnerInstructions, inner)
+	}
+
+	var err error
+	if meta.PreTokenBalances, err = tokenBalances(protoMeta.GetPreTokenBalances()); err != nil {
+		return nil, fmt.Errorf("error converting pre token balances: %s", err)
+	}
+	if meta.PostTokenBalances, err = tokenBalances(protoMeta.GetPostTokenBalances()); err != nil {
+		return nil, fmt.Errorf("error converting post token balances: %s", err)
+	}
+
+	if meta.LoadedAddresses.Writable, err = publicKeys(protoMeta.GetLoadedWritableAddresses()); err != nil {
+		return nil, fmt.Errorf("error converting loaded writable addresses: %s", err)
+	}
+	if meta.LoadedAddresses.ReadOnly, err = publicKeys(protoMeta.GetLoadedReadonlyAddresses()); err != nil {
+		return nil, fmt.Errorf("error converting loaded readonly addresses: %s", err)
+	}
+
+	return meta, nil
+}
+
+// compiledInstruction 转换指令，Geyser 中账户下标以字节串给出
+func compiledInstruction(programIDIndex uint32, accounts []byte, data []byte) solana.CompiledInstruction {
+	instruction := solana.CompiledInstruction{
+		ProgramIDIndex: uint16(programIDIndex),
+		Accounts:       make([]uint16, len(accounts)),
+		Data:           data,
+	}
+	for i, account := range accounts {
+		instruction.Accounts[i] = uint16(account)
+	}
+	return instruction
+}
+
+func publicKeys(raw [][]byte) (solana.PublicKeySlice, error) {
+	keys := make(solana.PublicKeySlice, 0, len(raw))
+	for _, key := range raw {
+		if len(key) != solana.PublicKeyLength {
+			return nil, fmt.Errorf("invalid public key length: %d", len(key))
+		}
+		keys = append(keys, solana.PublicKeyFromBytes(key))
+	}
+	return keys, nil
+}
+
+func tokenBalances(balances []*TokenBalance) ([]rpc.TokenBalance, error) {
+	result := make([]rpc.TokenBalance, 0, len(balances))
+	for _, balance := range balances {
+		mint, err := solana.PublicKeyFromBase58(balance.GetMint())
+		if err != nil {
+			return nil, fmt.Errorf("invalid mint %q: %s", balance.GetMint(), err)
+		}
+
+		converted := rpc.TokenBalance{
+			AccountIndex: uint16(balance.GetAccountIndex()),
+			Mint:         mint,
+			UiTokenAmount: &rpc.UiTokenAmount{
+				Amount:         balance.GetUiTokenAmount().GetAmount(),
+				Decimals:       uint8(balance.GetUiTokenAmount().GetDecimals()),
+				UiAmountString: balance.GetUiTokenAmount().GetUiAmountString(),
+			},
+		}
+		if balance.GetUiTokenAmount() != nil {
+			uiAmount := balance.GetUiTokenAmount().GetUiAmount()
+			converted.UiTokenAmount.UiAmount = &uiAmount
+		}
+		if balance.GetOwner() != "" {
+			owner, err := solana.PublicKeyFromBase58(balance.GetOwner())
+			if err != nil {
+				return nil, fmt.Errorf("invalid owner %q: %s", balance.GetOwner(), err)
+			}
+			converted.Owner = &owner
+		}
+
+		result = append(result, converted)
+	}
+	return result, nil
+}
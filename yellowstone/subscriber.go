@@ -0,0 +1,155 @@
+package yellowstone
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/sirupsen/logrus"
+	solanaswapgo "github.com/zzispp/solana-dex-parse/dex-parse"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// SubscribeMethod 是 Yellowstone Geyser 服务的双向流订阅方法
+const SubscribeMethod = "/geyser.Geyser/Subscribe"
+
+var subscribeStreamDesc = grpc.StreamDesc{
+	StreamName:    "Subscribe",
+	ServerStreams: true,
+	ClientStreams: true,
+}
+
+// SwapSubscriber 订阅 Geyser 交易推送并输出解析出的交换
+type SwapSubscriber struct {
+	conn grpc.ClientConnInterface
+
+	// Token 作为 x-token 请求头发送，为空时不发送
+	Token string
+	Log   *logrus.Logger
+}
+
+// NewSwapSubscriber 基于已建立的 gRPC 连接创建订阅器
+func NewSwapSubscriber(conn grpc.ClientConnInterface) *SwapSubscriber {
+	log := logrus.New()
+	log.SetFormatter(&logrus.TextFormatter{
+		TimestampFormat: "2006-01-02 15:04:05",
+		FullTimestamp:   true,
+	})
+
+	return &SwapSubscriber{
+		conn: conn,
+		Log:  log,
+	}
+}
+
+// TransactionRequest 构建订阅涉及指定程序的成功、非投票交易的请求
+func TransactionRequest(commitment CommitmentLevel, programIDs ...solana.PublicKey) *SubscribeRequest {
+	accounts := make([]string, 0, len(programIDs))
+	for _, programID := range programIDs {
+		accounts = append(accounts, programID.String())
+	}
+
+	vote, failed := false, false
+	return &SubscribeRequest{
+		Transactions: map[string]*SubscribeRequestFilterTransactions{
+			"swaps": {
+				Vote:           &vote,
+				Failed:         &failed,
+				AccountInclude: accounts,
+			},
+		},
+		Commitment: &commitment,
+	}
+}
+
+// Subscribe 发送订阅请求，并将每笔包含交换的交易写入 out，直到 ctx 取消或流出错。
+// out 写满时停止读取流，由 gRPC 流控对服务端形成背压。
+func (s *SwapSubscriber) Subscribe(ctx context.Context, req *SubscribeRequest, out chan<- solanaswapgo.BlockSwap) error {
+	if s.Token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-token", s.Token)
+	}
+
+	stream, err := s.conn.NewStream(ctx, &subscribeStreamDesc, SubscribeMethod)
+	if err != nil {
+		return fmt.Errorf("error opening subscribe stream: %s", err)
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return fmt.Errorf("error sending subscribe request: %s", err)
+	}
+
+	for {
+		var update SubscribeUpdate
+		if err := stream.RecvMsg(&update); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err == io.EOF {
+				return fmt.Errorf("subscribe stream closed by server")
+			}
+			return fmt.Errorf("error receiving update: %s", err)
+		}
+
+		switch {
+		case update.GetPing() != nil:
+			// 部分负载均衡器会断开没有上行流量的连接，收到 ping 时回复一次
+			if err := stream.SendMsg(&SubscribeRequest{Ping: &SubscribeRequestPing{Id: 1}}); err != nil {
+				return fmt.Errorf("error sending ping: %s", err)
+			}
+
+		case update.GetTransaction() != nil:
+			swap, err := ParseUpdate(update.GetTransaction())
+			if err != nil {
+				s.Log.Errorf("error parsing transaction update: %s", err)
+				continue
+			}
+			if swap == nil {
+				continue
+			}
+
+			select {
+			case out <- *swap:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// ParseUpdate 解析一条交易推送；失败交易、投票交易和不含交换的交易返回 nil, nil
+func ParseUpdate(update *SubscribeUpdateTransaction) (*solanaswapgo.BlockSwap, error) {
+	info := update.GetTransaction()
+	if info == nil || info.GetIsVote() || info.GetMeta().GetErr() != nil {
+		return nil, nil
+	}
+
+	parser, err := NewParser(update)
+	if err != nil {
+		return nil, err
+	}
+	parser.Log = solanaswapgo.DiscardLogger
+
+	swaps, err := parser.ParseTransaction()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing transaction: %s", err)
+	}
+	if len(swaps) == 0 {
+		return nil, nil
+	}
+
+	result := &solanaswapgo.BlockSwap{
+		Slot:             update.GetSlot(),
+		TransactionIndex: int(info.GetIndex()),
+		Fee:              info.GetMeta().GetFee(),
+		Swaps:            swaps,
+	}
+	if len(info.GetSignature()) == solana.SignatureLength {
+		result.Signature = solana.SignatureFromBytes(info.GetSignature())
+	}
+	if swapInfo, err := parser.ProcessSwapData(swaps); err == nil {
+		result.SwapInfo = swapInfo
+	}
+
+	return result, nil
+}